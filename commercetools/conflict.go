@@ -0,0 +1,47 @@
+package commercetools
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+// The strategies for updates which fail because the resource was changed by
+// someone else, configured with conflict_strategy of the provider
+const (
+	conflictStrategyRetry = "retry"
+	conflictStrategyFail  = "fail"
+)
+
+var conflictStrategies = []string{conflictStrategyRetry, conflictStrategyFail}
+
+// retryOnConflict returns whether an update which conflicts with a concurrent
+// change is sent again with the current version of the resource. This
+// overwrites the changes made outside of terraform, with the fail strategy the
+// update fails instead.
+func retryOnConflict(m interface{}) bool {
+	meta, ok := m.(*providerMeta)
+	return !ok || meta.conflictStrategy != conflictStrategyFail
+}
+
+// isConflictError returns whether the request failed because the version of
+// the request is not the current version of the resource
+func isConflictError(err error) bool {
+	var ctErr platform.ErrorResponse
+	return errors.As(err, &ctErr) && ctErr.StatusCode == 409
+}
+
+// conflictDiagnostics explains a failed update because the resource was
+// changed since terraform read it, when the provider doesn't retry these
+func conflictDiagnostics(resourceType string, id string, err error) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("The %s %s was changed outside of terraform", resourceType, id),
+		Detail: fmt.Sprintf(
+			"%s\n\nThe %s was changed after terraform read it, so the update is not applied. Run terraform "+
+				"plan again to review the changes, or set conflict_strategy of the provider to %s to apply the "+
+				"update on top of them.", err, resourceType, conflictStrategyRetry),
+	}}
+}
@@ -0,0 +1,80 @@
+package commercetools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+// newConflictServer returns a server for discount code code-1, which is at
+// version 5 while terraform read version 3. Updates with another version
+// are rejected with a 409.
+func newConflictServer(t *testing.T, versions *[]float64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			*versions = append(*versions, body["version"].(float64))
+			if body["version"] != 5.0 {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"statusCode": 409, "message": "Object code-1 has a different version than expected. Expected: 3 - Actual: 5."}`))
+				return
+			}
+			w.Write([]byte(`{"id": "code-1", "version": 6, "code": "SUMMER"}`))
+			return
+		}
+		w.Write([]byte(`{"id": "code-1", "version": 5, "code": "SUMMER"}`))
+	}))
+}
+
+func TestResourceDiscountCodeUpdateConflictStrategy(t *testing.T) {
+	var versions []float64
+	server := newConflictServer(t, &versions)
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"code":      "SUMMER",
+		"predicate": "1=1",
+	})
+	d.SetId("code-1")
+	d.Set("version", 3)
+
+	meta := &providerMeta{client: client.WithProjectKey("my-project"), conflictStrategy: conflictStrategyFail}
+	diags := resourceDiscountCodeUpdate(context.Background(), d, meta)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "The discount code code-1 was changed outside of terraform", diags[0].Summary)
+		assert.Contains(t, diags[0].Detail, "set conflict_strategy of the provider to retry")
+	}
+	assert.Equal(t, []float64{3}, versions)
+
+	// The default strategy updates the current version
+	versions = nil
+	meta.conflictStrategy = ""
+	diags = resourceDiscountCodeUpdate(context.Background(), d, meta)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, []float64{5}, versions)
+}
+
+func TestRetryOnConflict(t *testing.T) {
+	assert.True(t, retryOnConflict(&providerMeta{}))
+	assert.True(t, retryOnConflict(&providerMeta{conflictStrategy: conflictStrategyRetry}))
+	assert.False(t, retryOnConflict(&providerMeta{conflictStrategy: conflictStrategyFail}))
+}
+
+func TestIsConflictError(t *testing.T) {
+	assert.True(t, isConflictError(platform.ErrorResponse{StatusCode: 409}))
+	assert.False(t, isConflictError(platform.ErrorResponse{StatusCode: 400}))
+}
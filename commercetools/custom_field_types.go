@@ -0,0 +1,222 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+// validateCustomFieldTypes returns a CustomizeDiff function which checks the
+// custom fields against the field definitions of their type when the resource
+// has validate_custom_fields enabled.
+func validateCustomFieldTypes() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if !d.Get("validate_custom_fields").(bool) {
+			return nil
+		}
+		changed := d.HasChange("custom") || d.HasChange("validate_custom_fields")
+		if !changed || !d.NewValueKnown("custom") {
+			return nil
+		}
+		typeID, _ := d.Get("custom.0.type_id").(string)
+		if typeID == "" {
+			return nil
+		}
+
+		customType, err := getClient(m).Types().WithId(typeID).Get().Execute(ctx)
+		if err != nil {
+			if isNotFoundError(err) {
+				return fmt.Errorf("custom.0.type_id: type %s does not exist", typeID)
+			}
+			return err
+		}
+		fields, _ := d.Get("custom.0.fields").(map[string]interface{})
+		return checkCustomFieldTypes(customType, fields)
+	}
+}
+
+// validateCustomFieldsSchema returns the schema of the attribute to enable
+// the custom field type validation.
+func validateCustomFieldsSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Check when planning that the custom fields are defined by the type of the `custom` block " +
+			"and that their values match the type of the field definition. This requires an API call to " +
+			"fetch the type, so it is disabled by default",
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+}
+
+// checkCustomFieldTypes returns an error listing the fields which are not
+// defined by the type or have a value which doesn't match their definition
+func checkCustomFieldTypes(customType *platform.Type, fields map[string]interface{}) error {
+	definitions := make(map[string]platform.FieldDefinition, len(customType.FieldDefinitions))
+	for _, definition := range customType.FieldDefinitions {
+		definitions[definition.Name] = definition
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	problems := []string{}
+	for _, name := range names {
+		definition, ok := definitions[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf(
+				"custom.0.fields.%s: the type %s has no field definition with this name", name, customTypeName(customType)))
+			continue
+		}
+		raw, _ := fields[name].(string)
+		if err := checkCustomFieldValue(definition.Type, unmarshallCustomFieldValue(raw)); err != nil {
+			problems = append(problems, fmt.Sprintf("custom.0.fields.%s: %s", name, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("the custom fields don't match their type:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+func customTypeName(customType *platform.Type) string {
+	if customType.Key != "" {
+		return customType.Key
+	}
+	return customType.ID
+}
+
+// checkCustomFieldValue checks a decoded custom field value against the type
+// of its field definition. Types which are not known are not checked.
+func checkCustomFieldValue(fieldType platform.FieldType, value interface{}) error {
+	switch t := fieldType.(type) {
+	case platform.CustomFieldBooleanType:
+		if _, ok := value.(bool); !ok {
+			return customFieldTypeError("a Boolean", value)
+		}
+	case platform.CustomFieldNumberType:
+		if _, ok := value.(float64); !ok {
+			return customFieldTypeError("a Number", value)
+		}
+	case platform.CustomFieldStringType:
+		if _, ok := value.(string); !ok {
+			return customFieldStringError("a String", value)
+		}
+	case platform.CustomFieldLocalizedStringType:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return customFieldTypeError("a LocalizedString object", value)
+		}
+		for locale, text := range object {
+			if _, ok := text.(string); !ok {
+				return fmt.Errorf("expected a string for locale %s, got %s", locale, customFieldValueKind(text))
+			}
+		}
+	case platform.CustomFieldEnumType:
+		keys := make([]string, len(t.Values))
+		for i, item := range t.Values {
+			keys[i] = item.Key
+		}
+		return checkCustomFieldEnumValue(keys, value)
+	case platform.CustomFieldLocalizedEnumType:
+		keys := make([]string, len(t.Values))
+		for i, item := range t.Values {
+			keys[i] = item.Key
+		}
+		return checkCustomFieldEnumValue(keys, value)
+	case platform.CustomFieldMoneyType:
+		if _, ok := customFieldMoney(value); !ok {
+			return customFieldTypeError("a Money object", value)
+		}
+	case platform.CustomFieldDateType:
+		return checkCustomFieldTimeValue("a Date", "2006-01-02", value)
+	case platform.CustomFieldDateTimeType:
+		return checkCustomFieldTimeValue("a DateTime", time.RFC3339, value)
+	case platform.CustomFieldTimeType:
+		return checkCustomFieldTimeValue("a Time", "15:04:05", value)
+	case platform.CustomFieldReferenceType:
+		object, ok := value.(map[string]interface{})
+		if _, hasID := object["id"]; !ok || !hasID {
+			return customFieldTypeError("a Reference object with an id", value)
+		}
+	case platform.CustomFieldSetType:
+		items, ok := value.([]interface{})
+		if !ok {
+			return customFieldTypeError("a Set, encoded as a JSON list", value)
+		}
+		for i, item := range items {
+			if err := checkCustomFieldValue(t.ElementType, item); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func checkCustomFieldEnumValue(keys []string, value interface{}) error {
+	key, ok := value.(string)
+	if !ok {
+		return customFieldStringError("the key of an enum value", value)
+	}
+	for _, item := range keys {
+		if item == key {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not one of the enum values %s", key, strings.Join(keys, ", "))
+}
+
+func checkCustomFieldTimeValue(expected string, layout string, value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return customFieldStringError(expected, value)
+	}
+	// Parsing accepts fractional seconds after the seconds of the layout
+	if _, err := time.Parse(layout, text); err != nil {
+		return fmt.Errorf("expected %s, %q does not match the format %s", expected, text, layout)
+	}
+	return nil
+}
+
+func customFieldTypeError(expected string, value interface{}) error {
+	return fmt.Errorf("expected %s, got %s", expected, customFieldValueKind(value))
+}
+
+// customFieldStringError is the error for a field which expects a string.
+// Values like "42" or "true" are decoded as JSON, so the hint explains how
+// to pass them as a string.
+func customFieldStringError(expected string, value interface{}) error {
+	switch value.(type) {
+	case bool, float64, nil:
+		return fmt.Errorf("%w, use jsonencode() to pass a string which is valid JSON",
+			customFieldTypeError(expected, value))
+	}
+	return customFieldTypeError(expected, value)
+}
+
+// customFieldValueKind returns the JSON kind of a decoded value
+func customFieldValueKind(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
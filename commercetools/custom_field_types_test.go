@@ -0,0 +1,125 @@
+package commercetools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCustomFieldValue(t *testing.T) {
+	sizes := platform.CustomFieldEnumType{Values: []platform.CustomFieldEnumValue{{Key: "s"}, {Key: "m"}}}
+	cases := []struct {
+		fieldType platform.FieldType
+		value     string
+		err       string
+	}{
+		{platform.CustomFieldBooleanType{}, "true", ""},
+		{platform.CustomFieldBooleanType{}, "yes", "expected a Boolean, got a string"},
+		{platform.CustomFieldNumberType{}, "10", ""},
+		{platform.CustomFieldNumberType{}, "ten", "expected a Number, got a string"},
+		{platform.CustomFieldStringType{}, "summer", ""},
+		{platform.CustomFieldStringType{}, `"2021"`, ""},
+		{platform.CustomFieldStringType{}, "2021",
+			"expected a String, got a number, use jsonencode() to pass a string which is valid JSON"},
+		{platform.CustomFieldLocalizedStringType{}, `{"en": "Summer"}`, ""},
+		{platform.CustomFieldLocalizedStringType{}, "Summer", "expected a LocalizedString object, got a string"},
+		{sizes, "m", ""},
+		{sizes, "xl", `"xl" is not one of the enum values s, m`},
+		{platform.CustomFieldMoneyType{}, `{"currencyCode": "EUR", "centAmount": 100}`, ""},
+		{platform.CustomFieldMoneyType{}, "100", "expected a Money object, got a number"},
+		{platform.CustomFieldDateType{}, "2021-08-01", ""},
+		{platform.CustomFieldDateType{}, "01-08-2021", `expected a Date, "01-08-2021" does not match the format 2006-01-02`},
+		{platform.CustomFieldDateTimeType{}, "2021-08-01T10:00:00.000Z", ""},
+		{platform.CustomFieldTimeType{}, "10:00:00.000", ""},
+		{platform.CustomFieldReferenceType{}, `{"typeId": "category", "id": "category-1"}`, ""},
+		{platform.CustomFieldReferenceType{}, "category-1", "expected a Reference object with an id, got a string"},
+		{platform.CustomFieldSetType{ElementType: platform.CustomFieldNumberType{}}, "[1, 2]", ""},
+		{platform.CustomFieldSetType{ElementType: platform.CustomFieldNumberType{}}, `[1, "two"]`,
+			"item 1: expected a Number, got a string"},
+		{platform.CustomFieldSetType{ElementType: sizes}, "s", "expected a Set, encoded as a JSON list, got a string"},
+	}
+
+	for _, c := range cases {
+		err := checkCustomFieldValue(c.fieldType, unmarshallCustomFieldValue(c.value))
+		if c.err == "" {
+			assert.NoError(t, err, c.value)
+		} else {
+			assert.EqualError(t, err, c.err, c.value)
+		}
+	}
+}
+
+func TestCheckCustomFieldTypes(t *testing.T) {
+	customType := &platform.Type{
+		ID:  "type-1",
+		Key: "discount-code-fields",
+		FieldDefinitions: []platform.FieldDefinition{
+			{Name: "campaign", Type: platform.CustomFieldStringType{}},
+			{Name: "budget", Type: platform.CustomFieldNumberType{}},
+		},
+	}
+
+	assert.NoError(t, checkCustomFieldTypes(customType, map[string]interface{}{"campaign": "summer", "budget": "1000"}))
+	assert.EqualError(t,
+		checkCustomFieldTypes(customType, map[string]interface{}{"campaign": "summer", "budget": "a lot", "owner": "me"}),
+		"the custom fields don't match their type:\n"+
+			"custom.0.fields.budget: expected a Number, got a string\n"+
+			"custom.0.fields.owner: the type discount-code-fields has no field definition with this name")
+}
+
+func TestValidateCustomFieldTypes(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "type-1",
+			"key": "discount-code-fields",
+			"fieldDefinitions": [{"name": "budget", "type": {"name": "Number"}}]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	state := &terraform.InstanceState{
+		ID: "code-1",
+		Attributes: map[string]string{
+			"id":                          "code-1",
+			"code":                        "SUMMER",
+			"is_active":                   "true",
+			"wait_for_create_consistency": "true",
+		},
+	}
+	diff := func(validate bool) error {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"code": "SUMMER",
+			"custom": []interface{}{map[string]interface{}{
+				"type_id": "type-1",
+				"fields":  map[string]interface{}{"budget": "a lot"},
+			}},
+			"validate_custom_fields": validate,
+		})
+		_, err := resourceDiscountCode().SimpleDiff(context.Background(), state, config, meta)
+		return err
+	}
+
+	assert.NoError(t, diff(false))
+	assert.Equal(t, 0, requests)
+
+	err = diff(true)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "custom.0.fields.budget: expected a Number, got a string")
+	}
+	assert.Equal(t, 1, requests)
+}
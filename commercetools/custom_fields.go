@@ -0,0 +1,220 @@
+package commercetools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+// customFieldSchema returns the schema for the `custom` block which can be
+// used on every resource which supports custom fields.
+func customFieldSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "[Custom Fields](https://docs.commercetools.com/api/projects/custom-fields) for this resource",
+		Type:        schema.TypeList,
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type_id": {
+					Description: "The ID of the [Type](https://docs.commercetools.com/api/projects/types) holding the field definitions",
+					Type:        schema.TypeString,
+					Required:    true,
+				},
+				"fields": {
+					Description: "Map of the custom field values. Values are decoded as JSON when possible, " +
+						"so use `jsonencode()` for strings which would otherwise be valid JSON (for example numbers). " +
+						"Objects with a `currencyCode` or `centAmount` are validated as " +
+						"[Money](https://docs.commercetools.com/api/types#money) values",
+					Type:             schema.TypeMap,
+					Optional:         true,
+					Elem:             &schema.Schema{Type: schema.TypeString},
+					ValidateFunc:     validateCustomFields,
+					DiffSuppressFunc: diffSuppressCustomFieldValue,
+				},
+			},
+		},
+	}
+}
+
+// customFieldComputedSchema returns the read-only variant of the `custom`
+// block for data sources
+func customFieldComputedSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "[Custom Fields](https://docs.commercetools.com/api/projects/custom-fields) of this resource",
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type_id": {
+					Description: "The ID of the [Type](https://docs.commercetools.com/api/projects/types) holding the field definitions",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"fields": {
+					Description: "Map of the custom field values, encoded as JSON unless the value is a plain string",
+					Type:        schema.TypeMap,
+					Computed:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+func unmarshallCustomFieldsDraft(d *schema.ResourceData) (*platform.CustomFieldsDraft, error) {
+	input, err := elementFromList(d, "custom")
+	if err != nil || input == nil {
+		return nil, err
+	}
+
+	typeID := input["type_id"].(string)
+	fields := unmarshallCustomFieldContainer(input["fields"])
+	return &platform.CustomFieldsDraft{
+		Type:   platform.TypeResourceIdentifier{ID: &typeID},
+		Fields: &fields,
+	}, nil
+}
+
+func unmarshallCustomFieldContainer(val interface{}) platform.FieldContainer {
+	values, ok := val.(map[string]interface{})
+	if !ok {
+		return platform.FieldContainer{}
+	}
+
+	result := make(platform.FieldContainer, len(values))
+	for k, v := range values {
+		result[k] = unmarshallCustomFieldValue(v.(string))
+	}
+	return result
+}
+
+func unmarshallCustomFieldValue(value string) interface{} {
+	var result interface{}
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		return value
+	}
+	if money, ok := customFieldMoney(result); ok {
+		return normalizeCustomFieldMoney(money)
+	}
+	return result
+}
+
+// customFieldMoney returns the value as a Money object. Custom field values
+// are not typed, so every object with a currencyCode or centAmount is
+// considered to be a Money value.
+func customFieldMoney(value interface{}) (map[string]interface{}, bool) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	_, hasCurrency := object["currencyCode"]
+	_, hasAmount := object["centAmount"]
+	return object, hasCurrency || hasAmount
+}
+
+// validateCustomFields checks the Money values of the custom fields, so
+// malformed values are reported when planning instead of by the API
+func validateCustomFields(val interface{}, key string) (warns []string, errs []error) {
+	values, ok := val.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for name, raw := range values {
+		value, ok := raw.(string)
+		if !ok {
+			continue
+		}
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			continue
+		}
+		if money, ok := customFieldMoney(decoded); ok {
+			if err := validateCustomFieldMoney(money); err != nil {
+				errs = append(errs, fmt.Errorf("%s.%s: %w", key, name, err))
+			}
+		}
+	}
+	return
+}
+
+func validateCustomFieldMoney(money map[string]interface{}) error {
+	currencyCode, ok := money["currencyCode"].(string)
+	if !ok || !currencyCodes[strings.ToUpper(currencyCode)] {
+		return fmt.Errorf("currencyCode %v is not a valid ISO 4217 currency code", money["currencyCode"])
+	}
+	centAmount, ok := money["centAmount"].(float64)
+	if !ok || centAmount < 0 || centAmount != math.Trunc(centAmount) {
+		return fmt.Errorf("centAmount %v is not a non-negative amount in cents", money["centAmount"])
+	}
+	return nil
+}
+
+// normalizeCustomFieldMoney returns the Money value with an upper case
+// currency code and the type set
+func normalizeCustomFieldMoney(money map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(money)+1)
+	for k, v := range money {
+		result[k] = v
+	}
+	if currencyCode, ok := result["currencyCode"].(string); ok {
+		result["currencyCode"] = strings.ToUpper(currencyCode)
+	}
+	if _, ok := result["type"]; !ok {
+		result["type"] = "centPrecision"
+	}
+	return result
+}
+
+// diffSuppressCustomFieldValue suppresses the diff between two Money values
+// with the same currency and amount. commercetools returns the type and
+// fraction digits of a Money value, which are usually not configured.
+func diffSuppressCustomFieldValue(k, old, new string, d *schema.ResourceData) bool {
+	var oldValue, newValue interface{}
+	if json.Unmarshal([]byte(old), &oldValue) != nil || json.Unmarshal([]byte(new), &newValue) != nil {
+		return false
+	}
+	oldMoney, ok := customFieldMoney(oldValue)
+	if !ok {
+		return false
+	}
+	newMoney, ok := customFieldMoney(newValue)
+	if !ok {
+		return false
+	}
+	oldMoney, newMoney = normalizeCustomFieldMoney(oldMoney), normalizeCustomFieldMoney(newMoney)
+	return oldMoney["currencyCode"] == newMoney["currencyCode"] && oldMoney["centAmount"] == newMoney["centAmount"]
+}
+
+func marshallCustomFields(val *platform.CustomFields) []map[string]interface{} {
+	if val == nil {
+		return []map[string]interface{}{}
+	}
+
+	fields := make(map[string]interface{}, len(val.Fields))
+	for k, v := range val.Fields {
+		fields[k] = marshallCustomFieldValue(v)
+	}
+
+	return []map[string]interface{}{{
+		"type_id": val.Type.ID,
+		"fields":  fields,
+	}}
+}
+
+func marshallCustomFieldValue(value interface{}) string {
+	// Strings which are valid JSON themselves need to be quoted, otherwise
+	// they would be decoded into a different type again
+	if s, ok := value.(string); ok && !json.Valid([]byte(s)) {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,63 @@
+package commercetools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCustomFields(t *testing.T) {
+	_, errs := validateCustomFields(map[string]interface{}{
+		"price":  `{"currencyCode": "EUR", "centAmount": 1000}`,
+		"lower":  `{"currencyCode": "eur", "centAmount": 0}`,
+		"name":   "summer",
+		"number": "10",
+		"other":  `{"value": "-1"}`,
+	}, "custom.0.fields")
+	assert.Empty(t, errs)
+
+	_, errs = validateCustomFields(map[string]interface{}{
+		"currency": `{"currencyCode": "EURO", "centAmount": 1000}`,
+	}, "custom.0.fields")
+	assert.EqualError(t, errs[0], "custom.0.fields.currency: currencyCode EURO is not a valid ISO 4217 currency code")
+
+	_, errs = validateCustomFields(map[string]interface{}{
+		"negative": `{"currencyCode": "EUR", "centAmount": -1}`,
+	}, "custom.0.fields")
+	assert.EqualError(t, errs[0], "custom.0.fields.negative: centAmount -1 is not a non-negative amount in cents")
+
+	_, errs = validateCustomFields(map[string]interface{}{
+		"fraction": `{"currencyCode": "EUR", "centAmount": 10.5}`,
+	}, "custom.0.fields")
+	assert.Len(t, errs, 1)
+
+	_, errs = validateCustomFields(map[string]interface{}{
+		"unknown": `{"currencyCode": "XYZ", "centAmount": 100}`,
+	}, "custom.0.fields")
+	assert.Len(t, errs, 1)
+
+	_, errs = validateCustomFields(map[string]interface{}{
+		"missing": `{"centAmount": 100}`,
+	}, "custom.0.fields")
+	assert.Len(t, errs, 1)
+}
+
+func TestUnmarshallCustomFieldValueMoney(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{
+		"type":         "centPrecision",
+		"currencyCode": "EUR",
+		"centAmount":   float64(1000),
+	}, unmarshallCustomFieldValue(`{"currencyCode": "eur", "centAmount": 1000}`))
+}
+
+func TestDiffSuppressCustomFieldValue(t *testing.T) {
+	configured := `{"currencyCode": "eur", "centAmount": 1000}`
+	assert.True(t, diffSuppressCustomFieldValue("custom.0.fields.price",
+		`{"type":"centPrecision","currencyCode":"EUR","centAmount":1000,"fractionDigits":2}`, configured, nil))
+	assert.False(t, diffSuppressCustomFieldValue("custom.0.fields.price",
+		`{"type":"centPrecision","currencyCode":"EUR","centAmount":1200,"fractionDigits":2}`, configured, nil))
+	assert.False(t, diffSuppressCustomFieldValue("custom.0.fields.price",
+		`{"type":"centPrecision","currencyCode":"USD","centAmount":1000,"fractionDigits":2}`, configured, nil))
+	assert.False(t, diffSuppressCustomFieldValue("custom.0.fields.name", "summer", "winter", nil))
+	assert.False(t, diffSuppressCustomFieldValue("custom.0.fields.count", "1", "2", nil))
+}
@@ -0,0 +1,143 @@
+package commercetools
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceAPIExtension() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches an existing API extension by its ID or key, for example to depend on an extension " +
+			"which is managed by another team. The credentials of the destination are not exposed.\n\n" +
+			"See also the [API Extension API Documentation](https://docs.commercetools.com/api/projects/api-extensions)",
+		ReadContext: dataSourceAPIExtensionRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description:  "The ID of the extension",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"key": {
+				Description:  "User-specific unique identifier for the extension",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"destination": {
+				Description: "[Destination](https://docs.commercetools.com/api/projects/api-extensions#destination) " +
+					"Details where the extension can be reached",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Description: "HTTP or AWSLambda",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"url": {
+							Description: "HTTP destination specific field. The URL of the extension",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"arn": {
+							Description: "AWSLambda destination specific field. The ARN of the Lambda function",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"access_key": {
+							Description: "AWSLambda destination specific field. The access key of the IAM user " +
+								"which invokes the Lambda function",
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"trigger": {
+				Description: "Array of [Trigger](https://docs.commercetools.com/api/projects/api-extensions#trigger) " +
+					"Describes what triggers the extension",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource_type_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"actions": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"timeout_in_ms": {
+				Description: "Extension timeout in milliseconds",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceAPIExtensionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+
+	var extension *platform.Extension
+	var err error
+	if id := d.Get("id").(string); id != "" {
+		log.Printf("[DEBUG] Reading extension from commercetools, with id: %s", id)
+		extension, err = client.Extensions().WithId(id).Get().Execute(ctx)
+	} else {
+		key := d.Get("key").(string)
+		log.Printf("[DEBUG] Reading extension from commercetools, with key: %s", key)
+		extension, err = client.Extensions().WithKey(key).Get().Execute(ctx)
+	}
+	if err != nil {
+		if isNotFoundError(err) {
+			return diag.Errorf("extension not found")
+		}
+		return diagnosticsFromError(err)
+	}
+
+	d.SetId(extension.ID)
+	d.Set("key", extension.Key)
+	d.Set("destination", marshallExtensionDestinationPublic(extension.Destination))
+	d.Set("trigger", marshallExtensionTriggers(extension.Triggers))
+	d.Set("timeout_in_ms", extension.TimeoutInMs)
+	d.Set("version", extension.Version)
+	return nil
+}
+
+// marshallExtensionDestinationPublic returns the destination without its
+// credentials, which a data source should not copy into the state of a
+// configuration which doesn't own the extension
+func marshallExtensionDestinationPublic(destination platform.ExtensionDestination) []map[string]interface{} {
+	switch v := destination.(type) {
+	case platform.HttpDestination:
+		return []map[string]interface{}{{
+			"type": "HTTP",
+			"url":  v.Url,
+		}}
+	case platform.AWSLambdaDestination:
+		return []map[string]interface{}{{
+			"type":       "awslambda",
+			"arn":        v.Arn,
+			"access_key": v.AccessKey,
+		}}
+	}
+	return []map[string]interface{}{}
+}
@@ -0,0 +1,114 @@
+package commercetools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourceAPIExtensionRead(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "extension-1",
+			"version": 3,
+			"key": "tax-calculation",
+			"destination": {
+				"type": "AWSLambda",
+				"arn": "arn:aws:lambda:eu-west-1:123456789012:function:tax",
+				"accessKey": "AKIA1234",
+				"accessSecret": "****"
+			},
+			"triggers": [{"resourceTypeId": "cart", "actions": ["Create", "Update"]}],
+			"timeoutInMs": 1000
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, dataSourceAPIExtension().Schema, map[string]interface{}{"key": "tax-calculation"})
+	diags := dataSourceAPIExtensionRead(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, "/my-project/extensions/key=tax-calculation", path)
+	assert.Equal(t, "extension-1", d.Id())
+	assert.Equal(t, 1000, d.Get("timeout_in_ms"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"type":       "awslambda",
+			"url":        "",
+			"arn":        "arn:aws:lambda:eu-west-1:123456789012:function:tax",
+			"access_key": "AKIA1234",
+		},
+	}, d.Get("destination"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"resource_type_id": "cart",
+			"actions":          []interface{}{"Create", "Update"},
+		},
+	}, d.Get("trigger"))
+}
+
+func TestAccDataSourceAPIExtension_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAPIExtensionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAPIExtensionConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_api_extension.by_key", "id",
+						"commercetools_api_extension.ext", "id",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_api_extension.by_key", "destination.0.url", "https://example.com",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_api_extension.by_id", "trigger.0.resource_type_id", "customer",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAPIExtensionConfig() string {
+	return `
+resource "commercetools_api_extension" "ext" {
+  key = "ds-extension"
+
+  destination {
+    type = "HTTP"
+    url  = "https://example.com"
+  }
+
+  trigger {
+    resource_type_id = "customer"
+    actions = ["Create"]
+  }
+}
+
+data "commercetools_api_extension" "by_key" {
+  key = commercetools_api_extension.ext.key
+}
+
+data "commercetools_api_extension" "by_id" {
+  id = commercetools_api_extension.ext.id
+}
+`
+}
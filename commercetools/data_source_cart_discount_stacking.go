@@ -0,0 +1,176 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+const (
+	cartDiscountStacks = "stacks"
+	cartDiscountBlocks = "blocks"
+)
+
+func dataSourceCartDiscountStacking() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reports which of the cart discounts which are active and valid at the time of reading can " +
+			"be combined in a cart, for example to review the promotions of a campaign before it starts. The " +
+			"cart discounts are applied in order of their sort order until a cart discount with the " +
+			"`StopAfterThisDiscount` stacking mode is applied, so they are grouped by the cart discounts which " +
+			"stop the stacking. The predicates are not evaluated, so the report shows the combinations which " +
+			"are possible when the predicates of the cart discounts match.\n\n" +
+			"See also the [Cart Discount API Documentation](https://docs.commercetools.com/api/projects/cartDiscounts)",
+		ReadContext: dataSourceCartDiscountStackingRead,
+		Schema: map[string]*schema.Schema{
+			"store": {
+				Description: "Only report on the cart discounts whose cart predicate is limited to the store " +
+					"with this key, like the `store` of the `commercetools_cart_discounts` data source",
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"groups": {
+				Description: "The cart discounts which can be applied together, in the order they are applied. " +
+					"Every group but the last ends with the cart discount which stops the stacking",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cart_discounts": {
+							Description: "The IDs of the cart discounts, in the order they are applied",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"stopped_by": {
+							Description: "The ID of the cart discount which ends the group, empty for the last group " +
+								"when no cart discount stops the stacking",
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"pairs": {
+				Description: "The pairs of cart discounts which stack, because they are in the same group, or " +
+					"of which the first blocks the second, because it stops the stacking before the group of the " +
+					"second. Other pairs only stack when the cart discounts between them which stop the stacking " +
+					"don't match the cart",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cart_discounts": {
+							Description: "The IDs of the two cart discounts, in the order they are applied",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"effect": {
+							Description: "`stacks` or `blocks`",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCartDiscountStackingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	store := d.Get("store").(string)
+
+	cartDiscounts, err := queryCartDiscounts(ctx, client, []string{cartDiscountActivePredicate(time.Now())}, nil)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+	if store != "" {
+		cartDiscounts = filterCartDiscountsByStore(cartDiscounts, store)
+	}
+
+	// Sort orders are decimals between 0 and 1 without trailing zeros, so they
+	// can be compared as strings
+	sort.Slice(cartDiscounts, func(i, j int) bool {
+		return cartDiscounts[i].SortOrder > cartDiscounts[j].SortOrder
+	})
+
+	groups := cartDiscountStackingGroups(cartDiscounts)
+	d.SetId(fmt.Sprintf("store=%s", store))
+	d.Set("groups", marshallCartDiscountStackingGroups(groups))
+	d.Set("pairs", cartDiscountStackingPairs(groups))
+	return nil
+}
+
+// cartDiscountStackingGroups splits the cart discounts, which are in the order
+// they are applied, after every cart discount which stops the stacking
+func cartDiscountStackingGroups(cartDiscounts []platform.CartDiscount) [][]platform.CartDiscount {
+	var groups [][]platform.CartDiscount
+	var current []platform.CartDiscount
+	for _, cartDiscount := range cartDiscounts {
+		current = append(current, cartDiscount)
+		if cartDiscount.StackingMode == platform.StackingModeStopAfterThisDiscount {
+			groups = append(groups, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+func marshallCartDiscountStackingGroups(groups [][]platform.CartDiscount) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(groups))
+	for i, group := range groups {
+		ids := make([]string, len(group))
+		for j, cartDiscount := range group {
+			ids[j] = cartDiscount.ID
+		}
+		stoppedBy := ""
+		if last := group[len(group)-1]; last.StackingMode == platform.StackingModeStopAfterThisDiscount {
+			stoppedBy = last.ID
+		}
+		result[i] = map[string]interface{}{
+			"cart_discounts": ids,
+			"stopped_by":     stoppedBy,
+		}
+	}
+	return result
+}
+
+// cartDiscountStackingPairs returns the pairs of cart discounts in the same
+// group, which stack, and the pairs of a cart discount which stops the
+// stacking with the cart discounts of the later groups, which it blocks
+func cartDiscountStackingPairs(groups [][]platform.CartDiscount) []map[string]interface{} {
+	result := []map[string]interface{}{}
+	for i, group := range groups {
+		for j, first := range group {
+			for _, second := range group[j+1:] {
+				result = append(result, map[string]interface{}{
+					"cart_discounts": []string{first.ID, second.ID},
+					"effect":         cartDiscountStacks,
+				})
+			}
+		}
+
+		last := group[len(group)-1]
+		if last.StackingMode != platform.StackingModeStopAfterThisDiscount {
+			continue
+		}
+		for _, later := range groups[i+1:] {
+			for _, second := range later {
+				result = append(result, map[string]interface{}{
+					"cart_discounts": []string{last.ID, second.ID},
+					"effect":         cartDiscountBlocks,
+				})
+			}
+		}
+	}
+	return result
+}
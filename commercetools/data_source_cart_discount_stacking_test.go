@@ -0,0 +1,75 @@
+package commercetools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCartDiscountStacking(t *testing.T) {
+	groups := cartDiscountStackingGroups([]platform.CartDiscount{
+		{ID: "a", StackingMode: platform.StackingModeStacking},
+		{ID: "b", StackingMode: platform.StackingModeStopAfterThisDiscount},
+		{ID: "c", StackingMode: platform.StackingModeStacking},
+		{ID: "d", StackingMode: platform.StackingModeStacking},
+	})
+
+	assert.Equal(t, []map[string]interface{}{
+		{"cart_discounts": []string{"a", "b"}, "stopped_by": "b"},
+		{"cart_discounts": []string{"c", "d"}, "stopped_by": ""},
+	}, marshallCartDiscountStackingGroups(groups))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"cart_discounts": []string{"a", "b"}, "effect": "stacks"},
+		{"cart_discounts": []string{"b", "c"}, "effect": "blocks"},
+		{"cart_discounts": []string{"b", "d"}, "effect": "blocks"},
+		{"cart_discounts": []string{"c", "d"}, "effect": "stacks"},
+	}, cartDiscountStackingPairs(groups))
+
+	assert.Empty(t, cartDiscountStackingPairs(nil))
+}
+
+func TestDataSourceCartDiscountStackingRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.URL.Query().Get("where"), "isActive = true"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": 3, "results": [
+			{"id": "low", "sortOrder": "0.1", "stackingMode": "Stacking", "cartPredicate": "1=1",
+				"value": {"type": "relative", "permyriad": 500}, "target": {"type": "shipping"}},
+			{"id": "high", "sortOrder": "0.9", "stackingMode": "StopAfterThisDiscount", "cartPredicate": "1=1",
+				"value": {"type": "relative", "permyriad": 1000}, "target": {"type": "shipping"}},
+			{"id": "berlin", "sortOrder": "0.5", "stackingMode": "Stacking", "cartPredicate": "store.key = \"berlin\"",
+				"value": {"type": "relative", "permyriad": 200}, "target": {"type": "shipping"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, dataSourceCartDiscountStacking().Schema, map[string]interface{}{})
+	diags := dataSourceCartDiscountStackingRead(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, []interface{}{"high"}, d.Get("groups.0.cart_discounts"))
+	assert.Equal(t, "high", d.Get("groups.0.stopped_by"))
+	assert.Equal(t, []interface{}{"berlin", "low"}, d.Get("groups.1.cart_discounts"))
+	assert.Equal(t, 3, d.Get("pairs.#"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceCartDiscountStacking().Schema, map[string]interface{}{
+		"store": "berlin",
+	})
+	diags = dataSourceCartDiscountStackingRead(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, []interface{}{"berlin"}, d.Get("groups.0.cart_discounts"))
+	assert.Equal(t, 0, d.Get("pairs.#"))
+}
@@ -0,0 +1,461 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+// queryPageSize is the number of results fetched per request when a data
+// source needs to page through all results of a query
+const queryPageSize = 500
+
+// queryPagePredicates returns the predicates of the page after the resource
+// with lastID, the results must be sorted by ID. commercetools rejects offsets
+// above 10,000, so the pages continue after the last ID instead of using an
+// offset. Every predicate is sent as its own where parameter, which
+// commercetools combines with and.
+func queryPagePredicates(where []string, lastID string) []string {
+	result := append([]string{}, where...)
+	if lastID != "" {
+		result = append(result, fmt.Sprintf("id > %s", quotePredicateString(lastID)))
+	}
+	return result
+}
+
+func dataSourceCartDiscounts() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the cart discounts of a project. When `group` is set only the cart discounts referenced " +
+			"by the discount codes in that group are returned, which helps to reconcile cart discounts with the " +
+			"`groups` of discount codes. When `store` is set only the cart discounts with a predicate limited " +
+			"to that store are returned. `active_now` limits the result to the cart discounts which are active " +
+			"and valid at the time of reading, for example to report on the running promotions.\n\n" +
+			"See also the [Cart Discount API Documentation](https://docs.commercetools.com/api/projects/cartDiscounts)",
+		ReadContext: dataSourceCartDiscountsRead,
+		Schema: map[string]*schema.Schema{
+			"group": {
+				Description: "Only return the cart discounts referenced by discount codes in this group",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"store": {
+				Description: "Only return the cart discounts whose cart predicate is limited to the store with " +
+					"this key, like `store.key = \"berlin\"` or `store.key in (\"berlin\", \"munich\")`. Cart " +
+					"discounts have no store reference, so the predicates are matched with a best effort parser",
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"stacking_mode": {
+				Description:  "Only return the cart discounts with this stacking mode",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStackingMode,
+			},
+			"value_type": {
+				Description: "Only return the cart discounts with this type of value, one of `" +
+					strings.Join(cartDiscountValueTypes, "`, `") + "`. The query API can't filter on the type " +
+					"of the value, so all cart discounts are fetched and filtered afterwards",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(cartDiscountValueTypes, false),
+			},
+			"active_now": {
+				Description: "Only return the cart discounts which are active and within their validity period",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"expand_gift_products": {
+				Description: "Fetch the product of giftLineItem cart discounts with reference expansion and set " +
+					"`gift_product`. A warning is returned for every gift product which no longer exists",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"cart_discounts": {
+				Description: "The matching cart discounts, ordered by descending sort order so in the order " +
+					"they are applied to a cart",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"key": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     TypeLocalizedString,
+							Computed: true,
+						},
+						"sort_order": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"predicate": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"stacking_mode": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"requires_discount_code": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"is_active": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"valid_from": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"valid_until": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"value": {
+							Description: "The value of the cart discount",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"permyriad": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"money": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"currency_code": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												"cent_amount": {
+													Type:     schema.TypeInt,
+													Computed: true,
+												},
+											},
+										},
+									},
+									"product_id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"variant_id": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"gift_product": {
+							Description: "The product of a giftLineItem cart discount, only set when " +
+								"`expand_gift_products` is enabled and the product exists",
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"name": {
+										Type:     TypeLocalizedString,
+										Computed: true,
+									},
+									"slug": {
+										Type:     TypeLocalizedString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"total": {
+				Description: "The number of matching cart discounts",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceCartDiscountsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	group := d.Get("group").(string)
+	store := d.Get("store").(string)
+	stackingMode := d.Get("stacking_mode").(string)
+	valueType := d.Get("value_type").(string)
+	activeNow := d.Get("active_now").(bool)
+
+	var expand []string
+	if d.Get("expand_gift_products").(bool) {
+		expand = []string{"value.product"}
+	}
+
+	where := []string{}
+	if stackingMode != "" {
+		where = append(where, fmt.Sprintf("stackingMode = %s", quotePredicateString(stackingMode)))
+	}
+	if activeNow {
+		where = append(where, cartDiscountActivePredicate(time.Now()))
+	}
+
+	var cartDiscounts []platform.CartDiscount
+	if group != "" {
+		ids, err := queryDiscountCodeGroupCartDiscountIDs(ctx, client, group)
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+		log.Printf("[DEBUG] Discount codes in group %s reference %d cart discounts", group, len(ids))
+
+		// Query the cart discounts in batches to keep the predicate short
+		for start := 0; start < len(ids); start += 100 {
+			end := start + 100
+			if end > len(ids) {
+				end = len(ids)
+			}
+			results, err := queryCartDiscounts(ctx, client, append(where, predicateIn("id", ids[start:end])), expand)
+			if err != nil {
+				return diagnosticsFromError(err)
+			}
+			cartDiscounts = append(cartDiscounts, results...)
+		}
+	} else {
+		results, err := queryCartDiscounts(ctx, client, where, expand)
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+		cartDiscounts = results
+	}
+
+	if store != "" {
+		cartDiscounts = filterCartDiscountsByStore(cartDiscounts, store)
+	}
+	if valueType != "" {
+		cartDiscounts = filterCartDiscountsByValueType(cartDiscounts, valueType)
+	}
+
+	// Sort orders are decimals between 0 and 1 without trailing zeros, so they
+	// can be compared as strings
+	sort.Slice(cartDiscounts, func(i, j int) bool {
+		return cartDiscounts[i].SortOrder > cartDiscounts[j].SortOrder
+	})
+
+	var diags diag.Diagnostics
+	result := make([]map[string]interface{}, len(cartDiscounts))
+	for i, cartDiscount := range cartDiscounts {
+		key := ""
+		if cartDiscount.Key != nil {
+			key = *cartDiscount.Key
+		}
+		result[i] = map[string]interface{}{
+			"id":                     cartDiscount.ID,
+			"key":                    key,
+			"name":                   cartDiscount.Name,
+			"sort_order":             cartDiscount.SortOrder,
+			"predicate":              cartDiscount.CartPredicate,
+			"stacking_mode":          string(cartDiscount.StackingMode),
+			"requires_discount_code": cartDiscount.RequiresDiscountCode,
+			"is_active":              cartDiscount.IsActive,
+			"valid_from":             marshallTime(cartDiscount.ValidFrom),
+			"valid_until":            marshallTime(cartDiscount.ValidUntil),
+			"value":                  marshallCartDiscountValueDetails(cartDiscount.Value),
+			"gift_product":           []map[string]interface{}{},
+		}
+		if expand != nil {
+			giftProduct, warning := marshallCartDiscountGiftProduct(cartDiscount)
+			result[i]["gift_product"] = giftProduct
+			if warning != nil {
+				diags = append(diags, *warning)
+			}
+		}
+	}
+
+	id := fmt.Sprintf("group=%s,store=%s,stacking_mode=%s", group, store, stackingMode)
+	if valueType != "" {
+		id += fmt.Sprintf(",value_type=%s", valueType)
+	}
+	if activeNow {
+		id += ",active_now"
+	}
+	d.SetId(id)
+	d.Set("cart_discounts", result)
+	d.Set("total", len(result))
+	return diags
+}
+
+// cartDiscountActivePredicate returns a query predicate matching the cart
+// discounts which are active and valid at the given time
+func cartDiscountActivePredicate(now time.Time) string {
+	timestamp := now.UTC().Format("2006-01-02T15:04:05.000Z")
+	return fmt.Sprintf(
+		"isActive = true and (validFrom is not defined or validFrom <= %q) and "+
+			"(validUntil is not defined or validUntil >= %q)", timestamp, timestamp)
+}
+
+// filterCartDiscountsByStore returns the cart discounts with a cart predicate
+// limited to the given store
+func filterCartDiscountsByStore(cartDiscounts []platform.CartDiscount, store string) []platform.CartDiscount {
+	result := []platform.CartDiscount{}
+	for _, cartDiscount := range cartDiscounts {
+		for _, key := range parsePredicateStoreKeys(cartDiscount.CartPredicate) {
+			if key == store {
+				result = append(result, cartDiscount)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// cartDiscountValueTypes are the types of cart discount values
+var cartDiscountValueTypes = []string{"relative", "absolute", "fixed", "giftLineItem"}
+
+// cartDiscountValueType returns the type of the value as used by the API
+func cartDiscountValueType(value platform.CartDiscountValue) string {
+	switch value.(type) {
+	case platform.CartDiscountValueRelative:
+		return "relative"
+	case platform.CartDiscountValueAbsolute:
+		return "absolute"
+	case platform.CartDiscountValueFixed:
+		return "fixed"
+	case platform.CartDiscountValueGiftLineItem:
+		return "giftLineItem"
+	}
+	return ""
+}
+
+// filterCartDiscountsByValueType returns the cart discounts with a value of
+// the given type
+func filterCartDiscountsByValueType(cartDiscounts []platform.CartDiscount, valueType string) []platform.CartDiscount {
+	result := []platform.CartDiscount{}
+	for _, cartDiscount := range cartDiscounts {
+		if cartDiscountValueType(cartDiscount.Value) == valueType {
+			result = append(result, cartDiscount)
+		}
+	}
+	return result
+}
+
+// marshallCartDiscountValueDetails returns the value with only the fields
+// of its type set
+func marshallCartDiscountValueDetails(value platform.CartDiscountValue) []map[string]interface{} {
+	result := map[string]interface{}{
+		"type":  cartDiscountValueType(value),
+		"money": []map[string]interface{}{},
+	}
+	switch v := value.(type) {
+	case platform.CartDiscountValueRelative:
+		result["permyriad"] = v.Permyriad
+	case platform.CartDiscountValueAbsolute:
+		result["money"] = marshallTypedMoneyList(v.Money)
+	case platform.CartDiscountValueFixed:
+		result["money"] = marshallTypedMoneyList(v.Money)
+	case platform.CartDiscountValueGiftLineItem:
+		result["product_id"] = v.Product.ID
+		result["variant_id"] = v.VariantId
+	default:
+		return []map[string]interface{}{}
+	}
+	return []map[string]interface{}{result}
+}
+
+// marshallCartDiscountGiftProduct returns the expanded gift product of a
+// giftLineItem cart discount. When the reference could not be expanded the
+// product was deleted, which is returned as a warning.
+func marshallCartDiscountGiftProduct(cartDiscount platform.CartDiscount) ([]map[string]interface{}, *diag.Diagnostic) {
+	value, ok := cartDiscount.Value.(platform.CartDiscountValueGiftLineItem)
+	if !ok {
+		return []map[string]interface{}{}, nil
+	}
+
+	product := value.Product.Obj
+	if product == nil {
+		return []map[string]interface{}{}, &diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Gift product of cart discount %s does not exist", cartDiscount.ID),
+			Detail: fmt.Sprintf("The cart discount %s references the gift product %s, which could not be "+
+				"found. Carts matching the cart discount will fail to add the gift line item.",
+				cartDiscount.ID, value.Product.ID),
+		}
+	}
+
+	return []map[string]interface{}{{
+		"id":   product.ID,
+		"name": product.MasterData.Current.Name,
+		"slug": product.MasterData.Current.Slug,
+	}}, nil
+}
+
+func queryCartDiscounts(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, where []string, expand []string) ([]platform.CartDiscount, error) {
+	var result []platform.CartDiscount
+	lastID := ""
+	for {
+		request := client.CartDiscounts().Get().
+			Sort([]string{"id asc"}).
+			Limit(queryPageSize).
+			WithTotal(false)
+		if predicates := queryPagePredicates(where, lastID); len(predicates) > 0 {
+			request = request.Where(predicates)
+		}
+		if len(expand) > 0 {
+			request = request.Expand(expand)
+		}
+		response, err := request.Execute(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, response.Results...)
+		if response.Count < queryPageSize || len(response.Results) == 0 {
+			return result, nil
+		}
+		lastID = response.Results[len(response.Results)-1].ID
+	}
+}
+
+// queryDiscountCodeGroupCartDiscountIDs returns the IDs of the cart discounts
+// referenced by the discount codes in the given group
+func queryDiscountCodeGroupCartDiscountIDs(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, group string) ([]string, error) {
+	discountCodes, err := queryDiscountCodes(ctx, client, []string{discountCodeGroupPredicate(group)})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, discountCode := range discountCodes {
+		for _, ref := range discountCode.CartDiscounts {
+			ids[ref.ID] = true
+		}
+	}
+	return sortedKeys(ids), nil
+}
+
+// predicateIn returns a query predicate matching the field against one of the
+// given values
+func predicateIn(field string, values []string) string {
+	return fmt.Sprintf("%s in (%s)", field, quotePredicateStrings(values))
+}
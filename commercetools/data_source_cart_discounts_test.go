@@ -0,0 +1,180 @@
+package commercetools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredicateIn(t *testing.T) {
+	assert.Equal(t, `id in ("a", "b")`, predicateIn("id", []string{"a", "b"}))
+	assert.Equal(t, `key in ("say \"hi\"")`, predicateIn("key", []string{`say "hi"`}))
+	// Only quotes and backslashes are escaped in predicates, unlike in Go
+	assert.Equal(t, "key in (\"caf\u00e9\u00a0\\\\1\")", predicateIn("key", []string{"caf\u00e9\u00a0\\1"}))
+}
+
+func TestQueryPagePredicates(t *testing.T) {
+	where := []string{"isActive = true"}
+	assert.Equal(t, []string{"isActive = true"}, queryPagePredicates(where, ""))
+	assert.Equal(t, []string{"isActive = true", `id > "cart-discount-500"`}, queryPagePredicates(where, "cart-discount-500"))
+	assert.Equal(t, []string{`id > "a"`}, queryPagePredicates(nil, "a"))
+	assert.Equal(t, []string{"isActive = true"}, where)
+}
+
+func TestCartDiscountActivePredicate(t *testing.T) {
+	now := time.Date(2021, 8, 1, 14, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	assert.Equal(t,
+		`isActive = true and (validFrom is not defined or validFrom <= "2021-08-01T12:30:00.000Z") and `+
+			`(validUntil is not defined or validUntil >= "2021-08-01T12:30:00.000Z")`,
+		cartDiscountActivePredicate(now))
+}
+
+func TestFilterCartDiscountsByStore(t *testing.T) {
+	cartDiscounts := []platform.CartDiscount{
+		{ID: "berlin", CartPredicate: `store.key = "berlin"`},
+		{ID: "stores", CartPredicate: `(1 = 1) and store.key in ("munich", "berlin")`},
+		{ID: "not-berlin", CartPredicate: `store.key != "berlin"`},
+		{ID: "munich", CartPredicate: `store.key = "munich"`},
+		{ID: "all", CartPredicate: `1 = 1`},
+	}
+
+	result := filterCartDiscountsByStore(cartDiscounts, "berlin")
+	ids := []string{}
+	for _, cartDiscount := range result {
+		ids = append(ids, cartDiscount.ID)
+	}
+	assert.Equal(t, []string{"berlin", "stores"}, ids)
+}
+
+func TestFilterCartDiscountsByValueType(t *testing.T) {
+	cartDiscounts := []platform.CartDiscount{
+		{ID: "relative", Value: platform.CartDiscountValueRelative{Permyriad: 1000}},
+		{ID: "fixed", Value: platform.CartDiscountValueFixed{Money: []platform.TypedMoney{
+			platform.CentPrecisionMoney{CurrencyCode: "EUR", CentAmount: 999},
+		}}},
+		{ID: "gift", Value: platform.CartDiscountValueGiftLineItem{
+			Product:   platform.ProductReference{ID: "product-1"},
+			VariantId: 2,
+		}},
+	}
+
+	result := filterCartDiscountsByValueType(cartDiscounts, "fixed")
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "fixed", result[0].ID)
+	}
+	assert.Empty(t, filterCartDiscountsByValueType(cartDiscounts, "absolute"))
+
+	assert.Equal(t, []map[string]interface{}{{
+		"type":  "fixed",
+		"money": []map[string]interface{}{{"currency_code": "EUR", "cent_amount": 999}},
+	}}, marshallCartDiscountValueDetails(cartDiscounts[1].Value))
+	assert.Equal(t, []map[string]interface{}{{
+		"type":       "giftLineItem",
+		"money":      []map[string]interface{}{},
+		"product_id": "product-1",
+		"variant_id": 2,
+	}}, marshallCartDiscountValueDetails(cartDiscounts[2].Value))
+}
+
+func TestMarshallCartDiscountGiftProduct(t *testing.T) {
+	product := &platform.Product{
+		ID: "product-1",
+		MasterData: platform.ProductCatalogData{
+			Current: platform.ProductData{
+				Name: platform.LocalizedString{"en": "Gift"},
+				Slug: platform.LocalizedString{"en": "gift"},
+			},
+		},
+	}
+
+	result, warning := marshallCartDiscountGiftProduct(platform.CartDiscount{
+		ID: "cart-discount-1",
+		Value: platform.CartDiscountValueGiftLineItem{
+			Product: platform.ProductReference{ID: "product-1", Obj: product},
+		},
+	})
+	assert.Nil(t, warning)
+	assert.Equal(t, []map[string]interface{}{{
+		"id":   "product-1",
+		"name": platform.LocalizedString{"en": "Gift"},
+		"slug": platform.LocalizedString{"en": "gift"},
+	}}, result)
+
+	result, warning = marshallCartDiscountGiftProduct(platform.CartDiscount{
+		ID: "cart-discount-1",
+		Value: platform.CartDiscountValueGiftLineItem{
+			Product: platform.ProductReference{ID: "product-1"},
+		},
+	})
+	assert.Empty(t, result)
+	assert.Equal(t, diag.Warning, warning.Severity)
+	assert.Contains(t, warning.Detail, "product-1")
+
+	result, warning = marshallCartDiscountGiftProduct(platform.CartDiscount{
+		Value: platform.CartDiscountValueRelative{Permyriad: 1000},
+	})
+	assert.Empty(t, result)
+	assert.Nil(t, warning)
+}
+
+func TestAccDataSourceCartDiscounts_group(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDiscountCodeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCartDiscountsConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.commercetools_cart_discounts.summer", "cart_discounts.#", "1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_cart_discounts.summer", "total", "1",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_cart_discounts.summer", "cart_discounts.0.id",
+						"commercetools_cart_discount.summer", "id",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceCartDiscountsConfig() string {
+	return `
+resource "commercetools_cart_discount" "summer" {
+	name = {
+		en = "Summer"
+	}
+	sort_order             = "0.111"
+	predicate              = "1=1"
+	requires_discount_code = true
+
+	value {
+		type      = "relative"
+		permyriad = 1000
+	}
+
+	target {
+		type      = "lineItems"
+		predicate = "1=1"
+	}
+}
+
+resource "commercetools_discount_code" "summer" {
+	code           = "SUMMER-GROUP"
+	groups         = ["summer-campaign"]
+	cart_discounts = [commercetools_cart_discount.summer.id]
+}
+
+data "commercetools_cart_discounts" "summer" {
+	group = commercetools_discount_code.summer.groups[0]
+}
+`
+}
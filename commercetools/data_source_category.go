@@ -0,0 +1,179 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceCategory() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches an existing category by its ID, key or slug. The `ancestor_ids` together with the " +
+			"`id` can be used to build `categories.id in (...)` predicates.\n\n" +
+			"See also the [Category API Documentation](https://docs.commercetools.com/api/projects/categories)",
+		ReadContext: dataSourceCategoryRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description:  "The ID of the category",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key", "slug"},
+			},
+			"key": {
+				Description:  "User-specific unique identifier for the category",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key", "slug"},
+			},
+			"slug": {
+				Description:  "The slug of the category in the given `locale`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"id", "key", "slug"},
+				RequiredWith: []string{"locale"},
+			},
+			"locale": {
+				Description:  "The locale of the `slug`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"slug"},
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile("^[a-z]{2}(-[A-Z]{2})?$"),
+					"Locales must match pattern ^[a-z]{2}(-[A-Z]{2})?$",
+				),
+			},
+			"name": {
+				Description: "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+				Type:        TypeLocalizedString,
+				Computed:    true,
+			},
+			"parent": {
+				Description: "The ID of the parent category, empty for a root category",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"ancestor_ids": {
+				Description: "The IDs of the ancestors of the category, from the root category to the parent",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"ancestors": {
+				Description: "The ancestors of the category, from the root category to the parent",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"key": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     TypeLocalizedString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceCategoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	expand := []string{"ancestors[*]"}
+
+	var category *platform.Category
+	var err error
+	if id := d.Get("id").(string); id != "" {
+		log.Printf("[DEBUG] Reading category from commercetools, with id: %s", id)
+		category, err = client.Categories().WithId(id).Get().Expand(expand).Execute(ctx)
+	} else if key := d.Get("key").(string); key != "" {
+		log.Printf("[DEBUG] Reading category from commercetools, with key: %s", key)
+		category, err = client.Categories().WithKey(key).Get().Expand(expand).Execute(ctx)
+	} else {
+		slug := d.Get("slug").(string)
+		locale := d.Get("locale").(string)
+		log.Printf("[DEBUG] Reading category from commercetools, with slug: %s (%s)", slug, locale)
+		category, err = queryCategoryBySlug(ctx, client, locale, slug, expand)
+	}
+	if err != nil {
+		if isNotFoundError(err) {
+			return diag.Errorf("category not found")
+		}
+		return diagnosticsFromError(err)
+	}
+
+	d.SetId(category.ID)
+	d.Set("key", category.Key)
+	d.Set("name", category.Name)
+	if category.Parent != nil {
+		d.Set("parent", category.Parent.ID)
+	} else {
+		d.Set("parent", "")
+	}
+	d.Set("ancestor_ids", marshallCategoryAncestorIDs(category.Ancestors))
+	d.Set("ancestors", marshallCategoryAncestors(category.Ancestors))
+	d.Set("version", category.Version)
+	return nil
+}
+
+func queryCategoryBySlug(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, locale, slug string, expand []string) (*platform.Category, error) {
+	result, err := client.Categories().Get().
+		Where([]string{fmt.Sprintf("slug(`%s` = %s)", locale, quotePredicateString(slug))}).
+		Expand(expand).
+		Limit(1).
+		Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Results) == 0 {
+		return nil, fmt.Errorf("no category found with slug %q in locale %s", slug, locale)
+	}
+	return &result.Results[0], nil
+}
+
+func marshallCategoryAncestorIDs(ancestors []platform.CategoryReference) []string {
+	result := make([]string, len(ancestors))
+	for i, ancestor := range ancestors {
+		result[i] = ancestor.ID
+	}
+	return result
+}
+
+// marshallCategoryAncestors returns the ancestors of a category. The key and
+// name are only set when the references were expanded.
+func marshallCategoryAncestors(ancestors []platform.CategoryReference) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(ancestors))
+	for i, ancestor := range ancestors {
+		item := map[string]interface{}{
+			"id":   ancestor.ID,
+			"key":  "",
+			"name": platform.LocalizedString{},
+		}
+		if ancestor.Obj != nil {
+			if ancestor.Obj.Key != nil {
+				item["key"] = *ancestor.Obj.Key
+			}
+			item["name"] = ancestor.Obj.Name
+		}
+		result[i] = item
+	}
+	return result
+}
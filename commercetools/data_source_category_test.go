@@ -0,0 +1,131 @@
+package commercetools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshallCategoryAncestors(t *testing.T) {
+	key := "root"
+	ancestors := []platform.CategoryReference{
+		{ID: "root-id", Obj: &platform.Category{ID: "root-id", Key: &key, Name: platform.LocalizedString{"en": "Root"}}},
+		{ID: "parent-id"},
+	}
+
+	assert.Equal(t, []string{"root-id", "parent-id"}, marshallCategoryAncestorIDs(ancestors))
+	assert.Equal(t, []map[string]interface{}{
+		{"id": "root-id", "key": "root", "name": platform.LocalizedString{"en": "Root"}},
+		{"id": "parent-id", "key": "", "name": platform.LocalizedString{}},
+	}, marshallCategoryAncestors(ancestors))
+}
+
+func TestQueryCategoryBySlug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "slug(`en-US` = \"summer-\\\"21\\\"\")", r.URL.Query().Get("where"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count":   1,
+			"results": []map[string]interface{}{{"id": "category-id", "version": 3}},
+		})
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+
+	category, err := queryCategoryBySlug(
+		context.Background(), client.WithProjectKey("my-project"),
+		"en-US", `summer-"21"`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "category-id", category.ID)
+}
+
+func TestAccDataSourceCategory_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCategoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCategoryConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_category.by_slug", "id",
+						"commercetools_category.child", "id",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_category.by_slug", "parent",
+						"commercetools_category.parent", "id",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_category.by_slug", "ancestors.#", "2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_category.by_slug", "ancestors.0.key", "ds-root",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_category.by_key", "ancestor_ids.1",
+						"commercetools_category.parent", "id",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceCategoryConfig() string {
+	return `
+resource "commercetools_category" "root" {
+	key  = "ds-root"
+	name = {
+		en = "Root"
+	}
+	slug = {
+		en = "ds-root"
+	}
+}
+
+resource "commercetools_category" "parent" {
+	key    = "ds-parent"
+	parent = commercetools_category.root.id
+	name = {
+		en = "Parent"
+	}
+	slug = {
+		en = "ds-parent"
+	}
+}
+
+resource "commercetools_category" "child" {
+	key    = "ds-child"
+	parent = commercetools_category.parent.id
+	name = {
+		en = "Child"
+	}
+	slug = {
+		en = "ds-child"
+	}
+}
+
+data "commercetools_category" "by_slug" {
+	slug   = "ds-child"
+	locale = "en"
+
+	depends_on = [commercetools_category.child]
+}
+
+data "commercetools_category" "by_key" {
+	key = commercetools_category.child.key
+}
+`
+}
@@ -0,0 +1,166 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceCustomer() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches an existing customer by its ID, key or email, for example to check the customer " +
+			"group a discount targets. In projects with store specific customers multiple customers can have the " +
+			"same email, set `store` to select the customer of a store in that case.\n\n" +
+			"See also the [Customer API Documentation](https://docs.commercetools.com/api/projects/customers)",
+		ReadContext: dataSourceCustomerRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description:  "The ID of the customer",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key", "email"},
+			},
+			"key": {
+				Description:  "User-specific unique identifier for the customer",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key", "email"},
+			},
+			"email": {
+				Description:  "The email of the customer, matched exactly",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key", "email"},
+			},
+			"store": {
+				Description:  "Only match the customers of the store with this key when looking up by `email`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"email"},
+			},
+			"customer_number": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"first_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"customer_group_id": {
+				Description: "The ID of the customer group of the customer",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"stores": {
+				Description: "The keys of the stores of the customer, empty for customers of all stores",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"custom": customFieldComputedSchema(),
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceCustomerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+
+	var customer *platform.Customer
+	var err error
+	switch {
+	case d.Get("id").(string) != "":
+		id := d.Get("id").(string)
+		log.Printf("[DEBUG] Reading customer from commercetools, with id: %s", id)
+		customer, err = client.Customers().WithId(id).Get().Execute(ctx)
+	case d.Get("key").(string) != "":
+		key := d.Get("key").(string)
+		log.Printf("[DEBUG] Reading customer from commercetools, with key: %s", key)
+		customer, err = client.Customers().WithKey(key).Get().Execute(ctx)
+	default:
+		customer, err = queryCustomerByEmail(ctx, client, d.Get("email").(string), d.Get("store").(string))
+	}
+	if err != nil {
+		if isNotFoundError(err) {
+			return diag.Errorf("customer not found")
+		}
+		return diagnosticsFromError(err)
+	}
+	if customer == nil {
+		return diag.Errorf("customer not found")
+	}
+
+	customerGroupID := ""
+	if customer.CustomerGroup != nil {
+		customerGroupID = customer.CustomerGroup.ID
+	}
+	stores := make([]string, len(customer.Stores))
+	for i, store := range customer.Stores {
+		stores[i] = store.Key
+	}
+
+	d.SetId(customer.ID)
+	d.Set("key", customer.Key)
+	d.Set("email", customer.Email)
+	d.Set("customer_number", customer.CustomerNumber)
+	d.Set("first_name", customer.FirstName)
+	d.Set("last_name", customer.LastName)
+	d.Set("customer_group_id", customerGroupID)
+	d.Set("stores", stores)
+	d.Set("custom", marshallCustomFields(customer.Custom))
+	d.Set("version", customer.Version)
+	return nil
+}
+
+// queryCustomerByEmail returns the customer with the email, or nil when there
+// is none. Store specific customers can share an email, so an error is
+// returned when the email matches more than one customer.
+func queryCustomerByEmail(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, email string, store string) (*platform.Customer, error) {
+	where := []string{fmt.Sprintf("email = %s", quotePredicateString(email))}
+	if store != "" {
+		where = append(where, fmt.Sprintf("stores(key = %s)", quotePredicateString(store)))
+	}
+
+	log.Printf("[DEBUG] Querying customer from commercetools, with email: %s", email)
+	result, err := client.Customers().Get().Where([]string{strings.Join(where, " and ")}).Limit(10).Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(result.Results) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &result.Results[0], nil
+	}
+
+	matches := make([]string, len(result.Results))
+	for i, customer := range result.Results {
+		stores := make([]string, len(customer.Stores))
+		for j, store := range customer.Stores {
+			stores[j] = store.Key
+		}
+		if len(stores) == 0 {
+			stores = []string{"all stores"}
+		}
+		matches[i] = fmt.Sprintf("%s (%s)", customer.ID, strings.Join(stores, ", "))
+	}
+	return nil, fmt.Errorf(
+		"the email %s matches %d customers: %s. Set store, or look up the customer by id or key",
+		email, len(result.Results), strings.Join(matches, ", "))
+}
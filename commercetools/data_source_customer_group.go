@@ -0,0 +1,73 @@
+package commercetools
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceCustomerGroup() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches an existing customer group by its ID or key, for example to reference a customer group " +
+			"which is managed outside of terraform.\n\n" +
+			"See also the [Customer Group API Documentation](https://docs.commercetools.com/api/projects/customerGroups)",
+		ReadContext: dataSourceCustomerGroupRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description:  "The ID of the customer group",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"key": {
+				Description:  "User-specific unique identifier for the customer group",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"name": {
+				Description: "Unique within the project",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"custom": customFieldComputedSchema(),
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceCustomerGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+
+	var customerGroup *platform.CustomerGroup
+	var err error
+	if id := d.Get("id").(string); id != "" {
+		log.Printf("[DEBUG] Reading customer group from commercetools, with id: %s", id)
+		customerGroup, err = client.CustomerGroups().WithId(id).Get().Execute(ctx)
+	} else {
+		key := d.Get("key").(string)
+		log.Printf("[DEBUG] Reading customer group from commercetools, with key: %s", key)
+		customerGroup, err = client.CustomerGroups().WithKey(key).Get().Execute(ctx)
+	}
+	if err != nil {
+		if isNotFoundError(err) {
+			return diag.Errorf("customer group not found")
+		}
+		return diagnosticsFromError(err)
+	}
+
+	d.SetId(customerGroup.ID)
+	d.Set("key", customerGroup.Key)
+	d.Set("name", customerGroup.Name)
+	d.Set("custom", marshallCustomFields(customerGroup.Custom))
+	d.Set("version", customerGroup.Version)
+	return nil
+}
@@ -0,0 +1,49 @@
+package commercetools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCustomerGroup_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCustomerGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCustomerGroupConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_customer_group.by_key", "id",
+						"commercetools_customer_group.standard", "id",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_customer_group.by_key", "name", "Standard name",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_customer_group.by_id", "key", "standard-key",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceCustomerGroupConfig() string {
+	return `
+resource "commercetools_customer_group" "standard" {
+	name = "Standard name"
+	key  = "standard-key"
+}
+
+data "commercetools_customer_group" "by_key" {
+	key = commercetools_customer_group.standard.key
+}
+
+data "commercetools_customer_group" "by_id" {
+	id = commercetools_customer_group.standard.id
+}
+`
+}
@@ -0,0 +1,81 @@
+package commercetools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourceCustomerReadByEmail(t *testing.T) {
+	var where string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/my-project/customers", r.URL.Path)
+		where = r.URL.Query().Get("where")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": 1, "results": [{
+			"id": "customer-1",
+			"version": 2,
+			"email": "jane@example.com",
+			"firstName": "Jane",
+			"customerGroup": {"typeId": "customer-group", "id": "group-1"},
+			"stores": [{"typeId": "store", "key": "nl"}],
+			"custom": {"type": {"typeId": "type", "id": "type-1"}, "fields": {"newsletter": true}}
+		}]}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, dataSourceCustomer().Schema, map[string]interface{}{
+		"email": "jane@example.com",
+		"store": "nl",
+	})
+	diags := dataSourceCustomerRead(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, `email = "jane@example.com" and stores(key = "nl")`, where)
+	assert.Equal(t, "customer-1", d.Id())
+	assert.Equal(t, "Jane", d.Get("first_name"))
+	assert.Equal(t, "group-1", d.Get("customer_group_id"))
+	assert.Equal(t, []interface{}{"nl"}, d.Get("stores"))
+	assert.Equal(t, "type-1", d.Get("custom.0.type_id"))
+	assert.Equal(t, "true", d.Get("custom.0.fields.newsletter"))
+}
+
+func TestDataSourceCustomerReadByEmailAmbiguous(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": 2, "results": [
+			{"id": "customer-1", "email": "jane@example.com", "stores": [{"typeId": "store", "key": "nl"}]},
+			{"id": "customer-2", "email": "jane@example.com", "stores": []}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, dataSourceCustomer().Schema, map[string]interface{}{
+		"email": "jane@example.com",
+	})
+	diags := dataSourceCustomerRead(context.Background(), d, meta)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t,
+			"the email jane@example.com matches 2 customers: customer-1 (nl), customer-2 (all stores). "+
+				"Set store, or look up the customer by id or key",
+			diags[0].Summary)
+	}
+}
@@ -0,0 +1,141 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceDiscountCode() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches an existing discount code by its ID or code. The attributes have the same names as " +
+			"the attributes of the `commercetools_discount_code` resource, so the settings of an existing code " +
+			"can be copied to a new code.\n\n" +
+			"See also the [Discount Code Api Documentation](https://docs.commercetools.com/api/projects/discountCodes)",
+		ReadContext: dataSourceDiscountCodeRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description:  "The ID of the discount code",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "code"},
+			},
+			"code": {
+				Description:  "Unique identifier of the discount code",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "code"},
+			},
+			"name": {
+				Description: "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+				Type:        TypeLocalizedString,
+				Computed:    true,
+			},
+			"description": {
+				Description: "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+				Type:        TypeLocalizedString,
+				Computed:    true,
+			},
+			"predicate": {
+				Description: "[Cart Predicate](https://docs.commercetools.com/api/projects/predicates#cart-predicates)",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"cart_discounts": {
+				Description: "The IDs of the referenced cart discounts",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"groups": {
+				Description: "The groups to which this discount code belong",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"is_active": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"valid_from": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"valid_until": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"max_applications": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"max_applications_per_customer": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"custom": customFieldComputedSchema(),
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDiscountCodeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+
+	var discountCode *platform.DiscountCode
+	if id := d.Get("id").(string); id != "" {
+		log.Printf("[DEBUG] Reading discount code from commercetools, with id: %s", id)
+		result, err := client.DiscountCodes().WithId(id).Get().Execute(ctx)
+		if err != nil {
+			if isNotFoundError(err) {
+				return diag.Errorf("discount code not found")
+			}
+			return diagnosticsFromError(err)
+		}
+		discountCode = result
+	} else {
+		code := d.Get("code").(string)
+		log.Printf("[DEBUG] Reading discount code from commercetools, with code: %s", code)
+		result, err := client.DiscountCodes().Get().
+			Where([]string{fmt.Sprintf("code = %s", quotePredicateString(code))}).
+			Limit(1).
+			Execute(ctx)
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+		if len(result.Results) == 0 {
+			return diag.Errorf("discount code not found")
+		}
+		discountCode = &result.Results[0]
+	}
+
+	predicate := ""
+	if discountCode.CartPredicate != nil {
+		predicate = *discountCode.CartPredicate
+	}
+
+	d.SetId(discountCode.ID)
+	d.Set("code", discountCode.Code)
+	d.Set("name", discountCode.Name)
+	d.Set("description", discountCode.Description)
+	d.Set("predicate", predicate)
+	d.Set("cart_discounts", marshallDiscountCodeCartDiscounts(discountCode.CartDiscounts, nil))
+	d.Set("groups", discountCode.Groups)
+	d.Set("is_active", discountCode.IsActive)
+	d.Set("valid_from", marshallTime(discountCode.ValidFrom))
+	d.Set("valid_until", marshallTime(discountCode.ValidUntil))
+	d.Set("max_applications", discountCode.MaxApplications)
+	d.Set("max_applications_per_customer", discountCode.MaxApplicationsPerCustomer)
+	d.Set("custom", marshallCustomFields(discountCode.Custom))
+	d.Set("version", discountCode.Version)
+	return nil
+}
@@ -0,0 +1,120 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceDiscountCodeImports() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists all discount codes of the project with a resource name for each of them, to import " +
+			"the discount codes of an existing project into terraform. Write `import_blocks` to a file to import " +
+			"the codes with terraform 1.5 or later, or run the `import_commands` with older versions.\n\n" +
+			"See also the [Discount Code Api Documentation](https://docs.commercetools.com/api/projects/discountCodes)",
+		ReadContext: dataSourceDiscountCodeImportsRead,
+		Schema: map[string]*schema.Schema{
+			"where": {
+				Description: "A [query predicate](https://docs.commercetools.com/api/predicates/query) to only " +
+					"import the matching discount codes, for example `groups contains \"summer\"`",
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"discount_codes": {
+				Description: "The discount codes, ordered by code",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"code": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"import_commands": {
+				Description: "A `terraform import` command for every discount code",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"import_blocks": {
+				Description: "An `import` block for every discount code",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceDiscountCodeImportsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	where := d.Get("where").(string)
+
+	var predicates []string
+	if where != "" {
+		predicates = append(predicates, where)
+	}
+	discountCodes, err := queryDiscountCodes(ctx, client, predicates)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	names := discountCodeResourceNames(discountCodes)
+	result := make([]map[string]interface{}, len(discountCodes))
+	commands := make([]string, len(discountCodes))
+	blocks := make([]string, len(discountCodes))
+	for i, discountCode := range discountCodes {
+		address := fmt.Sprintf("commercetools_discount_code.%s", names[i])
+		result[i] = map[string]interface{}{
+			"id":            discountCode.ID,
+			"code":          discountCode.Code,
+			"resource_name": names[i],
+		}
+		commands[i] = fmt.Sprintf("terraform import %s %s", address, discountCode.ID)
+		blocks[i] = fmt.Sprintf("import {\n  to = %s\n  id = %q\n}\n", address, discountCode.ID)
+	}
+
+	d.SetId(fmt.Sprintf("where=%s", where))
+	d.Set("discount_codes", result)
+	d.Set("import_commands", commands)
+	d.Set("import_blocks", strings.Join(blocks, "\n"))
+	return nil
+}
+
+var invalidResourceNameCharacters = regexp.MustCompile("[^a-z0-9_-]+")
+
+// discountCodeResourceNames returns a unique terraform resource name for
+// every discount code, derived from the code. Codes are case sensitive, so a
+// name which is already taken gets a number as suffix.
+func discountCodeResourceNames(discountCodes []platform.DiscountCode) []string {
+	taken := map[string]bool{}
+	result := make([]string, len(discountCodes))
+	for i, discountCode := range discountCodes {
+		base := invalidResourceNameCharacters.ReplaceAllString(strings.ToLower(discountCode.Code), "_")
+		if base == "" || !(base[0] == '_' || (base[0] >= 'a' && base[0] <= 'z')) {
+			base = "code_" + base
+		}
+		name := base
+		for n := 2; taken[name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		taken[name] = true
+		result[i] = name
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package commercetools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscountCodeResourceNames(t *testing.T) {
+	result := discountCodeResourceNames([]platform.DiscountCode{
+		{Code: "SUMMER-2021"},
+		{Code: "summer-2021"},
+		{Code: "Summer-2021"},
+		{Code: "10% OFF"},
+		{Code: "free shipping!"},
+	})
+	assert.Equal(t, []string{
+		"summer-2021",
+		"summer-2021_2",
+		"summer-2021_3",
+		"code_10_off",
+		"free_shipping_",
+	}, result)
+}
+
+func TestDataSourceDiscountCodeImportsRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `groups contains "summer"`, r.URL.Query().Get("where"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": 2, "results": [
+			{"id": "id-1", "version": 1, "code": "SUMMER-1"},
+			{"id": "id-2", "version": 1, "code": "SUMMER-2"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, dataSourceDiscountCodeImports().Schema, map[string]interface{}{
+		"where": `groups contains "summer"`,
+	})
+	diags := dataSourceDiscountCodeImportsRead(
+		context.Background(), d, &providerMeta{client: client.WithProjectKey("my-project")})
+	assert.Empty(t, diags)
+
+	assert.Equal(t, "summer-1", d.Get("discount_codes.0.resource_name"))
+	assert.Equal(t, []interface{}{
+		"terraform import commercetools_discount_code.summer-1 id-1",
+		"terraform import commercetools_discount_code.summer-2 id-2",
+	}, d.Get("import_commands"))
+	assert.Equal(t, "import {\n  to = commercetools_discount_code.summer-1\n  id = \"id-1\"\n}\n\n"+
+		"import {\n  to = commercetools_discount_code.summer-2\n  id = \"id-2\"\n}\n", d.Get("import_blocks"))
+}
@@ -0,0 +1,272 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceDiscountCodeSimulation() *schema.Resource {
+	return &schema.Resource{
+		Description: "Simulates which cart discounts of a discount code apply to a cart, to verify the behavior of " +
+			"a promotion. The predicates are evaluated by the provider with a best effort evaluator which " +
+			"supports the common fields (`customerGroup.key`, `totalPrice`, `lineItemExists`, `lineItemCount` " +
+			"and the `sku`, `productId` or `product.id`, `productType.id`, `categories.id`, `quantity` and `price` of line " +
+			"items) and operators. Cart discounts with predicates which can't be evaluated are reported with " +
+			"`evaluated` set to false. The cart is not sent to commercetools.",
+		ReadContext: dataSourceDiscountCodeSimulationRead,
+		Schema: map[string]*schema.Schema{
+			"code": {
+				Description: "The code of the discount code",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"customer_group": {
+				Description: "The key of the customer group of the cart",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"currency_code": {
+				Description:  "The currency of the cart, required to evaluate predicates on prices",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: ValidateCurrencyCode,
+			},
+			"line_item": {
+				Description: "The line items of the cart",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"sku": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"product_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"product_type_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"category_ids": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"quantity": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  1,
+						},
+						"cent_amount": {
+							Description: "The price of a single item in cents",
+							Type:        schema.TypeInt,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"discount_code_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"applicable": {
+				Description: "Whether the discount code can be added to the cart: it is active, valid and its " +
+					"cart predicate matches",
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"reason": {
+				Description: "Why the discount code can't be added to the cart, empty when it is applicable",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"cart_discounts": {
+				Description: "The cart discounts of the discount code",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"key": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     TypeLocalizedString,
+							Computed: true,
+						},
+						"applies": {
+							Description: "Whether the cart discount applies to the cart",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+						"evaluated": {
+							Description: "False when a predicate of the cart discount could not be evaluated",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+						"reason": {
+							Description: "Why the cart discount doesn't apply, empty when it applies",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDiscountCodeSimulationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	code := d.Get("code").(string)
+
+	log.Printf("[DEBUG] Reading discount code from commercetools, with code: %s", code)
+	result, err := client.DiscountCodes().Get().
+		Where([]string{fmt.Sprintf("code = %s", quotePredicateString(code))}).
+		Expand([]string{"cartDiscounts[*]"}).
+		Limit(1).
+		Execute(ctx)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+	if len(result.Results) == 0 {
+		return diag.Errorf("discount code not found")
+	}
+	discountCode := result.Results[0]
+
+	cart := unmarshallPredicateCart(d)
+	applicable, reason, cartDiscounts := simulateDiscountCode(discountCode, cart, time.Now())
+
+	d.SetId(discountCode.ID)
+	d.Set("discount_code_id", discountCode.ID)
+	d.Set("applicable", applicable)
+	d.Set("reason", reason)
+	d.Set("cart_discounts", cartDiscounts)
+	return nil
+}
+
+func unmarshallPredicateCart(d *schema.ResourceData) predicateCart {
+	cart := predicateCart{
+		CustomerGroupKey: d.Get("customer_group").(string),
+		Currency:         d.Get("currency_code").(string),
+	}
+	for _, raw := range d.Get("line_item").([]interface{}) {
+		input := raw.(map[string]interface{})
+		cart.LineItems = append(cart.LineItems, predicateLineItem{
+			SKU:           input["sku"].(string),
+			ProductID:     input["product_id"].(string),
+			ProductTypeID: input["product_type_id"].(string),
+			CategoryIDs:   expandStringArray(input["category_ids"].([]interface{})),
+			Quantity:      input["quantity"].(int),
+			CentAmount:    input["cent_amount"].(int),
+		})
+	}
+	return cart
+}
+
+// simulateDiscountCode returns whether the discount code can be added to the
+// cart and which of its cart discounts apply
+func simulateDiscountCode(discountCode platform.DiscountCode, cart predicateCart, now time.Time) (bool, string, []map[string]interface{}) {
+	applicable, evaluated, reason := true, true, ""
+	switch {
+	case !discountCode.IsActive:
+		applicable, reason = false, "the discount code is not active"
+	case !isValidAt(discountCode.ValidFrom, discountCode.ValidUntil, now):
+		applicable, reason = false, "the discount code is not valid at this time"
+	case discountCode.CartPredicate != nil && *discountCode.CartPredicate != "":
+		matches, err := evaluatePredicate(*discountCode.CartPredicate, cart, nil)
+		if err != nil {
+			applicable, evaluated = false, false
+			reason = fmt.Sprintf("the cart predicate of the discount code can't be evaluated: %s", err)
+		} else if !matches {
+			applicable, reason = false, "the cart predicate of the discount code doesn't match"
+		}
+	}
+
+	result := make([]map[string]interface{}, len(discountCode.CartDiscounts))
+	for i, ref := range discountCode.CartDiscounts {
+		item := map[string]interface{}{
+			"id":        ref.ID,
+			"key":       "",
+			"name":      platform.LocalizedString{},
+			"applies":   false,
+			"evaluated": evaluated,
+			"reason":    reason,
+		}
+		result[i] = item
+
+		if ref.Obj == nil {
+			item["evaluated"] = false
+			item["reason"] = "the cart discount does not exist"
+			continue
+		}
+		if ref.Obj.Key != nil {
+			item["key"] = *ref.Obj.Key
+		}
+		item["name"] = ref.Obj.Name
+		if !applicable {
+			continue
+		}
+
+		applies, evaluated, reason := simulateCartDiscount(*ref.Obj, cart, now)
+		item["applies"] = applies
+		item["evaluated"] = evaluated
+		item["reason"] = reason
+	}
+	return applicable, reason, result
+}
+
+func simulateCartDiscount(cartDiscount platform.CartDiscount, cart predicateCart, now time.Time) (bool, bool, string) {
+	if !cartDiscount.IsActive {
+		return false, true, "the cart discount is not active"
+	}
+	if !isValidAt(cartDiscount.ValidFrom, cartDiscount.ValidUntil, now) {
+		return false, true, "the cart discount is not valid at this time"
+	}
+
+	matches, err := evaluatePredicate(cartDiscount.CartPredicate, cart, nil)
+	if err != nil {
+		return false, false, fmt.Sprintf("the cart predicate can't be evaluated: %s", err)
+	}
+	if !matches {
+		return false, true, "the cart predicate doesn't match"
+	}
+
+	switch target := cartDiscount.Target.(type) {
+	case platform.CartDiscountLineItemsTarget:
+		matches, err := evaluateLineItemPredicate(target.Predicate, cart)
+		if err != nil {
+			return false, false, fmt.Sprintf("the target predicate can't be evaluated: %s", err)
+		}
+		if !matches {
+			return false, true, "no line item matches the target predicate"
+		}
+	case platform.CartDiscountCustomLineItemsTarget:
+		return false, false, "custom line item targets are not simulated"
+	case platform.MultiBuyLineItemsTarget, platform.MultiBuyCustomLineItemsTarget:
+		return false, false, "multi buy targets are not simulated"
+	}
+	return true, true, ""
+}
+
+func isValidAt(validFrom, validUntil *time.Time, now time.Time) bool {
+	if validFrom != nil && now.Before(*validFrom) {
+		return false
+	}
+	if validUntil != nil && now.After(*validUntil) {
+		return false
+	}
+	return true
+}
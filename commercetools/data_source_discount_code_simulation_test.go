@@ -0,0 +1,90 @@
+package commercetools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateDiscountCode(t *testing.T) {
+	now := time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	shoes := stringRef("shoes")
+	predicate := `customerGroup.key = "vip"`
+
+	discountCode := platform.DiscountCode{
+		ID:            "code-1",
+		IsActive:      true,
+		CartPredicate: &predicate,
+		CartDiscounts: []platform.CartDiscountReference{
+			{ID: "cd-1", Obj: &platform.CartDiscount{
+				Key:           shoes,
+				IsActive:      true,
+				CartPredicate: `totalPrice >= "20.00 EUR"`,
+				Target:        platform.CartDiscountLineItemsTarget{Predicate: `categories.id contains "shoes"`},
+			}},
+			{ID: "cd-2", Obj: &platform.CartDiscount{
+				IsActive:      true,
+				CartPredicate: "1 = 1",
+				Target:        platform.CartDiscountLineItemsTarget{Predicate: `sku = "hat-1"`},
+			}},
+			{ID: "cd-3", Obj: &platform.CartDiscount{
+				IsActive:      true,
+				CartPredicate: "1 = 1",
+				ValidUntil:    &past,
+				Target:        platform.CartDiscountShippingCostTarget{},
+			}},
+			{ID: "cd-4", Obj: &platform.CartDiscount{
+				IsActive:      true,
+				CartPredicate: `shippingInfo.shippingMethodName = "Express"`,
+			}},
+			{ID: "cd-5", Obj: &platform.CartDiscount{
+				IsActive:      true,
+				CartPredicate: "1 = 1",
+				Target:        platform.CartDiscountCustomLineItemsTarget{Predicate: "1 = 1"},
+			}},
+			{ID: "cd-6"},
+		},
+	}
+	cart := predicateCart{
+		CustomerGroupKey: "vip",
+		Currency:         "EUR",
+		LineItems: []predicateLineItem{
+			{SKU: "shoe-1", CategoryIDs: []string{"shoes"}, Quantity: 1, CentAmount: 2500},
+		},
+	}
+
+	applicable, reason, result := simulateDiscountCode(discountCode, cart, now)
+	assert.True(t, applicable)
+	assert.Empty(t, reason)
+	assert.Len(t, result, 6)
+
+	assert.Equal(t, "shoes", result[0]["key"])
+	assert.Equal(t, true, result[0]["applies"])
+	assert.Equal(t, true, result[0]["evaluated"])
+
+	assert.Equal(t, false, result[1]["applies"])
+	assert.Equal(t, true, result[1]["evaluated"])
+	assert.Equal(t, "no line item matches the target predicate", result[1]["reason"])
+
+	assert.Equal(t, false, result[2]["applies"])
+	assert.Equal(t, "the cart discount is not valid at this time", result[2]["reason"])
+
+	assert.Equal(t, false, result[3]["evaluated"])
+	assert.Equal(t, false, result[4]["evaluated"])
+	assert.Equal(t, "the cart discount does not exist", result[5]["reason"])
+
+	cart.CustomerGroupKey = "regular"
+	applicable, reason, result = simulateDiscountCode(discountCode, cart, now)
+	assert.False(t, applicable)
+	assert.Equal(t, "the cart predicate of the discount code doesn't match", reason)
+	assert.Equal(t, false, result[0]["applies"])
+	assert.Equal(t, reason, result[0]["reason"])
+
+	discountCode.ValidFrom = &now
+	applicable, reason, _ = simulateDiscountCode(discountCode, cart, past)
+	assert.False(t, applicable)
+	assert.Equal(t, "the discount code is not valid at this time", reason)
+}
@@ -0,0 +1,79 @@
+package commercetools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceDiscountCode_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDiscountCodeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDiscountCodeConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_discount_code.spring", "id",
+						"commercetools_discount_code.spring", "id",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_discount_code.summer", "name.en", "Spring sale",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_discount_code.summer", "max_applications", "100",
+					),
+					resource.TestCheckResourceAttrPair(
+						"commercetools_discount_code.summer", "cart_discounts.0",
+						"commercetools_cart_discount.spring", "id",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceDiscountCodeConfig() string {
+	return `
+resource "commercetools_cart_discount" "spring" {
+	name = {
+		en = "Spring"
+	}
+	sort_order             = "0.6543"
+	predicate              = "1=1"
+	requires_discount_code = true
+
+	target {
+		type      = "lineItems"
+		predicate = "1=1"
+	}
+
+	value {
+		type      = "relative"
+		permyriad = 1000
+	}
+}
+
+resource "commercetools_discount_code" "spring" {
+	code = "SPRING"
+	name = {
+		en = "Spring sale"
+	}
+	max_applications = 100
+	cart_discounts   = [commercetools_cart_discount.spring.id]
+}
+
+data "commercetools_discount_code" "spring" {
+	code = commercetools_discount_code.spring.code
+}
+
+resource "commercetools_discount_code" "summer" {
+	code             = "SUMMER"
+	name             = data.commercetools_discount_code.spring.name
+	max_applications = data.commercetools_discount_code.spring.max_applications
+	cart_discounts   = data.commercetools_discount_code.spring.cart_discounts
+}
+`
+}
@@ -0,0 +1,447 @@
+package commercetools
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceDiscountCodes() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the discount codes in a group or with a name, for example to report on the codes of a " +
+			"campaign. The `csv` attribute can be written to a file with the `local_file` resource to distribute the codes.\n\n" +
+			"See also the [Discount Code Api Documentation](https://docs.commercetools.com/api/projects/discountCodes)",
+		ReadContext: dataSourceDiscountCodesRead,
+		Schema: map[string]*schema.Schema{
+			"group": {
+				Description:  "The group of the discount codes",
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"group", "name"},
+			},
+			"name": {
+				Description: "Only return the discount codes of which the name in the given `locale` contains " +
+					"this value, ignoring case",
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"group", "name"},
+				RequiredWith: []string{"locale"},
+			},
+			"locale": {
+				Description:  "The locale of the `name`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"name"},
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile("^[a-z]{2}(-[A-Z]{2})?$"),
+					"Locales must match pattern ^[a-z]{2}(-[A-Z]{2})?$",
+				),
+			},
+			"active_only": {
+				Description: "Only return the discount codes which are active",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"discount_codes": {
+				Description: "The matching discount codes, ordered by code",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"code": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     TypeLocalizedString,
+							Computed: true,
+						},
+						"is_active": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"max_applications": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"valid_from": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"valid_until": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"check_overlaps": {
+				Description: "Compute `overlaps` and `overlap_count`, they are empty by default",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"fail_on_overlap": {
+				Description: "Fail when active discount codes overlap, to enforce one active code per group at a " +
+					"time. Implies `check_overlaps`",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"overlap_count": {
+				Description: "The number of pairs of active discount codes of which the validity windows overlap",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"overlaps": {
+				Description: fmt.Sprintf("The pairs of active discount codes of which the validity windows overlap, "+
+					"ordered by code. At most %d pairs are returned, `overlap_count` has the total. A missing "+
+					"`valid_from` or `valid_until` counts as an unlimited window", maxDiscountCodeOverlaps),
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"codes": {
+							Description: "The two overlapping codes",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"valid_from": {
+							Description: "The start of the overlap, empty when it has no start",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"valid_until": {
+							Description: "The end of the overlap, empty when it has no end",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"csv": {
+				Description: "The discount codes as CSV with a header and the columns code, valid_from, " +
+					"valid_until and is_active",
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDiscountCodesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	group := d.Get("group").(string)
+	name := d.Get("name").(string)
+	locale := d.Get("locale").(string)
+	activeOnly := d.Get("active_only").(bool)
+
+	var where, id []string
+	if group != "" {
+		where = append(where, discountCodeGroupPredicate(group))
+		id = append(id, fmt.Sprintf("group=%s", group))
+	}
+	if name != "" {
+		// Query predicates can't match a part of a string, so only the codes
+		// with a name in the locale are queried and filtered afterwards
+		where = append(where, discountCodeNamePredicate(locale))
+		id = append(id, fmt.Sprintf("name=%s", name), fmt.Sprintf("locale=%s", locale))
+	}
+	if activeOnly {
+		where = append(where, "isActive = true")
+		id = append(id, "active_only")
+	}
+	discountCodes, err := queryDiscountCodes(ctx, client, where)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+	if name != "" {
+		discountCodes = filterDiscountCodesByName(discountCodes, locale, name)
+	}
+	csvContent, err := marshallDiscountCodesCSV(discountCodes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result := make([]map[string]interface{}, len(discountCodes))
+	for i, discountCode := range discountCodes {
+		maxApplications := 0
+		if discountCode.MaxApplications != nil {
+			maxApplications = *discountCode.MaxApplications
+		}
+		localizedName := platform.LocalizedString{}
+		if discountCode.Name != nil {
+			localizedName = *discountCode.Name
+		}
+		result[i] = map[string]interface{}{
+			"id":               discountCode.ID,
+			"code":             discountCode.Code,
+			"name":             localizedName,
+			"is_active":        discountCode.IsActive,
+			"max_applications": maxApplications,
+			"valid_from":       marshallTime(discountCode.ValidFrom),
+			"valid_until":      marshallTime(discountCode.ValidUntil),
+		}
+	}
+
+	var overlaps []discountCodeOverlap
+	var overlapCount int
+	failOnOverlap := d.Get("fail_on_overlap").(bool)
+	if failOnOverlap || d.Get("check_overlaps").(bool) {
+		overlaps, overlapCount = discountCodeOverlaps(discountCodes)
+	}
+
+	d.SetId(strings.Join(id, ","))
+	d.Set("discount_codes", result)
+	d.Set("overlaps", marshallDiscountCodeOverlaps(overlaps))
+	d.Set("overlap_count", overlapCount)
+	d.Set("csv", csvContent)
+
+	if failOnOverlap && overlapCount > 0 {
+		return discountCodeOverlapDiagnostics(overlaps, overlapCount)
+	}
+	return nil
+}
+
+// discountCodeOverlap is a pair of discount codes which are valid at the same
+// time. A nil time means the overlap has no start or end.
+type discountCodeOverlap struct {
+	codes      [2]string
+	validFrom  *time.Time
+	validUntil *time.Time
+}
+
+// maxDiscountCodeOverlaps caps the number of overlaps which are returned,
+// since every pair of a group of codes without validity windows overlaps
+const maxDiscountCodeOverlaps = 100
+
+// discountCodeOverlaps returns the pairs of active discount codes of which the
+// validity windows overlap, at most maxDiscountCodeOverlaps ordered by code,
+// and the total number of overlapping pairs. The codes are swept in the order
+// of their start, with a heap of the codes which are still valid at that
+// start, so the pairs which are not returned are only counted.
+func discountCodeOverlaps(discountCodes []platform.DiscountCode) ([]discountCodeOverlap, int) {
+	var active []platform.DiscountCode
+	for _, discountCode := range discountCodes {
+		if discountCode.IsActive {
+			active = append(active, discountCode)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return startsBefore(active[i].ValidFrom, active[j].ValidFrom)
+	})
+
+	var result []discountCodeOverlap
+	total := 0
+	valid := &discountCodesByEnd{}
+	for _, b := range active {
+		for valid.Len() > 0 && b.ValidFrom != nil {
+			until := (*valid)[0].ValidUntil
+			if until == nil || b.ValidFrom.Before(*until) {
+				break
+			}
+			heap.Pop(valid)
+		}
+
+		total += valid.Len()
+		for _, a := range *valid {
+			if len(result) == maxDiscountCodeOverlaps {
+				break
+			}
+			codes := [2]string{a.Code, b.Code}
+			if codes[1] < codes[0] {
+				codes[0], codes[1] = codes[1], codes[0]
+			}
+			result = append(result, discountCodeOverlap{
+				codes:      codes,
+				validFrom:  laterTime(a.ValidFrom, b.ValidFrom),
+				validUntil: earlierTime(a.ValidUntil, b.ValidUntil),
+			})
+		}
+		heap.Push(valid, b)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].codes[0] != result[j].codes[0] {
+			return result[i].codes[0] < result[j].codes[0]
+		}
+		return result[i].codes[1] < result[j].codes[1]
+	})
+	return result, total
+}
+
+// discountCodesByEnd is a heap of discount codes with the code which is valid
+// for the shortest time first, codes without an end come last
+type discountCodesByEnd []platform.DiscountCode
+
+func (h discountCodesByEnd) Len() int { return len(h) }
+
+func (h discountCodesByEnd) Less(i, j int) bool {
+	a, b := h[i].ValidUntil, h[j].ValidUntil
+	return a != nil && (b == nil || a.Before(*b))
+}
+
+func (h discountCodesByEnd) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *discountCodesByEnd) Push(x interface{}) {
+	*h = append(*h, x.(platform.DiscountCode))
+}
+
+func (h *discountCodesByEnd) Pop() interface{} {
+	old := *h
+	result := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return result
+}
+
+// startsBefore returns whether start time a is before b, nil means no start
+func startsBefore(a, b *time.Time) bool {
+	return b != nil && (a == nil || a.Before(*b))
+}
+
+// laterTime returns the later of the two start times, nil means no start
+func laterTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+// earlierTime returns the earlier of the two end times, nil means no end
+func earlierTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+func marshallDiscountCodeOverlaps(overlaps []discountCodeOverlap) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(overlaps))
+	for i, overlap := range overlaps {
+		result[i] = map[string]interface{}{
+			"codes":       []string{overlap.codes[0], overlap.codes[1]},
+			"valid_from":  marshallTime(overlap.validFrom),
+			"valid_until": marshallTime(overlap.validUntil),
+		}
+	}
+	return result
+}
+
+func discountCodeOverlapDiagnostics(overlaps []discountCodeOverlap, total int) diag.Diagnostics {
+	lines := make([]string, len(overlaps), len(overlaps)+1)
+	for i, overlap := range overlaps {
+		from, until := marshallTime(overlap.validFrom), marshallTime(overlap.validUntil)
+		window := "at any time"
+		switch {
+		case from != "" && until != "":
+			window = fmt.Sprintf("from %s until %s", from, until)
+		case from != "":
+			window = fmt.Sprintf("from %s on", from)
+		case until != "":
+			window = fmt.Sprintf("until %s", until)
+		}
+		lines[i] = fmt.Sprintf("%s and %s are both valid %s", overlap.codes[0], overlap.codes[1], window)
+	}
+	if total > len(overlaps) {
+		lines = append(lines, fmt.Sprintf("and %d more pair(s)", total-len(overlaps)))
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("%d pair(s) of active discount codes have overlapping validity windows", total),
+		Detail:   strings.Join(lines, "\n"),
+	}}
+}
+
+func marshallDiscountCodesCSV(discountCodes []platform.DiscountCode) (string, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"code", "valid_from", "valid_until", "is_active"})
+	for _, discountCode := range discountCodes {
+		writer.Write([]string{
+			discountCode.Code,
+			marshallTime(discountCode.ValidFrom),
+			marshallTime(discountCode.ValidUntil),
+			strconv.FormatBool(discountCode.IsActive),
+		})
+	}
+	writer.Flush()
+	return buf.String(), writer.Error()
+}
+
+// filterDiscountCodesByName returns the discount codes of which the name in
+// the locale contains the value, ignoring case
+func filterDiscountCodesByName(discountCodes []platform.DiscountCode, locale, value string) []platform.DiscountCode {
+	value = strings.ToLower(value)
+	result := []platform.DiscountCode{}
+	for _, discountCode := range discountCodes {
+		if discountCode.Name == nil {
+			continue
+		}
+		name, ok := (*discountCode.Name)[locale]
+		if ok && strings.Contains(strings.ToLower(name), value) {
+			result = append(result, discountCode)
+		}
+	}
+	return result
+}
+
+// discountCodeGroupPredicate returns a query predicate matching the discount
+// codes in the given group
+func discountCodeGroupPredicate(group string) string {
+	return fmt.Sprintf("groups contains any (%s)", quotePredicateString(group))
+}
+
+// discountCodeNamePredicate returns a query predicate matching the discount
+// codes with a name in the given locale
+func discountCodeNamePredicate(locale string) string {
+	return fmt.Sprintf("name(`%s` is defined)", locale)
+}
+
+// queryDiscountCodes returns all discount codes matching the predicates,
+// ordered by code
+func queryDiscountCodes(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, where []string) ([]platform.DiscountCode, error) {
+	var result []platform.DiscountCode
+	lastID := ""
+	for {
+		request := client.DiscountCodes().Get().
+			Sort([]string{"id asc"}).
+			Limit(queryPageSize).
+			WithTotal(false)
+		if predicates := queryPagePredicates(where, lastID); len(predicates) > 0 {
+			request = request.Where(predicates)
+		}
+		response, err := request.Execute(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, response.Results...)
+		if response.Count < queryPageSize || len(response.Results) == 0 {
+			break
+		}
+		lastID = response.Results[len(response.Results)-1].ID
+	}
+
+	// The pages are sorted by ID
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+	return result, nil
+}
@@ -0,0 +1,276 @@
+package commercetools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryDiscountCodesPagination(t *testing.T) {
+	// More codes than the offset limit of commercetools, with codes which are
+	// not in the order of the IDs
+	total := 10003
+	ids := make([]string, total)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("id-%05d", i)
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()
+		assert.Empty(t, query.Get("offset"))
+		assert.Equal(t, "id asc", query.Get("sort"))
+		where := query["where"]
+		assert.Equal(t, `groups contains any ("summer \"21\"")`, where[0])
+
+		start := 0
+		if len(where) > 1 {
+			var lastID string
+			_, err := fmt.Sscanf(where[1], "id > %q", &lastID)
+			assert.NoError(t, err)
+			start = sort.SearchStrings(ids, lastID) + 1
+		}
+		results := []map[string]interface{}{}
+		for i := start; i < total && i < start+queryPageSize; i++ {
+			results = append(results, map[string]interface{}{
+				"id":   ids[i],
+				"code": fmt.Sprintf("CODE-%05d", total-i),
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count":   len(results),
+			"results": results,
+		})
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+
+	discountCodes, err := queryDiscountCodes(
+		context.Background(), client.WithProjectKey("my-project"),
+		[]string{discountCodeGroupPredicate(`summer "21"`)})
+	assert.NoError(t, err)
+	assert.Len(t, discountCodes, total)
+	assert.Equal(t, 21, requests)
+	assert.Equal(t, "CODE-00001", discountCodes[0].Code)
+	assert.Equal(t, "CODE-10003", discountCodes[total-1].Code)
+}
+
+func TestFilterDiscountCodesByName(t *testing.T) {
+	result := filterDiscountCodesByName([]platform.DiscountCode{
+		{Code: "SUMMER-1", Name: &platform.LocalizedString{"en": "Summer Sale 2021", "de": "Sommerschlussverkauf"}},
+		{Code: "SUMMER-2", Name: &platform.LocalizedString{"de": "Summer Sale"}},
+		{Code: "WINTER-1", Name: &platform.LocalizedString{"en": "Winter Sale"}},
+		{Code: "OTHER"},
+	}, "en", "summer sale")
+
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "SUMMER-1", result[0].Code)
+	}
+}
+
+func TestDiscountCodeNamePredicate(t *testing.T) {
+	assert.Equal(t, "name(`en` is defined)", discountCodeNamePredicate("en"))
+	assert.Equal(t, "name(`de-DE` is defined)", discountCodeNamePredicate("de-DE"))
+}
+
+func TestMarshallDiscountCodesCSV(t *testing.T) {
+	validFrom := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	result, err := marshallDiscountCodesCSV([]platform.DiscountCode{
+		{Code: "SUMMER-1", IsActive: true, ValidFrom: &validFrom},
+		{Code: "SUMMER,2", IsActive: false},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "code,valid_from,valid_until,is_active\n"+
+		"SUMMER-1,2021-06-01T00:00:00Z,,true\n"+
+		"\"SUMMER,2\",,,false\n", result)
+}
+
+func TestDiscountCodeOverlaps(t *testing.T) {
+	date := func(day int) *time.Time {
+		result := time.Date(2021, 6, day, 0, 0, 0, 0, time.UTC)
+		return &result
+	}
+	overlaps, total := discountCodeOverlaps([]platform.DiscountCode{
+		{Code: "JUNE-1", IsActive: true, ValidFrom: date(1), ValidUntil: date(10)},
+		{Code: "JUNE-2", IsActive: true, ValidFrom: date(10), ValidUntil: date(20)},
+		{Code: "JUNE-3", IsActive: true, ValidFrom: date(15)},
+		{Code: "JUNE-4", IsActive: false, ValidFrom: date(1), ValidUntil: date(30)},
+		{Code: "OPEN", IsActive: true, ValidUntil: date(5)},
+	})
+
+	assert.Equal(t, []discountCodeOverlap{
+		{codes: [2]string{"JUNE-1", "OPEN"}, validFrom: date(1), validUntil: date(5)},
+		{codes: [2]string{"JUNE-2", "JUNE-3"}, validFrom: date(15), validUntil: date(20)},
+	}, overlaps)
+	assert.Equal(t, 2, total)
+
+	diags := discountCodeOverlapDiagnostics(overlaps, total)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "2 pair(s) of active discount codes have overlapping validity windows", diags[0].Summary)
+		assert.Equal(t,
+			"JUNE-1 and OPEN are both valid from 2021-06-01T00:00:00Z until 2021-06-05T00:00:00Z\n"+
+				"JUNE-2 and JUNE-3 are both valid from 2021-06-15T00:00:00Z until 2021-06-20T00:00:00Z",
+			diags[0].Detail)
+	}
+
+	overlaps, total = discountCodeOverlaps([]platform.DiscountCode{
+		{Code: "A", IsActive: true, ValidUntil: date(10)},
+		{Code: "B", IsActive: true, ValidFrom: date(10)},
+	})
+	assert.Empty(t, overlaps)
+	assert.Equal(t, 0, total)
+
+	overlaps, total = discountCodeOverlaps([]platform.DiscountCode{
+		{Code: "B", IsActive: true},
+		{Code: "A", IsActive: true},
+	})
+	assert.Equal(t, []discountCodeOverlap{{codes: [2]string{"A", "B"}}}, overlaps)
+	assert.Equal(t, 1, total)
+}
+
+func TestDiscountCodeOverlapsLimit(t *testing.T) {
+	// Every pair of codes without a validity window overlaps, only the first
+	// pairs are returned and the rest is counted
+	discountCodes := make([]platform.DiscountCode, 2000)
+	for i := range discountCodes {
+		discountCodes[i] = platform.DiscountCode{Code: fmt.Sprintf("CODE-%04d", i), IsActive: true}
+	}
+	overlaps, total := discountCodeOverlaps(discountCodes)
+	assert.Len(t, overlaps, maxDiscountCodeOverlaps)
+	assert.Equal(t, 2000*1999/2, total)
+	assert.Equal(t, [2]string{"CODE-0000", "CODE-0001"}, overlaps[0].codes)
+
+	diags := discountCodeOverlapDiagnostics(overlaps, total)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "1999000 pair(s) of active discount codes have overlapping validity windows", diags[0].Summary)
+		assert.Contains(t, diags[0].Detail, fmt.Sprintf("\nand %d more pair(s)", total-maxDiscountCodeOverlaps))
+	}
+}
+
+func TestAccDataSourceDiscountCodes_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDiscountCodeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDiscountCodesConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.commercetools_discount_codes.campaign", "discount_codes.#", "2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_discount_codes.campaign", "discount_codes.0.code", "CAMPAIGN-1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_discount_codes.campaign", "discount_codes.1.max_applications", "10",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_discount_codes.active", "discount_codes.#", "1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_discount_codes.active", "csv",
+						"code,valid_from,valid_until,is_active\nCAMPAIGN-2,,,true\n",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_discount_codes.named", "discount_codes.#", "1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_discount_codes.named", "discount_codes.0.name.en", "Big Campaign",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceDiscountCodesConfig() string {
+	return `
+resource "commercetools_cart_discount" "campaign" {
+	name = {
+		en = "Campaign"
+	}
+	sort_order             = "0.6544"
+	predicate              = "1=1"
+	requires_discount_code = true
+
+	target {
+		type      = "lineItems"
+		predicate = "1=1"
+	}
+
+	value {
+		type      = "relative"
+		permyriad = 1000
+	}
+}
+
+resource "commercetools_discount_code" "campaign_1" {
+	code           = "CAMPAIGN-1"
+	is_active      = false
+	groups         = ["campaign"]
+	cart_discounts = [commercetools_cart_discount.campaign.id]
+}
+
+resource "commercetools_discount_code" "campaign_2" {
+	code             = "CAMPAIGN-2"
+	name = {
+		en = "Big Campaign"
+	}
+	groups           = ["campaign"]
+	max_applications = 10
+	cart_discounts   = [commercetools_cart_discount.campaign.id]
+}
+
+resource "commercetools_discount_code" "other" {
+	code           = "OTHER"
+	cart_discounts = [commercetools_cart_discount.campaign.id]
+}
+
+data "commercetools_discount_codes" "campaign" {
+	group = "campaign"
+
+	depends_on = [
+		commercetools_discount_code.campaign_1,
+		commercetools_discount_code.campaign_2,
+		commercetools_discount_code.other,
+	]
+}
+
+data "commercetools_discount_codes" "active" {
+	group       = "campaign"
+	active_only = true
+
+	depends_on = [
+		commercetools_discount_code.campaign_1,
+		commercetools_discount_code.campaign_2,
+	]
+}
+
+data "commercetools_discount_codes" "named" {
+	name   = "campaign"
+	locale = "en"
+
+	depends_on = [
+		commercetools_discount_code.campaign_1,
+		commercetools_discount_code.campaign_2,
+		commercetools_discount_code.other,
+	]
+}
+`
+}
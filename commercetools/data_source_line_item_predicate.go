@@ -0,0 +1,122 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceLineItemPredicate() *schema.Resource {
+	return &schema.Resource{
+		Description: "Builds a [line item predicate](https://docs.commercetools.com/api/projects/predicates#lineitem-field-identifiers) " +
+			"for the `target` of a cart discount from common fragments. The values are quoted and escaped, so SKUs " +
+			"and IDs with quotes or other special characters result in a valid predicate. This data source " +
+			"doesn't call the API.",
+		ReadContext: dataSourceLineItemPredicateRead,
+		Schema: map[string]*schema.Schema{
+			"skus": {
+				Description: "Match line items with one of these SKUs",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"product_ids": {
+				Description: "Match line items of one of these products",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"product_type_ids": {
+				Description: "Match line items of products with one of these product types",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"category_ids": {
+				Description: "Match line items of products in at least one of these categories",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"operator": {
+				Description:  "How the fragments are combined, either `and` or `or`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "and",
+				ValidateFunc: validation.StringInSlice([]string{"and", "or"}, false),
+			},
+			"predicate": {
+				Description: "The resulting predicate, `1 = 1` when no fragments are given",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceLineItemPredicateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	predicate := buildLineItemPredicate(
+		d.Get("operator").(string),
+		lineItemPredicateFragment("sku", expandStringArray(d.Get("skus").([]interface{}))),
+		lineItemPredicateFragment("product.id", expandStringArray(d.Get("product_ids").([]interface{}))),
+		lineItemPredicateFragment("productType.id", expandStringArray(d.Get("product_type_ids").([]interface{}))),
+		categoriesPredicateFragment(expandStringArray(d.Get("category_ids").([]interface{}))),
+	)
+
+	d.SetId(fmt.Sprintf("%d", schema.HashString(predicate)))
+	d.Set("predicate", predicate)
+	return nil
+}
+
+// buildLineItemPredicate combines the non-empty fragments with the operator
+func buildLineItemPredicate(operator string, fragments ...string) string {
+	parts := []string{}
+	for _, fragment := range fragments {
+		if fragment != "" {
+			parts = append(parts, fragment)
+		}
+	}
+	if len(parts) == 0 {
+		return "1 = 1"
+	}
+	return strings.Join(parts, fmt.Sprintf(" %s ", operator))
+}
+
+// lineItemPredicateFragment compares the field with a single value or checks
+// if it is one of multiple values
+func lineItemPredicateFragment(field string, values []string) string {
+	switch len(values) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("%s = %s", field, quotePredicateString(values[0]))
+	}
+	return fmt.Sprintf("%s in (%s)", field, quotePredicateStrings(values))
+}
+
+func categoriesPredicateFragment(ids []string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("categories.id contains any (%s)", quotePredicateStrings(ids))
+}
+
+// quotePredicateString returns the value as a string literal for use in a
+// predicate, escaping backslashes and double quotes
+func quotePredicateString(value string) string {
+	escaped := strings.ReplaceAll(value, `\`, `\\`)
+	escaped = strings.ReplaceAll(escaped, `"`, `\"`)
+	return fmt.Sprintf(`"%s"`, escaped)
+}
+
+func quotePredicateStrings(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quotePredicateString(value)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,54 @@
+package commercetools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotePredicateString(t *testing.T) {
+	assert.Equal(t, `"sku-1"`, quotePredicateString("sku-1"))
+	assert.Equal(t, `"say \"hi\""`, quotePredicateString(`say "hi"`))
+	assert.Equal(t, `"back\\slash"`, quotePredicateString(`back\slash`))
+	assert.Equal(t, `"\\\""`, quotePredicateString(`\"`))
+	assert.Equal(t, `"größe (XL) & más"`, quotePredicateString("größe (XL) & más"))
+}
+
+func TestBuildLineItemPredicate(t *testing.T) {
+	assert.Equal(t, "1 = 1", buildLineItemPredicate("and"))
+	assert.Equal(t, `sku = "sku-1"`, buildLineItemPredicate("and",
+		lineItemPredicateFragment("sku", []string{"sku-1"}),
+		lineItemPredicateFragment("productType.id", nil),
+	))
+	assert.Equal(t,
+		`sku in ("sku \"1\"", "sku, 2") or productType.id = "type-1"`,
+		buildLineItemPredicate("or",
+			lineItemPredicateFragment("sku", []string{`sku "1"`, "sku, 2"}),
+			lineItemPredicateFragment("productType.id", []string{"type-1"}),
+		))
+	assert.Equal(t,
+		`categories.id contains any ("cat-1", "cat-2")`,
+		buildLineItemPredicate("and", categoriesPredicateFragment([]string{"cat-1", "cat-2"})))
+}
+
+func TestAccDataSourceLineItemPredicate_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "commercetools_line_item_predicate" "shirts" {
+	skus             = ["shirt-\"s\"", "shirt-m"]
+	product_type_ids = ["type-1"]
+}
+`,
+				Check: resource.TestCheckResourceAttr(
+					"data.commercetools_line_item_predicate.shirts", "predicate",
+					`sku in ("shirt-\"s\"", "shirt-m") and productType.id = "type-1"`,
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,99 @@
+package commercetools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"golang.org/x/oauth2"
+)
+
+func dataSourceProjectHealth() *schema.Resource {
+	return &schema.Resource{
+		Description: "Checks the connection to commercetools by requesting a token and reading the project, so " +
+			"wrong credentials or scopes fail a run before any resource is changed. Reading the project requires " +
+			"the `view_project_settings` or `manage_project` scope.",
+		ReadContext: dataSourceProjectHealthRead,
+		Schema: map[string]*schema.Schema{
+			"reachable": {
+				Description: "Whether the project could be read, always true since the data source fails otherwise",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"scopes": {
+				Description: "The scopes granted to the token of the provider, sorted",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceProjectHealthRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	meta := getProviderMeta(m)
+
+	log.Print("[DEBUG] Requesting a token from commercetools")
+	token, err := meta.tokenSource.Token()
+	if err != nil {
+		return projectHealthDiagnostics(meta.projectKey, err)
+	}
+
+	log.Printf("[DEBUG] Reading project from commercetools, with key: %s", meta.projectKey)
+	if _, err := meta.client.Get().Execute(ctx); err != nil {
+		return projectHealthDiagnostics(meta.projectKey, err)
+	}
+
+	d.SetId(meta.projectKey)
+	d.Set("reachable", true)
+	d.Set("scopes", tokenScopes(token))
+	return nil
+}
+
+// tokenScopes returns the scopes of the token response, which commercetools
+// returns as a space separated list
+func tokenScopes(token *oauth2.Token) []string {
+	scope, _ := token.Extra("scope").(string)
+	scopes := strings.Fields(scope)
+	sort.Strings(scopes)
+	return scopes
+}
+
+// projectHealthDiagnostics explains the errors of a failed connection check
+// with the provider settings which are most likely wrong
+func projectHealthDiagnostics(projectKey string, err error) diag.Diagnostics {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Could not authenticate with commercetools",
+			Detail: fmt.Sprintf(
+				"The token request failed with HTTP status %d: %s\n\nCheck the client_id, client_secret, "+
+					"scopes and token_url of the provider.",
+				retrieveErr.Response.StatusCode, strings.TrimSpace(string(retrieveErr.Body))),
+		}}
+	}
+
+	var ctErr platform.ErrorResponse
+	if errors.As(err, &ctErr) && (ctErr.StatusCode == 401 || ctErr.StatusCode == 403) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Not allowed to read the commercetools project %q", projectKey),
+			Detail: fmt.Sprintf(
+				"%s\n\nHTTP status: %d\n\nThe scopes of the provider need to include view_project_settings "+
+					"or manage_project for the project.",
+				ctErr.Message, ctErr.StatusCode),
+		}}
+	}
+
+	if isNotFoundError(err) {
+		return diag.Errorf("project %q not found, check the project_key and api_url of the provider", projectKey)
+	}
+	return diagnosticsFromError(err)
+}
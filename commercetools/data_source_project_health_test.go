@@ -0,0 +1,98 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+func TestDataSourceProjectHealthRead(t *testing.T) {
+	cases := []struct {
+		name          string
+		tokenStatus   int
+		projectStatus int
+		summary       string
+	}{
+		{"reachable", http.StatusOK, http.StatusOK, ""},
+		{"invalid credentials", http.StatusUnauthorized, http.StatusOK, "Could not authenticate with commercetools"},
+		{"missing scope", http.StatusOK, http.StatusForbidden, `Not allowed to read the commercetools project "my-project"`},
+		{"unknown project", http.StatusOK, http.StatusNotFound, `project "my-project" not found, check the project_key and api_url of the provider`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/oauth/token":
+					w.WriteHeader(c.tokenStatus)
+					if c.tokenStatus != http.StatusOK {
+						w.Write([]byte(`{"error": "invalid_client"}`))
+						return
+					}
+					w.Write([]byte(`{"access_token": "token", "token_type": "Bearer", "expires_in": 172800, ` +
+						`"scope": "view_products:my-project manage_discount_codes:my-project"}`))
+				case "/my-project":
+					w.WriteHeader(c.projectStatus)
+					if c.projectStatus != http.StatusOK {
+						fmt.Fprintf(w, `{"statusCode": %d, "message": "Insufficient scope."}`, c.projectStatus)
+						return
+					}
+					w.Write([]byte(`{"key": "my-project"}`))
+				}
+			}))
+			defer server.Close()
+
+			client, err := platform.NewClient(&platform.ClientConfig{
+				URL:        server.URL,
+				HTTPClient: server.Client(),
+			})
+			assert.NoError(t, err)
+			credentials := &clientcredentials.Config{TokenURL: server.URL + "/oauth/token"}
+			meta := &providerMeta{
+				client:     client.WithProjectKey("my-project"),
+				projectKey: "my-project",
+				tokenSource: credentials.TokenSource(
+					context.WithValue(context.Background(), oauth2.HTTPClient, server.Client())),
+			}
+
+			d := schema.TestResourceDataRaw(t, dataSourceProjectHealth().Schema, map[string]interface{}{})
+			diags := dataSourceProjectHealthRead(context.Background(), d, meta)
+			if c.summary == "" {
+				assert.False(t, diags.HasError())
+				assert.Equal(t, true, d.Get("reachable"))
+				assert.Equal(t, []interface{}{"manage_discount_codes:my-project", "view_products:my-project"}, d.Get("scopes"))
+				return
+			}
+			if assert.Len(t, diags, 1) {
+				assert.Equal(t, c.summary, diags[0].Summary)
+			}
+		})
+	}
+}
+
+func TestAccDataSourceProjectHealth_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "commercetools_project_health" "check" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.commercetools_project_health.check", "reachable", "true",
+					),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,163 @@
+package commercetools
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceShippingMethod() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches an existing shipping method by its ID or key, for example to reference a centrally " +
+			"managed shipping method in the predicate of a cart discount.\n\n" +
+			"See also the [Shipping Methods API Documentation](https://docs.commercetools.com/api/projects/shippingMethods)",
+		ReadContext: dataSourceShippingMethodRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description:  "The ID of the shipping method",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"key": {
+				Description:  "User-specific unique identifier for the shipping method",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_default": {
+				Description: "One shipping method in a project can be default",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"tax_category_id": {
+				Description: "ID of the [Tax Category](https://docs.commercetools.com/api/projects/taxCategories#taxcategory)",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"predicate": {
+				Description: "A Cart predicate which can be used to more precisely select a shipping method for a cart",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"zone_rates": {
+				Description: "The shipping rates of the shipping method per shipping zone",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"shipping_zone_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"shipping_rates": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"price": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem:     moneyComputedSchema(),
+									},
+									"free_above": {
+										Description: "The shipping is free if the sum of line item prices exceeds this value",
+										Type:        schema.TypeList,
+										Computed:    true,
+										Elem:        moneyComputedSchema(),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func moneyComputedSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"currency_code": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"cent_amount": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceShippingMethodRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+
+	var shippingMethod *platform.ShippingMethod
+	var err error
+	if id := d.Get("id").(string); id != "" {
+		log.Printf("[DEBUG] Reading shipping method from commercetools, with id: %s", id)
+		shippingMethod, err = client.ShippingMethods().WithId(id).Get().Execute(ctx)
+	} else {
+		key := d.Get("key").(string)
+		log.Printf("[DEBUG] Reading shipping method from commercetools, with key: %s", key)
+		shippingMethod, err = client.ShippingMethods().WithKey(key).Get().Execute(ctx)
+	}
+	if err != nil {
+		if isNotFoundError(err) {
+			return diag.Errorf("shipping method not found")
+		}
+		return diagnosticsFromError(err)
+	}
+
+	d.SetId(shippingMethod.ID)
+	d.Set("key", shippingMethod.Key)
+	d.Set("name", shippingMethod.Name)
+	d.Set("description", shippingMethod.Description)
+	d.Set("is_default", shippingMethod.IsDefault)
+	d.Set("tax_category_id", shippingMethod.TaxCategory.ID)
+	d.Set("predicate", shippingMethod.Predicate)
+	d.Set("zone_rates", marshallShippingMethodZoneRates(shippingMethod.ZoneRates))
+	d.Set("version", shippingMethod.Version)
+	return nil
+}
+
+func marshallShippingMethodZoneRates(zoneRates []platform.ZoneRate) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(zoneRates))
+	for i, zoneRate := range zoneRates {
+		shippingRates := make([]map[string]interface{}, len(zoneRate.ShippingRates))
+		for j, shippingRate := range zoneRate.ShippingRates {
+			item := map[string]interface{}{
+				"price":      []map[string]interface{}{marshallTypedMoney(shippingRate.Price)},
+				"free_above": []map[string]interface{}{},
+			}
+			if shippingRate.FreeAbove != nil {
+				item["free_above"] = []map[string]interface{}{marshallTypedMoney(shippingRate.FreeAbove)}
+			}
+			shippingRates[j] = item
+		}
+		result[i] = map[string]interface{}{
+			"shipping_zone_id": zoneRate.Zone.ID,
+			"shipping_rates":   shippingRates,
+		}
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package commercetools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshallShippingMethodZoneRates(t *testing.T) {
+	zoneRates := []platform.ZoneRate{
+		{
+			Zone: platform.ZoneReference{ID: "zone-de"},
+			ShippingRates: []platform.ShippingRate{
+				{
+					Price:     platform.CentPrecisionMoney{CurrencyCode: "EUR", CentAmount: 500},
+					FreeAbove: platform.CentPrecisionMoney{CurrencyCode: "EUR", CentAmount: 5000},
+				},
+				{
+					Price: platform.CentPrecisionMoney{CurrencyCode: "USD", CentAmount: 700},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"shipping_zone_id": "zone-de",
+			"shipping_rates": []map[string]interface{}{
+				{
+					"price":      []map[string]interface{}{{"currency_code": "EUR", "cent_amount": 500}},
+					"free_above": []map[string]interface{}{{"currency_code": "EUR", "cent_amount": 5000}},
+				},
+				{
+					"price":      []map[string]interface{}{{"currency_code": "USD", "cent_amount": 700}},
+					"free_above": []map[string]interface{}{},
+				},
+			},
+		},
+	}, marshallShippingMethodZoneRates(zoneRates))
+}
+
+func TestAccDataSourceShippingMethod_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckShippingMethodDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceShippingMethodConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_shipping_method.standard", "id",
+						"commercetools_shipping_method.standard", "id",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_shipping_method.standard", "tax_category_id",
+						"commercetools_tax_category.standard", "id",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_shipping_method.standard", "name", "Standard",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_shipping_method.standard", "zone_rates.0.shipping_rates.0.price.0.cent_amount", "500",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_shipping_method.by_id", "key", "ds-standard",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceShippingMethodConfig() string {
+	return `
+resource "commercetools_tax_category" "standard" {
+	name = "ds-standard"
+	key  = "ds-standard"
+}
+
+resource "commercetools_shipping_method" "standard" {
+	name            = "Standard"
+	key             = "ds-standard"
+	tax_category_id = commercetools_tax_category.standard.id
+}
+
+resource "commercetools_shipping_zone" "de" {
+	name = "DE"
+	location {
+		country = "DE"
+	}
+}
+
+resource "commercetools_shipping_zone_rate" "standard_de" {
+	shipping_method_id = commercetools_shipping_method.standard.id
+	shipping_zone_id   = commercetools_shipping_zone.de.id
+
+	price {
+		cent_amount   = 500
+		currency_code = "EUR"
+	}
+}
+
+data "commercetools_shipping_method" "standard" {
+	key = "ds-standard"
+
+	depends_on = [commercetools_shipping_zone_rate.standard_de]
+}
+
+data "commercetools_shipping_method" "by_id" {
+	id = commercetools_shipping_method.standard.id
+}
+`
+}
@@ -0,0 +1,136 @@
+package commercetools
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceStore() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches an existing store by its ID or key, for example to limit discounts to a centrally " +
+			"managed store.\n\n" +
+			"See also the [Stores API Documentation](https://docs.commercetools.com/api/projects/stores)",
+		ReadContext: dataSourceStoreRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description:  "The ID of the store",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"key": {
+				Description:  "User-specific unique identifier for the store",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"name": {
+				Description: "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+				Type:        TypeLocalizedString,
+				Computed:    true,
+			},
+			"languages": {
+				Description: "[IETF Language Tag](https://en.wikipedia.org/wiki/IETF_language_tag)",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"distribution_channels": {
+				Description: "The keys of the channels with the ProductDistribution role",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"supply_channels": {
+				Description: "The keys of the channels with the InventorySupply role",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"product_selections": {
+				Description: "The product selections of the store, all products are available when empty",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"product_selection_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"active": {
+							Description: "Whether the products of the product selection are available in the store",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceStoreRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	expand := []string{"distributionChannels[*]", "supplyChannels[*]"}
+
+	var store *platform.Store
+	var err error
+	if id := d.Get("id").(string); id != "" {
+		log.Printf("[DEBUG] Reading store from commercetools, with id: %s", id)
+		store, err = client.Stores().WithId(id).Get().Expand(expand).Execute(ctx)
+	} else {
+		key := d.Get("key").(string)
+		log.Printf("[DEBUG] Reading store from commercetools, with key: %s", key)
+		store, err = client.Stores().WithKey(key).Get().Expand(expand).Execute(ctx)
+	}
+	if err != nil {
+		if isNotFoundError(err) {
+			return diag.Errorf("store not found")
+		}
+		return diagnosticsFromError(err)
+	}
+
+	distributionChannels, err := flattenStoreChannels(store.DistributionChannels)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+	supplyChannels, err := flattenStoreChannels(store.SupplyChannels)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	d.SetId(store.ID)
+	d.Set("key", store.Key)
+	if store.Name != nil {
+		d.Set("name", *store.Name)
+	} else {
+		d.Set("name", nil)
+	}
+	d.Set("languages", store.Languages)
+	d.Set("distribution_channels", distributionChannels)
+	d.Set("supply_channels", supplyChannels)
+	d.Set("product_selections", marshallStoreProductSelections(store.ProductSelections))
+	d.Set("version", store.Version)
+	return nil
+}
+
+func marshallStoreProductSelections(settings []platform.ProductSelectionSetting) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(settings))
+	for i, setting := range settings {
+		result[i] = map[string]interface{}{
+			"product_selection_id": setting.ProductSelection.ID,
+			"active":               setting.Active,
+		}
+	}
+	return result
+}
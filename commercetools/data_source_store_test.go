@@ -0,0 +1,76 @@
+package commercetools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshallStoreProductSelections(t *testing.T) {
+	assert.Equal(t, []map[string]interface{}{
+		{"product_selection_id": "selection-1", "active": true},
+		{"product_selection_id": "selection-2", "active": false},
+	}, marshallStoreProductSelections([]platform.ProductSelectionSetting{
+		{ProductSelection: platform.ProductSelectionReference{ID: "selection-1"}, Active: true},
+		{ProductSelection: platform.ProductSelectionReference{ID: "selection-2"}},
+	}))
+}
+
+func TestAccDataSourceStore_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStoreDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceStoreConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_store.by_key", "id",
+						"commercetools_store.standard", "id",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_store.by_key", "name.en", "Standard",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_store.by_key", "distribution_channels.0", "ds-store-distribution",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_store.by_id", "key", "ds-standard",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_store.by_id", "languages.#", "1",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceStoreConfig() string {
+	return `
+resource "commercetools_channel" "distribution" {
+	key   = "ds-store-distribution"
+	roles = ["ProductDistribution"]
+}
+
+resource "commercetools_store" "standard" {
+	key = "ds-standard"
+	name = {
+		en = "Standard"
+	}
+	languages             = ["en-US"]
+	distribution_channels = [commercetools_channel.distribution.key]
+}
+
+data "commercetools_store" "by_key" {
+	key = commercetools_store.standard.key
+}
+
+data "commercetools_store" "by_id" {
+	id = commercetools_store.standard.id
+}
+`
+}
@@ -0,0 +1,158 @@
+package commercetools
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceTaxCategory() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches an existing tax category by its ID or key, for example to use it for a shipping " +
+			"method.\n\n" +
+			"See also the [Tax Category API Documentation](https://docs.commercetools.com/api/projects/taxCategories)",
+		ReadContext: dataSourceTaxCategoryRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description:  "The ID of the tax category",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"key": {
+				Description:  "User-specific unique identifier for the tax category",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"rates": {
+				Description: "The tax rates of the tax category",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"amount": {
+							Description: "Number Percentage in the range of [0..1]",
+							Type:        schema.TypeFloat,
+							Computed:    true,
+						},
+						"included_in_price": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"country": {
+							Description: "A two-digit country code as per [ISO 3166-1 alpha-2](https://en.wikipedia.org/wiki/ISO_3166-1_alpha-2)",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"sub_rate": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"amount": {
+										Type:     schema.TypeFloat,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceTaxCategoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+
+	var taxCategory *platform.TaxCategory
+	var err error
+	if id := d.Get("id").(string); id != "" {
+		log.Printf("[DEBUG] Reading tax category from commercetools, with id: %s", id)
+		taxCategory, err = client.TaxCategories().WithId(id).Get().Execute(ctx)
+	} else {
+		key := d.Get("key").(string)
+		log.Printf("[DEBUG] Reading tax category from commercetools, with key: %s", key)
+		taxCategory, err = client.TaxCategories().WithKey(key).Get().Execute(ctx)
+	}
+	if err != nil {
+		if isNotFoundError(err) {
+			return diag.Errorf("tax category not found")
+		}
+		return diagnosticsFromError(err)
+	}
+
+	d.SetId(taxCategory.ID)
+	d.Set("key", taxCategory.Key)
+	d.Set("name", taxCategory.Name)
+	d.Set("description", taxCategory.Description)
+	d.Set("rates", marshallTaxCategoryRates(taxCategory.Rates))
+	d.Set("version", taxCategory.Version)
+	return nil
+}
+
+func marshallTaxCategoryRates(rates []platform.TaxRate) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(rates))
+	for i, rate := range rates {
+		subRates := make([]map[string]interface{}, len(rate.SubRates))
+		for j, subRate := range rate.SubRates {
+			subRates[j] = map[string]interface{}{
+				"name":   subRate.Name,
+				"amount": subRate.Amount,
+			}
+		}
+
+		item := map[string]interface{}{
+			"id":                "",
+			"name":              rate.Name,
+			"amount":            rate.Amount,
+			"included_in_price": rate.IncludedInPrice,
+			"country":           rate.Country,
+			"state":             "",
+			"sub_rate":          subRates,
+		}
+		if rate.ID != nil {
+			item["id"] = *rate.ID
+		}
+		if rate.State != nil {
+			item["state"] = *rate.State
+		}
+		result[i] = item
+	}
+	return result
+}
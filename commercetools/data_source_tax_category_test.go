@@ -0,0 +1,111 @@
+package commercetools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshallTaxCategoryRates(t *testing.T) {
+	rates := []platform.TaxRate{
+		{
+			ID:              stringRef("rate-de"),
+			Name:            "19% MwSt",
+			Amount:          0.19,
+			IncludedInPrice: true,
+			Country:         "DE",
+		},
+		{
+			Name:    "Texas",
+			Amount:  0.0825,
+			Country: "US",
+			State:   stringRef("TX"),
+			SubRates: []platform.SubRate{
+				{Name: "State", Amount: 0.0625},
+				{Name: "Local", Amount: 0.02},
+			},
+		},
+	}
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"id":                "rate-de",
+			"name":              "19% MwSt",
+			"amount":            0.19,
+			"included_in_price": true,
+			"country":           "DE",
+			"state":             "",
+			"sub_rate":          []map[string]interface{}{},
+		},
+		{
+			"id":                "",
+			"name":              "Texas",
+			"amount":            0.0825,
+			"included_in_price": false,
+			"country":           "US",
+			"state":             "TX",
+			"sub_rate": []map[string]interface{}{
+				{"name": "State", "amount": 0.0625},
+				{"name": "Local", "amount": 0.02},
+			},
+		},
+	}, marshallTaxCategoryRates(rates))
+}
+
+func TestAccDataSourceTaxCategory_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckTaxCategoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceTaxCategoryConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_tax_category.standard", "id",
+						"commercetools_tax_category.standard", "id",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_tax_category.standard", "rates.#", "1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_tax_category.standard", "rates.0.country", "DE",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_tax_category.by_id", "key", "ds-standard-tax",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceTaxCategoryConfig() string {
+	return `
+resource "commercetools_tax_category" "standard" {
+	name        = "Standard tax"
+	key         = "ds-standard-tax"
+	description = "Standard tax category"
+}
+
+resource "commercetools_tax_category_rate" "standard_de" {
+	tax_category_id   = commercetools_tax_category.standard.id
+	name              = "19% MwSt"
+	amount            = 0.19
+	included_in_price = true
+	country           = "DE"
+}
+
+data "commercetools_tax_category" "standard" {
+	key = "ds-standard-tax"
+
+	depends_on = [commercetools_tax_category_rate.standard_de]
+}
+
+data "commercetools_tax_category" "by_id" {
+	id = commercetools_tax_category.standard.id
+}
+`
+}
@@ -0,0 +1,167 @@
+package commercetools
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceType() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches an existing type by its ID or key, for example to set the `custom` fields of a " +
+			"discount code with a type which is managed elsewhere. The field definitions show which fields the " +
+			"type has and which values they accept.\n\n" +
+			"See also the [Types Api Documentation](https://docs.commercetools.com/api/projects/types)",
+		ReadContext: dataSourceTypeRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description:  "The ID of the type",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"key": {
+				Description:  "Identifier for the type",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"name": {
+				Type:     TypeLocalizedString,
+				Computed: true,
+			},
+			"description": {
+				Type:     TypeLocalizedString,
+				Computed: true,
+			},
+			"resource_type_ids": {
+				Description: "The [resources](https://docs.commercetools.com/api/projects/custom-fields#customizable-resources)" +
+					" the type is valid for",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"field_definitions": {
+				Description: "The [field definitions](https://docs.commercetools.com/api/projects/types#fielddefinition)" +
+					" of the type, in the same format as the `field` blocks of the `commercetools_type` resource",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     fieldTypeComputedElement(true),
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"label": {
+							Type:     TypeLocalizedString,
+							Computed: true,
+						},
+						"required": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"input_hint": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// fieldTypeComputedElement is the computed variant of fieldTypeElement
+func fieldTypeComputedElement(setsAllowed bool) *schema.Resource {
+	result := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"values": {
+			Type:     schema.TypeMap,
+			Computed: true,
+		},
+		"localized_value": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"key": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"label": {
+						Type:     TypeLocalizedString,
+						Computed: true,
+					},
+				},
+			},
+		},
+		"reference_type_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+
+	if setsAllowed {
+		result["element_type"] = &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     fieldTypeComputedElement(false),
+		}
+	}
+
+	return &schema.Resource{Schema: result}
+}
+
+func dataSourceTypeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+
+	var ctType *platform.Type
+	var err error
+	if id := d.Get("id").(string); id != "" {
+		log.Printf("[DEBUG] Reading type from commercetools, with id: %s", id)
+		ctType, err = client.Types().WithId(id).Get().Execute(ctx)
+	} else {
+		key := d.Get("key").(string)
+		log.Printf("[DEBUG] Reading type from commercetools, with key: %s", key)
+		ctType, err = client.Types().WithKey(key).Get().Execute(ctx)
+	}
+	if err != nil {
+		if isNotFoundError(err) {
+			return diag.Errorf("type not found")
+		}
+		return diagnosticsFromError(err)
+	}
+
+	fields, err := marshallTypeFields(ctType)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	d.SetId(ctType.ID)
+	d.Set("key", ctType.Key)
+	d.Set("name", ctType.Name)
+	if ctType.Description != nil {
+		d.Set("description", ctType.Description)
+	}
+	d.Set("resource_type_ids", ctType.ResourceTypeIds)
+	d.Set("field_definitions", fields)
+	d.Set("version", ctType.Version)
+	return nil
+}
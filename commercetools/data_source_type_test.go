@@ -0,0 +1,119 @@
+package commercetools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourceTypeRead(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "type-1",
+			"version": 3,
+			"key": "discount-code-fields",
+			"name": {"en": "Discount code fields"},
+			"resourceTypeIds": ["discount-code"],
+			"fieldDefinitions": [
+				{
+					"name": "campaign",
+					"label": {"en": "Campaign"},
+					"required": true,
+					"inputHint": "SingleLine",
+					"type": {"name": "Enum", "values": [{"key": "summer", "label": "Summer"}]}
+				},
+				{
+					"name": "channels",
+					"label": {"en": "Channels"},
+					"required": false,
+					"inputHint": "SingleLine",
+					"type": {"name": "Set", "elementType": {"name": "Reference", "referenceTypeId": "channel"}}
+				}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, dataSourceType().Schema, map[string]interface{}{"key": "discount-code-fields"})
+	diags := dataSourceTypeRead(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, "/my-project/types/key=discount-code-fields", path)
+	assert.Equal(t, "type-1", d.Id())
+	assert.Equal(t, map[string]interface{}{"en": "Discount code fields"}, d.Get("name"))
+	assert.Equal(t, []interface{}{"discount-code"}, d.Get("resource_type_ids"))
+	assert.Equal(t, 2, d.Get("field_definitions.#"))
+	assert.Equal(t, "campaign", d.Get("field_definitions.0.name"))
+	assert.Equal(t, true, d.Get("field_definitions.0.required"))
+	assert.Equal(t, "Enum", d.Get("field_definitions.0.type.0.name"))
+	assert.Equal(t, map[string]interface{}{"summer": "Summer"}, d.Get("field_definitions.0.type.0.values"))
+	assert.Equal(t, "Set", d.Get("field_definitions.1.type.0.name"))
+	assert.Equal(t, "Reference", d.Get("field_definitions.1.type.0.element_type.0.name"))
+	assert.Equal(t, "channel", d.Get("field_definitions.1.type.0.element_type.0.reference_type_id"))
+}
+
+func TestAccDataSourceType_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckTypesDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceTypeConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_type.fields", "id",
+						"commercetools_type.fields", "id",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_type.fields", "field_definitions.#", "1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_type.fields", "field_definitions.0.type.0.name", "String",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceTypeConfig() string {
+	return `
+resource "commercetools_type" "fields" {
+	key = "ds-discount-code-fields"
+	name = {
+		en = "Discount code fields"
+	}
+	resource_type_ids = ["discount-code"]
+
+	field {
+		name = "campaign"
+		label = {
+			en = "Campaign"
+		}
+		type {
+			name = "String"
+		}
+	}
+}
+
+data "commercetools_type" "fields" {
+	key = commercetools_type.fields.key
+}
+`
+}
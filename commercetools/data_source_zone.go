@@ -0,0 +1,95 @@
+package commercetools
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func dataSourceZone() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches an existing zone by its ID or key, for example to reference a centrally managed " +
+			"zone from a shipping method. Zones are managed with the `commercetools_shipping_zone` resource.\n\n" +
+			"See also the [Zones API Documentation](https://docs.commercetools.com/api/projects/zones)",
+		ReadContext: dataSourceZoneRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description:  "The ID of the zone",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"key": {
+				Description:  "User-specific unique identifier for the zone",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "key"},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"locations": {
+				Description: "The [Locations](https://docs.commercetools.com/api/projects/zones#location) of the zone",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"country": {
+							Description: "A two-digit country code as per " +
+								"[ISO 3166-1 alpha-2](https://en.wikipedia.org/wiki/ISO_3166-1_alpha-2)",
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceZoneRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+
+	var zone *platform.Zone
+	var err error
+	if id := d.Get("id").(string); id != "" {
+		log.Printf("[DEBUG] Reading zone from commercetools, with id: %s", id)
+		zone, err = client.Zones().WithId(id).Get().Execute(ctx)
+	} else {
+		key := d.Get("key").(string)
+		log.Printf("[DEBUG] Reading zone from commercetools, with key: %s", key)
+		zone, err = client.Zones().WithKey(key).Get().Execute(ctx)
+	}
+	if err != nil {
+		if isNotFoundError(err) {
+			return diag.Errorf("zone not found")
+		}
+		return diagnosticsFromError(err)
+	}
+
+	d.SetId(zone.ID)
+	d.Set("key", zone.Key)
+	d.Set("name", zone.Name)
+	d.Set("description", zone.Description)
+	d.Set("locations", marshallShippingZoneLocations(zone.Locations))
+	d.Set("version", zone.Version)
+	return nil
+}
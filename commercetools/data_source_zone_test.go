@@ -0,0 +1,101 @@
+package commercetools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourceZoneRead(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "zone-1",
+			"version": 2,
+			"key": "dach",
+			"name": "DACH",
+			"locations": [{"country": "DE"}, {"country": "US", "state": "TX"}]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, dataSourceZone().Schema, map[string]interface{}{"key": "dach"})
+	diags := dataSourceZoneRead(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, "/my-project/zones/key=dach", path)
+	assert.Equal(t, "zone-1", d.Id())
+	assert.Equal(t, "DACH", d.Get("name"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"country": "DE", "state": ""},
+		map[string]interface{}{"country": "US", "state": "TX"},
+	}, d.Get("locations"))
+}
+
+func TestAccDataSourceZone_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckShippingZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceZoneConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.commercetools_zone.by_key", "id",
+						"commercetools_shipping_zone.dach", "id",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_zone.by_key", "locations.#", "2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_zone.by_key", "locations.0.country", "DE",
+					),
+					resource.TestCheckResourceAttr(
+						"data.commercetools_zone.by_id", "name", "DACH",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceZoneConfig() string {
+	return `
+resource "commercetools_shipping_zone" "dach" {
+	key         = "ds-dach"
+	name        = "DACH"
+	description = "Germany, Austria and Switzerland"
+
+	location {
+		country = "DE"
+	}
+
+	location {
+		country = "AT"
+	}
+}
+
+data "commercetools_zone" "by_key" {
+	key = commercetools_shipping_zone.dach.key
+}
+
+data "commercetools_zone" "by_id" {
+	id = commercetools_shipping_zone.dach.id
+}
+`
+}
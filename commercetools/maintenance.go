@@ -0,0 +1,97 @@
+package commercetools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+// maintenanceMessage starts the message of the 503 responses commercetools
+// sends for every request while the project is under maintenance
+const maintenanceMessage = "The project is currently under maintenance"
+
+// The waits while a project is under maintenance. They are variables so tests
+// don't have to wait for minutes.
+var (
+	maintenanceRetryInterval    = 15 * time.Second
+	maintenanceMaxRetryInterval = 2 * time.Minute
+	maintenanceMaxWait          = 30 * time.Minute
+)
+
+// maintenanceError is returned by handleCommercetoolsError for the responses
+// commercetools sends while the project is under maintenance. Maintenance
+// takes longer than the retries of a resource timeout, so it is not retried
+// unless the provider is configured to wait for it.
+type maintenanceError struct {
+	err error
+}
+
+func (e *maintenanceError) Error() string {
+	return fmt.Sprintf("the commercetools project is under maintenance: %s", e.err)
+}
+
+func (e *maintenanceError) Unwrap() error {
+	return e.err
+}
+
+// isMaintenanceResponse returns whether the error response is sent because of
+// a scheduled maintenance. These are 503 responses like gateway errors, but
+// with the maintenance message. Other 503 responses which happen to mention a
+// maintenance, for example of a proxy, are gateway errors.
+func isMaintenanceResponse(err platform.ErrorResponse) bool {
+	return err.StatusCode == 503 && strings.HasPrefix(err.Message, maintenanceMessage)
+}
+
+// waitForMaintenance calls f again while it fails because the project is
+// under maintenance. The wait between the calls doubles up to
+// maintenanceMaxRetryInterval and the total wait is limited by
+// maintenanceMaxWait. The wait is also bounded by the context, which expires
+// with the timeout of the resource operation, since the requests made after
+// the wait can't use an expired context either.
+func waitForMaintenance(ctx context.Context, operation string, f func() error) error {
+	var waited time.Duration
+	interval := maintenanceRetryInterval
+	for {
+		err := f()
+		var maintenance *maintenanceError
+		if err == nil || !errors.As(err, &maintenance) {
+			return err
+		}
+		if waited+interval > maintenanceMaxWait {
+			return fmt.Errorf(
+				"%s failed, the project is still under maintenance after waiting %s: %s", operation, waited, maintenance.err)
+		}
+
+		log.Printf("[INFO] The project is under maintenance, retrying %s in %s", operation, interval)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return fmt.Errorf(
+				"%s failed, the timeout of the operation expired after waiting %s for the maintenance to end: %s",
+				operation, waited, maintenance.err)
+		}
+		waited += interval
+		interval *= 2
+		if interval > maintenanceMaxRetryInterval {
+			interval = maintenanceMaxRetryInterval
+		}
+	}
+}
+
+// maintenanceDiagnostics explains how to handle a failure because of a
+// maintenance of the project
+func maintenanceDiagnostics(err *maintenanceError) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "The commercetools project is under maintenance",
+		Detail: fmt.Sprintf(
+			"%s\n\nTry again once the maintenance is over, or set wait_for_maintenance of the provider to "+
+				"wait up to %s for it, within the timeout of the resource.", err.err, maintenanceMaxWait),
+	}}
+}
@@ -0,0 +1,99 @@
+package commercetools
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+var errMaintenance = platform.ErrorResponse{
+	StatusCode: 503,
+	Message:    "The project is currently under maintenance. Please try again later.",
+}
+
+func setMaintenanceWaits(t *testing.T, interval, maxWait time.Duration) {
+	oldInterval, oldMaxInterval, oldMaxWait := maintenanceRetryInterval, maintenanceMaxRetryInterval, maintenanceMaxWait
+	maintenanceRetryInterval, maintenanceMaxRetryInterval, maintenanceMaxWait = interval, interval, maxWait
+	t.Cleanup(func() {
+		maintenanceRetryInterval, maintenanceMaxRetryInterval, maintenanceMaxWait = oldInterval, oldMaxInterval, oldMaxWait
+	})
+}
+
+func TestHandleCommercetoolsErrorMaintenance(t *testing.T) {
+	result := handleCommercetoolsError(errMaintenance)
+	assert.False(t, result.Retryable)
+	var maintenance *maintenanceError
+	assert.True(t, errors.As(result.Err, &maintenance))
+
+	// Other 503 responses are still retried as gateway errors
+	assert.True(t, handleCommercetoolsError(platform.ErrorResponse{StatusCode: 503, Message: "Service Unavailable"}).Retryable)
+	proxy := platform.ErrorResponse{StatusCode: 503, Message: "Backend in maintenance mode, retry later"}
+	assert.True(t, handleCommercetoolsError(proxy).Retryable)
+}
+
+func TestRetryContextMaintenanceFailFast(t *testing.T) {
+	attempts := 0
+	err := retryContext(context.Background(), &providerMeta{}, "create test", time.Minute, func() *resource.RetryError {
+		attempts++
+		return handleCommercetoolsError(errMaintenance)
+	})
+	assert.Equal(t, 1, attempts)
+
+	diags := diagnosticsFromError(err)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "The commercetools project is under maintenance", diags[0].Summary)
+		assert.Contains(t, diags[0].Detail, "set wait_for_maintenance of the provider")
+	}
+}
+
+func TestRetryContextWaitForMaintenance(t *testing.T) {
+	setMaintenanceWaits(t, 10*time.Millisecond, time.Second)
+	meta := &providerMeta{waitForMaintenance: true}
+
+	attempts := 0
+	err := retryContext(context.Background(), meta, "create test", time.Minute, func() *resource.RetryError {
+		attempts++
+		if attempts < 3 {
+			return handleCommercetoolsError(errMaintenance)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryContextWaitForMaintenanceLimit(t *testing.T) {
+	setMaintenanceWaits(t, 10*time.Millisecond, 50*time.Millisecond)
+	meta := &providerMeta{waitForMaintenance: true}
+
+	attempts := 0
+	err := retryContext(context.Background(), meta, "create test", time.Minute, func() *resource.RetryError {
+		attempts++
+		return handleCommercetoolsError(errMaintenance)
+	})
+	assert.Equal(t, 6, attempts)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "create test failed, the project is still under maintenance after waiting 50ms")
+	}
+}
+
+func TestRetryContextWaitForMaintenanceTimeout(t *testing.T) {
+	setMaintenanceWaits(t, time.Minute, time.Hour)
+	meta := &providerMeta{waitForMaintenance: true}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := retryContext(ctx, meta, "create test", time.Minute, func() *resource.RetryError {
+		return handleCommercetoolsError(errMaintenance)
+	})
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Second))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "create test failed, the timeout of the operation expired after waiting 0s")
+	}
+}
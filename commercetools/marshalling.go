@@ -1,8 +1,11 @@
 package commercetools
 
 import (
+	"fmt"
+	"log"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/labd/commercetools-go-sdk/platform"
 )
 
@@ -17,8 +20,47 @@ func unmarshallTime(input string) (time.Time, error) {
 	return time.Parse(time.RFC3339, input)
 }
 
+// unmarshallOptionalTime parses the timestamp, an empty value results in nil
+// so it can be used to clear a field.
+func unmarshallOptionalTime(input string) (*time.Time, error) {
+	if input == "" {
+		return nil, nil
+	}
+	result, err := unmarshallTime(input)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// diffSuppressTime suppresses the difference between two timestamps which
+// represent the same instant, for example when the API returns the timestamp
+// in another format than configured.
+func diffSuppressTime(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	if old == "" || new == "" {
+		return false
+	}
+	oldTime, err := unmarshallTime(old)
+	if err != nil {
+		return false
+	}
+	newTime, err := unmarshallTime(new)
+	if err != nil {
+		return false
+	}
+	return oldTime.Equal(newTime)
+}
+
 func marshallTypedMoney(val platform.TypedMoney) map[string]interface{} {
 	switch v := val.(type) {
+	case platform.CentPrecisionMoney:
+		return map[string]interface{}{
+			"currency_code": v.CurrencyCode,
+			"cent_amount":   v.CentAmount,
+		}
 	case platform.HighPrecisionMoney:
 		return map[string]interface{}{
 			"currency_code": v.CurrencyCode,
@@ -33,6 +75,77 @@ func marshallTypedMoney(val platform.TypedMoney) map[string]interface{} {
 	panic("Unknown money type")
 }
 
+// modifiedBySchema returns the schema of the createdBy and lastModifiedBy
+// fields of a resource
+func modifiedBySchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"client_id": {
+				Description: "The ID of the API client",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"external_user_id": {
+				Description: "The external user ID set by the client",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"customer_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"anonymous_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func marshallCreatedBy(val *platform.CreatedBy) []map[string]interface{} {
+	if val == nil {
+		return []map[string]interface{}{}
+	}
+	return marshallModifiedBy(val.ClientId, val.ExternalUserId, val.Customer, val.AnonymousId)
+}
+
+func marshallLastModifiedBy(val *platform.LastModifiedBy) []map[string]interface{} {
+	if val == nil {
+		return []map[string]interface{}{}
+	}
+	return marshallModifiedBy(val.ClientId, val.ExternalUserId, val.Customer, val.AnonymousId)
+}
+
+func marshallModifiedBy(clientID, externalUserID *string, customer *platform.CustomerReference, anonymousID *string) []map[string]interface{} {
+	result := map[string]interface{}{
+		"client_id":        "",
+		"external_user_id": "",
+		"customer_id":      "",
+		"anonymous_id":     "",
+	}
+	if clientID != nil {
+		result["client_id"] = *clientID
+	}
+	if externalUserID != nil {
+		result["external_user_id"] = *externalUserID
+	}
+	if customer != nil {
+		result["customer_id"] = customer.ID
+	}
+	if anonymousID != nil {
+		result["anonymous_id"] = *anonymousID
+	}
+	return []map[string]interface{}{result}
+}
+
+func marshallTypedMoneyList(values []platform.TypedMoney) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(values))
+	for i, value := range values {
+		result[i] = marshallTypedMoney(value)
+	}
+	return result
+}
+
 func unmarshallTypedMoney(d map[string]interface{}) []platform.Money {
 	input := d["money"].([]interface{})
 	var result []platform.Money
@@ -57,8 +170,30 @@ func unmarshallLocalizedString(val interface{}) platform.LocalizedString {
 	}
 
 	result := make(platform.LocalizedString, len(values))
-	for k := range values {
-		result[k] = values[k].(string)
+	for k, v := range values {
+		value, err := localizedStringValue(v)
+		if err != nil {
+			log.Printf("[WARN] Ignoring the value of locale %s: %s", k, err)
+			continue
+		}
+		result[k] = value
 	}
 	return result
 }
+
+// localizedStringValue returns the value of a locale as string. Terraform
+// passes the values of a map as strings, but maps built in other ways, like
+// decoded JSON, can contain numbers and booleans as well.
+func localizedStringValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case bool, int, int64, float64:
+		return fmt.Sprint(v), nil
+	case nil:
+		return "", fmt.Errorf("must be a string, got null")
+	}
+	return "", fmt.Errorf("must be a string, got %T", value)
+}
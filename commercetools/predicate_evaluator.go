@@ -0,0 +1,487 @@
+package commercetools
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// predicateCart is the cart a predicate is evaluated against by
+// evaluatePredicate. Prices are in cents of the currency of the cart.
+type predicateCart struct {
+	CustomerGroupKey string
+	Currency         string
+	LineItems        []predicateLineItem
+}
+
+type predicateLineItem struct {
+	SKU           string
+	ProductID     string
+	ProductTypeID string
+	CategoryIDs   []string
+	Quantity      int
+	CentAmount    int
+}
+
+func (c predicateCart) totalCentAmount() int {
+	total := 0
+	for _, lineItem := range c.LineItems {
+		total += lineItem.CentAmount * lineItem.Quantity
+	}
+	return total
+}
+
+// evaluatePredicate evaluates a cart predicate against the cart. When
+// lineItem is set the predicate is a line item predicate, like the predicate
+// of a lineItems target. This is a best effort evaluator which supports the
+// common fields and operators, an error is returned for anything else.
+func evaluatePredicate(predicate string, cart predicateCart, lineItem *predicateLineItem) (bool, error) {
+	node, err := parsePredicate(predicate)
+	if err != nil {
+		return false, err
+	}
+	return node.eval(predicateScope{cart: cart, lineItem: lineItem})
+}
+
+// evaluateLineItemPredicate returns whether the predicate matches at least one
+// of the line items of the cart
+func evaluateLineItemPredicate(predicate string, cart predicateCart) (bool, error) {
+	node, err := parsePredicate(predicate)
+	if err != nil {
+		return false, err
+	}
+	return predicateScope{cart: cart}.anyLineItem(node)
+}
+
+type predicateScope struct {
+	cart     predicateCart
+	lineItem *predicateLineItem
+}
+
+func (s predicateScope) anyLineItem(node predicateNode) (bool, error) {
+	for i := range s.cart.LineItems {
+		ok, err := node.eval(predicateScope{cart: s.cart, lineItem: &s.cart.LineItems[i]})
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+type predicateNode interface {
+	eval(scope predicateScope) (bool, error)
+}
+
+type predicateAnd []predicateNode
+
+func (n predicateAnd) eval(scope predicateScope) (bool, error) {
+	for _, child := range n {
+		ok, err := child.eval(scope)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+type predicateOr []predicateNode
+
+func (n predicateOr) eval(scope predicateScope) (bool, error) {
+	for _, child := range n {
+		ok, err := child.eval(scope)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+type predicateNot struct{ node predicateNode }
+
+func (n predicateNot) eval(scope predicateScope) (bool, error) {
+	ok, err := n.node.eval(scope)
+	return !ok, err
+}
+
+type predicateLineItemExists struct{ node predicateNode }
+
+func (n predicateLineItemExists) eval(scope predicateScope) (bool, error) {
+	return scope.anyLineItem(n.node)
+}
+
+// predicateLineItemCount compares the sum of the quantities of the matching
+// line items with a number
+type predicateLineItemCount struct {
+	node     predicateNode
+	operator string
+	value    predicateValue
+}
+
+func (n predicateLineItemCount) eval(scope predicateScope) (bool, error) {
+	count := 0
+	for i := range scope.cart.LineItems {
+		lineItem := &scope.cart.LineItems[i]
+		ok, err := n.node.eval(predicateScope{cart: scope.cart, lineItem: lineItem})
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			count += lineItem.Quantity
+		}
+	}
+	return compareNumbers(float64(count), n.operator, n.value)
+}
+
+// predicateValue is a literal in a predicate
+type predicateValue struct {
+	text     string
+	isString bool
+}
+
+func (v predicateValue) number() (float64, error) {
+	if v.isString {
+		return 0, fmt.Errorf("expected a number, got %q", v.text)
+	}
+	return strconv.ParseFloat(v.text, 64)
+}
+
+type predicateComparison struct {
+	field    string
+	operator string
+	values   []predicateValue
+}
+
+func (n predicateComparison) eval(scope predicateScope) (bool, error) {
+	if n.field == "totalPrice" && scope.lineItem == nil {
+		return compareMoney(scope.cart.totalCentAmount(), scope.cart.Currency, n.operator, n.values[0])
+	}
+	if number, err := strconv.ParseFloat(n.field, 64); err == nil {
+		return compareNumbers(number, n.operator, n.values[0])
+	}
+
+	if strings.HasSuffix(n.field, "customerGroup.key") && scope.lineItem == nil {
+		return compareStrings([]string{scope.cart.CustomerGroupKey}, n.operator, n.values)
+	}
+
+	if scope.lineItem == nil {
+		return false, fmt.Errorf("unsupported cart predicate field %s", n.field)
+	}
+	lineItem := scope.lineItem
+	switch n.field {
+	case "sku":
+		return compareStrings([]string{lineItem.SKU}, n.operator, n.values)
+	case "productId", "product.id":
+		return compareStrings([]string{lineItem.ProductID}, n.operator, n.values)
+	case "productType.id":
+		return compareStrings([]string{lineItem.ProductTypeID}, n.operator, n.values)
+	case "categories.id":
+		return compareStrings(lineItem.CategoryIDs, n.operator, n.values)
+	case "quantity":
+		return compareNumbers(float64(lineItem.Quantity), n.operator, n.values[0])
+	case "price":
+		return compareMoney(lineItem.CentAmount, scope.cart.Currency, n.operator, n.values[0])
+	default:
+		return false, fmt.Errorf("unsupported line item predicate field %s", n.field)
+	}
+}
+
+// compareStrings compares the values of a field with the literals. Single
+// valued fields are passed as a list with one value.
+func compareStrings(field []string, operator string, values []predicateValue) (bool, error) {
+	contains := func(value predicateValue) bool {
+		for _, item := range field {
+			if item == value.text {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch operator {
+	case "=", "contains":
+		return contains(values[0]), nil
+	case "!=":
+		return !contains(values[0]), nil
+	case "in", "contains any":
+		for _, value := range values {
+			if contains(value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "not in":
+		for _, value := range values {
+			if contains(value) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case "contains all":
+		for _, value := range values {
+			if !contains(value) {
+				return false, nil
+			}
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("operator %s is not supported for strings", operator)
+	}
+}
+
+func compareNumbers(field float64, operator string, value predicateValue) (bool, error) {
+	number, err := value.number()
+	if err != nil {
+		return false, err
+	}
+	switch operator {
+	case "=":
+		return field == number, nil
+	case "!=":
+		return field != number, nil
+	case "<":
+		return field < number, nil
+	case "<=":
+		return field <= number, nil
+	case ">":
+		return field > number, nil
+	case ">=":
+		return field >= number, nil
+	default:
+		return false, fmt.Errorf("operator %s is not supported for numbers", operator)
+	}
+}
+
+// compareMoney compares an amount in cents with a money literal like
+// "10.00 EUR". Amounts in another currency never match.
+func compareMoney(centAmount int, currency string, operator string, value predicateValue) (bool, error) {
+	parts := strings.Fields(value.text)
+	if !value.isString || len(parts) != 2 {
+		return false, fmt.Errorf("expected a money value like \"10.00 EUR\", got %s", value.text)
+	}
+	if currency == "" {
+		return false, fmt.Errorf("the currency of the cart is required to compare with %s", value.text)
+	}
+	if !strings.EqualFold(parts[1], currency) {
+		return false, nil
+	}
+	amount, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return false, fmt.Errorf("expected a money value like \"10.00 EUR\", got %s", value.text)
+	}
+	cents := math.Round(amount * 100)
+	return compareNumbers(float64(centAmount), operator, predicateValue{text: strconv.FormatFloat(cents, 'f', -1, 64)})
+}
+
+// predicateParser is a recursive descent parser for the supported subset of
+// the predicate language, using the tokens of predicateTokens
+type predicateParser struct {
+	tokens []string
+	pos    int
+}
+
+func parsePredicate(predicate string) (predicateNode, error) {
+	p := &predicateParser{tokens: predicateTokens(predicate)}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unsupported predicate, unexpected %s", p.tokens[p.pos])
+	}
+	return node, nil
+}
+
+func (p *predicateParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *predicateParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *predicateParser) peekKeyword(keyword string) bool {
+	return strings.EqualFold(p.peek(), keyword)
+}
+
+func (p *predicateParser) expect(token string) error {
+	if next := p.next(); next != token {
+		return fmt.Errorf("unsupported predicate, expected %s instead of %q", token, next)
+	}
+	return nil
+}
+
+func (p *predicateParser) parseOr() (predicateNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	result := predicateOr{node}
+	for p.peekKeyword("or") {
+		p.next()
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, node)
+	}
+	if len(result) == 1 {
+		return node, nil
+	}
+	return result, nil
+}
+
+func (p *predicateParser) parseAnd() (predicateNode, error) {
+	node, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	result := predicateAnd{node}
+	for p.peekKeyword("and") {
+		p.next()
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, node)
+	}
+	if len(result) == 1 {
+		return node, nil
+	}
+	return result, nil
+}
+
+func (p *predicateParser) parseUnary() (predicateNode, error) {
+	switch {
+	case p.peekKeyword("not"):
+		p.next()
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return predicateNot{node}, nil
+	case p.peek() == "(":
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+	return p.parseComparison()
+}
+
+func (p *predicateParser) parseComparison() (predicateNode, error) {
+	field := p.next()
+	if field == "" || isPredicateOperator(field) || strings.HasPrefix(field, `"`) {
+		return nil, fmt.Errorf("unsupported predicate, expected a field instead of %q", field)
+	}
+
+	if (field == "lineItemExists" || field == "lineItemCount") && p.peek() == "(" {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if field == "lineItemExists" {
+			return predicateLineItemExists{node}, nil
+		}
+		operator, err := p.parseOperator()
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return predicateLineItemCount{node: node, operator: operator, value: value}, nil
+	}
+
+	operator, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+	var values []predicateValue
+	if p.peek() == "(" {
+		values, err = p.parseValueList()
+	} else {
+		var value predicateValue
+		value, err = p.parseValue()
+		values = []predicateValue{value}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return predicateComparison{field: field, operator: operator, values: values}, nil
+}
+
+func (p *predicateParser) parseOperator() (string, error) {
+	token := p.next()
+	switch {
+	case token == "=":
+		return "=", nil
+	case token == "!" && p.peek() == "=":
+		p.next()
+		return "!=", nil
+	case token == "<" && p.peek() == ">":
+		p.next()
+		return "!=", nil
+	case token == "<" || token == ">":
+		if p.peek() == "=" {
+			p.next()
+			return token + "=", nil
+		}
+		return token, nil
+	case strings.EqualFold(token, "in"):
+		return "in", nil
+	case strings.EqualFold(token, "not") && p.peekKeyword("in"):
+		p.next()
+		return "not in", nil
+	case strings.EqualFold(token, "contains"):
+		if p.peekKeyword("any") || p.peekKeyword("all") {
+			return "contains " + strings.ToLower(p.next()), nil
+		}
+		return "contains", nil
+	}
+	return "", fmt.Errorf("unsupported predicate operator %q", token)
+}
+
+func (p *predicateParser) parseValueList() ([]predicateValue, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var values []predicateValue
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.peek() != "," {
+			break
+		}
+		p.next()
+	}
+	return values, p.expect(")")
+}
+
+func (p *predicateParser) parseValue() (predicateValue, error) {
+	token := p.next()
+	if len(token) >= 2 && (token[0] == '"' || token[0] == '\'') && token[len(token)-1] == token[0] {
+		unquoted := token[1 : len(token)-1]
+		unquoted = strings.NewReplacer(`\"`, `"`, `\'`, `'`, `\\`, `\`).Replace(unquoted)
+		return predicateValue{text: unquoted, isString: true}, nil
+	}
+	if _, err := strconv.ParseFloat(token, 64); err == nil {
+		return predicateValue{text: token}, nil
+	}
+	return predicateValue{}, fmt.Errorf("unsupported predicate value %q", token)
+}
@@ -0,0 +1,114 @@
+package commercetools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluatePredicate(t *testing.T) {
+	cart := predicateCart{
+		CustomerGroupKey: "vip",
+		Currency:         "EUR",
+		LineItems: []predicateLineItem{
+			{SKU: "shoe-1", ProductID: "p-1", CategoryIDs: []string{"shoes", "sale"}, Quantity: 2, CentAmount: 2500},
+			{SKU: "sock-1", ProductID: "p-2", CategoryIDs: []string{"socks"}, Quantity: 3, CentAmount: 500},
+		},
+	}
+
+	cases := []struct {
+		predicate string
+		expected  bool
+	}{
+		{`1 = 1`, true},
+		{`1=2`, false},
+		{`customer.customerGroup.key = "vip"`, true},
+		{`customerGroup.key in ("gold", "silver")`, false},
+		{`customerGroup.key != "gold"`, true},
+		{`totalPrice >= "65.00 EUR"`, true},
+		{`totalPrice > "65.00 EUR"`, false},
+		{`totalPrice > "1.00 USD"`, false},
+		{`lineItemExists(sku = "shoe-1")`, true},
+		{`lineItemExists(sku = "hat-1")`, false},
+		{`lineItemExists(categories.id contains any ("sale", "new")) and customerGroup.key = "vip"`, true},
+		{`lineItemExists(categories.id contains all ("shoes", "socks"))`, false},
+		{`lineItemCount(categories.id contains "socks") >= 3`, true},
+		{`lineItemCount(1 = 1) = 5`, true},
+		{`not (customerGroup.key = "vip") or lineItemExists(quantity > 2)`, true},
+		{`lineItemExists(price < "10.00 EUR" and sku not in ("shoe-1"))`, true},
+	}
+
+	for _, c := range cases {
+		result, err := evaluatePredicate(c.predicate, cart, nil)
+		assert.NoError(t, err, c.predicate)
+		assert.Equal(t, c.expected, result, c.predicate)
+	}
+}
+
+func TestEvaluatePredicateUnsupported(t *testing.T) {
+	cart := predicateCart{LineItems: []predicateLineItem{{SKU: "a", Quantity: 1}}}
+
+	for _, predicate := range []string{
+		`shippingInfo.shippingMethodName = "Express"`,
+		`lineItemTotal(1 = 1) > "10.00 EUR"`,
+		`customer.email is defined`,
+		`totalPrice > "10.00 EUR"`,
+		`sku = "a"`,
+		`customerGroup.key = `,
+	} {
+		_, err := evaluatePredicate(predicate, cart, nil)
+		assert.Error(t, err, predicate)
+	}
+}
+
+func TestEvaluateLineItemPredicate(t *testing.T) {
+	cart := predicateCart{
+		LineItems: []predicateLineItem{
+			{SKU: "a", ProductTypeID: "shoes", Quantity: 1},
+			{SKU: "b", ProductTypeID: "socks", Quantity: 1},
+		},
+	}
+
+	result, err := evaluateLineItemPredicate(`productType.id = "socks"`, cart)
+	assert.NoError(t, err)
+	assert.True(t, result)
+
+	result, err = evaluateLineItemPredicate(`sku in ("c", "d")`, cart)
+	assert.NoError(t, err)
+	assert.False(t, result)
+}
+
+func TestEvaluateGeneratedLineItemPredicate(t *testing.T) {
+	cart := predicateCart{
+		LineItems: []predicateLineItem{
+			{SKU: "a", ProductID: "product-1", ProductTypeID: "shoes", CategoryIDs: []string{"sale"}, Quantity: 1},
+		},
+	}
+
+	cases := []struct {
+		config   map[string]interface{}
+		expected bool
+	}{
+		{map[string]interface{}{"skus": []interface{}{"a", "b"}}, true},
+		{map[string]interface{}{"product_ids": []interface{}{"product-1"}}, true},
+		{map[string]interface{}{"product_ids": []interface{}{"product-2", "product-3"}}, false},
+		{map[string]interface{}{"product_type_ids": []interface{}{"shoes"}}, true},
+		{map[string]interface{}{"category_ids": []interface{}{"new", "sale"}}, true},
+		{map[string]interface{}{"product_ids": []interface{}{"product-1"}, "skus": []interface{}{"b"}}, false},
+		{map[string]interface{}{
+			"product_ids": []interface{}{"product-1"}, "skus": []interface{}{"b"}, "operator": "or"}, true},
+	}
+	for _, c := range cases {
+		// Evaluate the predicate of the commercetools_line_item_predicate
+		// data source, which the simulation must understand
+		d := schema.TestResourceDataRaw(t, dataSourceLineItemPredicate().Schema, c.config)
+		assert.Empty(t, dataSourceLineItemPredicateRead(context.Background(), d, nil))
+		predicate := d.Get("predicate").(string)
+
+		result, err := evaluateLineItemPredicate(predicate, cart)
+		assert.NoError(t, err, predicate)
+		assert.Equal(t, c.expected, result, predicate)
+	}
+}
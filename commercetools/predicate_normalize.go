@@ -0,0 +1,111 @@
+package commercetools
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// predicateOperatorChars are the characters which form a token on their own.
+// Whitespace around them has no meaning.
+const predicateOperatorChars = "=!<>(),[]"
+
+// diffSuppressPredicate suppresses the diff between two predicates which only
+// differ cosmetically, see normalizePredicate
+func diffSuppressPredicate(k, old, new string, d *schema.ResourceData) bool {
+	return normalizePredicate(old) == normalizePredicate(new)
+}
+
+// normalizePredicate returns a canonical form of the predicate. Whitespace is
+// collapsed and removed around operators, string literals are left untouched.
+// When the predicate is a plain conjunction (`a = 1 and b = 2`) the operands
+// are sorted. Predicates with a top level `or` or `not` keep their order, so
+// only changes which can't alter the logic are normalized.
+func normalizePredicate(predicate string) string {
+	tokens := predicateTokens(predicate)
+
+	var conjuncts [][]string
+	start, depth := 0, 0
+	sortable := true
+	for i, token := range tokens {
+		switch {
+		case token == "(" || token == "[":
+			depth++
+		case token == ")" || token == "]":
+			depth--
+		case depth == 0 && (strings.EqualFold(token, "or") || strings.EqualFold(token, "not")):
+			sortable = false
+		case depth == 0 && strings.EqualFold(token, "and"):
+			conjuncts = append(conjuncts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	conjuncts = append(conjuncts, tokens[start:])
+
+	if !sortable || depth != 0 || len(conjuncts) < 2 {
+		return joinPredicateTokens(tokens)
+	}
+
+	result := make([]string, len(conjuncts))
+	for i, conjunct := range conjuncts {
+		result[i] = joinPredicateTokens(conjunct)
+	}
+	sort.Strings(result)
+	return strings.Join(result, " and ")
+}
+
+// predicateTokens splits the predicate in words, string literals and
+// operator characters
+func predicateTokens(predicate string) []string {
+	var tokens []string
+	runes := []rune(predicate)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				end = len(runes) - 1
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end + 1
+		case strings.ContainsRune(predicateOperatorChars, r):
+			tokens = append(tokens, string(r))
+			i++
+		default:
+			end := i
+			for end < len(runes) && !strings.ContainsRune(predicateOperatorChars+" \t\n\r\"'", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end
+		}
+	}
+	return tokens
+}
+
+// joinPredicateTokens joins the tokens with a single space between words and
+// string literals, and no space around operator characters
+func joinPredicateTokens(tokens []string) string {
+	var b strings.Builder
+	for i, token := range tokens {
+		if i > 0 && !isPredicateOperator(tokens[i-1]) && !isPredicateOperator(token) {
+			b.WriteString(" ")
+		}
+		b.WriteString(token)
+	}
+	return b.String()
+}
+
+func isPredicateOperator(token string) bool {
+	return len(token) == 1 && strings.Contains(predicateOperatorChars, token)
+}
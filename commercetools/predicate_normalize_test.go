@@ -0,0 +1,33 @@
+package commercetools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePredicate(t *testing.T) {
+	cases := []struct {
+		old      string
+		new      string
+		suppress bool
+	}{
+		{`1=1`, `1 = 1`, true},
+		{"sku = \"a\"\n  and  quantity > 1", `sku="a" and quantity>1`, true},
+		{`a = 1 and b = 2`, `b = 2 and a = 1`, true},
+		{`a = 1 and (b = 2 or c = 3)`, `(b = 2 or c = 3) and a = 1`, true},
+		{`lineItemExists(sku = "a") and b != 2`, `b!=2 and lineItemExists( sku = "a" )`, true},
+		{`sku = "a b"`, `sku = "a  b"`, false},
+		{`sku = "a and b"`, `sku = "b and a"`, false},
+		{`a = 1 or b = 2 and c = 3`, `c = 3 and b = 2 or a = 1`, false},
+		{`a = 1 and b = 2`, `a = 1 or b = 2`, false},
+		{`a = 1 and b = 2`, `a = 1 and b = 3`, false},
+		{`a not in ("x") and b = 2`, `b = 2 and a not in ("x")`, false},
+		{`(a = 1 or b = 2) and c = 3`, `(b = 2 or a = 1) and c = 3`, false},
+		{`totalPrice > "10.00 EUR"`, `totalPrice >= "10.00 EUR"`, false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.suppress, diffSuppressPredicate("predicate", c.old, c.new, nil), "%s / %s", c.old, c.new)
+	}
+}
@@ -0,0 +1,149 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+// predicateReferencePattern matches comparisons of the customer group key or
+// the category ID with a string or a list of strings, for example
+// `customerGroup.key = "vip"` or `categories.id contains any ("a", "b")`
+var predicateReferencePattern = regexp.MustCompile(
+	`\b(customerGroup\.key|categories\.id)\s*(?:!?=|(?:not\s+)?in|contains(?:\s+(?:any|all))?)\s*(\([^)]*\)|"[^"]*")`)
+
+var predicateStringPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+
+// predicateReferences holds the entities referenced in a predicate
+type predicateReferences struct {
+	CustomerGroupKeys []string
+	CategoryIDs       []string
+}
+
+// parsePredicateReferences returns the customer group keys and category IDs
+// referenced in the predicate. This is a best effort parser which only
+// recognizes the common comparison forms.
+func parsePredicateReferences(predicate string) predicateReferences {
+	customerGroups := map[string]bool{}
+	categories := map[string]bool{}
+
+	for _, match := range predicateReferencePattern.FindAllStringSubmatch(predicate, -1) {
+		for _, value := range predicateStringPattern.FindAllStringSubmatch(match[2], -1) {
+			switch match[1] {
+			case "customerGroup.key":
+				customerGroups[value[1]] = true
+			case "categories.id":
+				categories[value[1]] = true
+			}
+		}
+	}
+
+	return predicateReferences{
+		CustomerGroupKeys: sortedKeys(customerGroups),
+		CategoryIDs:       sortedKeys(categories),
+	}
+}
+
+// predicateStorePattern matches predicates limiting a cart to stores, for
+// example `store.key = "berlin"` or `store.key in ("berlin", "munich")`.
+// Negated comparisons are not matched since they don't target the store.
+var predicateStorePattern = regexp.MustCompile(`\bstore\.key\s*(?:=|in)\s*(\([^)]*\)|"(?:[^"\\]|\\.)*")`)
+
+// parsePredicateStoreKeys returns the keys of the stores a predicate is
+// limited to. Like parsePredicateReferences this is a best effort parser.
+func parsePredicateStoreKeys(predicate string) []string {
+	stores := map[string]bool{}
+	for _, match := range predicateStorePattern.FindAllStringSubmatch(predicate, -1) {
+		for _, value := range predicateStringPattern.FindAllStringSubmatch(match[1], -1) {
+			stores[strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(value[1])] = true
+		}
+	}
+	return sortedKeys(stores)
+}
+
+func sortedKeys(values map[string]bool) []string {
+	result := make([]string, 0, len(values))
+	for k := range values {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// checkPredicateReferences verifies that the customer groups and categories
+// referenced in the predicate exist and returns an error listing the ones
+// which don't.
+func checkPredicateReferences(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, predicate string) error {
+	refs := parsePredicateReferences(predicate)
+	missing := []string{}
+
+	for _, key := range refs.CustomerGroupKeys {
+		_, err := client.CustomerGroups().WithKey(key).Get().Execute(ctx)
+		if err != nil {
+			if !isNotFoundError(err) {
+				return err
+			}
+			missing = append(missing, fmt.Sprintf("customer group with key %q", key))
+		}
+	}
+	for _, id := range refs.CategoryIDs {
+		_, err := client.Categories().WithId(id).Get().Execute(ctx)
+		if err != nil {
+			if !isNotFoundError(err) {
+				return err
+			}
+			missing = append(missing, fmt.Sprintf("category with id %q", id))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("predicate %q references entities which do not exist: %s",
+			predicate, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// validatePredicateReferences returns a CustomizeDiff function which checks
+// the references in the given predicate attributes when the resource has
+// validate_predicate_references enabled.
+func validatePredicateReferences(keys ...string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if !d.Get("validate_predicate_references").(bool) {
+			return nil
+		}
+
+		client := getClient(m)
+		for _, key := range keys {
+			changed := d.HasChange(key) || d.HasChange("validate_predicate_references")
+			if !changed || !d.NewValueKnown(key) {
+				continue
+			}
+			predicate, _ := d.Get(key).(string)
+			if predicate == "" {
+				continue
+			}
+			if err := checkPredicateReferences(ctx, client, predicate); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// validatePredicateReferencesSchema returns the schema of the attribute to
+// enable the predicate reference validation.
+func validatePredicateReferencesSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Check when planning that the customer groups (`customerGroup.key`) and categories " +
+			"(`categories.id`) referenced in the predicates exist. The references are found with a best " +
+			"effort parser and every reference requires an API call, so this is disabled by default",
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+}
@@ -0,0 +1,57 @@
+package commercetools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePredicateReferences(t *testing.T) {
+	cases := []struct {
+		predicate string
+		expected  predicateReferences
+	}{
+		{
+			`customer.customerGroup.key = "vip"`,
+			predicateReferences{CustomerGroupKeys: []string{"vip"}, CategoryIDs: []string{}},
+		},
+		{
+			`customerGroup.key in ("gold", "silver") and totalPrice > "10.00 EUR"`,
+			predicateReferences{CustomerGroupKeys: []string{"gold", "silver"}, CategoryIDs: []string{}},
+		},
+		{
+			`lineItemExists(categories.id contains any ("cat-2", "cat-1")) and categories.id = "cat-1"`,
+			predicateReferences{CustomerGroupKeys: []string{}, CategoryIDs: []string{"cat-1", "cat-2"}},
+		},
+		{
+			`sku = "customerGroup.key = \"vip\""`,
+			predicateReferences{CustomerGroupKeys: []string{}, CategoryIDs: []string{}},
+		},
+		{
+			`1 = 1`,
+			predicateReferences{CustomerGroupKeys: []string{}, CategoryIDs: []string{}},
+		},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, parsePredicateReferences(c.predicate), c.predicate)
+	}
+}
+
+func TestParsePredicateStoreKeys(t *testing.T) {
+	cases := []struct {
+		predicate string
+		expected  []string
+	}{
+		{`store.key = "berlin"`, []string{"berlin"}},
+		{`store.key in ("munich", "berlin") and totalPrice > "10.00 EUR"`, []string{"berlin", "munich"}},
+		{`(1 = 1) and store.key in ("say \"hi\"")`, []string{`say "hi"`}},
+		{`store.key != "berlin"`, []string{}},
+		{`store.key not in ("berlin")`, []string{}},
+		{`sku = "store.key = \"berlin\""`, []string{}},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, parsePredicateStoreKeys(c.predicate), c.predicate)
+	}
+}
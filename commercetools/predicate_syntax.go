@@ -0,0 +1,119 @@
+package commercetools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cartPredicateFields are the functions and fields which only exist in cart
+// predicates. Using them in the predicate of a lineItems or customLineItems
+// target is a common mistake which commercetools only reports when the
+// discount is created.
+var cartPredicateFields = map[string]bool{
+	"lineItemExists":           true,
+	"lineItemCount":            true,
+	"lineItemTotal":            true,
+	"lineItemNetTotal":         true,
+	"lineItemGrossTotal":       true,
+	"customLineItemExists":     true,
+	"customLineItemCount":      true,
+	"customLineItemTotal":      true,
+	"customLineItemNetTotal":   true,
+	"customLineItemGrossTotal": true,
+	"customer":                 true,
+	"customerEmail":            true,
+	"customerGroup":            true,
+	"shippingInfo":             true,
+	"shippingAddress":          true,
+	"billingAddress":           true,
+	"country":                  true,
+	"currency":                 true,
+	"store":                    true,
+}
+
+// predicateToken is a token of a predicate with its offset in runes, since
+// the offset is what a user needs to find the error in a long predicate
+type predicateToken struct {
+	text   string
+	offset int
+}
+
+// checkPredicateSyntax returns an error for the syntax errors which can be
+// found without knowing the fields of the predicate: string literals which
+// are not terminated and unbalanced parentheses or brackets.
+func checkPredicateSyntax(predicate string) error {
+	_, err := scanPredicate(predicate)
+	return err
+}
+
+// checkLineItemPredicate checks the syntax of the predicate of a lineItems or
+// customLineItems target and that it doesn't use cart predicate fields
+func checkLineItemPredicate(targetType string, predicate string) error {
+	tokens, err := scanPredicate(predicate)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		field := strings.SplitN(token.text, ".", 2)[0]
+		if cartPredicateFields[field] {
+			return fmt.Errorf(
+				"%s at offset %d is a cart predicate field, the predicate of a %s target is evaluated "+
+					"for every item and can only use the fields of the item", token.text, token.offset, targetType)
+		}
+	}
+	return nil
+}
+
+// scanPredicate returns the words of the predicate outside of string
+// literals. It uses the same rules as predicateTokens, but keeps the offsets
+// and returns an error instead of skipping over invalid input.
+func scanPredicate(predicate string) ([]predicateToken, error) {
+	var words []predicateToken
+	var open []predicateToken
+	closing := map[rune]string{')': "(", ']': "["}
+
+	runes := []rune(predicate)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("string starting at offset %d is not terminated", i)
+			}
+			i = end + 1
+		case r == '(' || r == '[':
+			open = append(open, predicateToken{text: string(r), offset: i})
+			i++
+		case r == ')' || r == ']':
+			if len(open) == 0 || open[len(open)-1].text != closing[r] {
+				return nil, fmt.Errorf("unexpected %q at offset %d", r, i)
+			}
+			open = open[:len(open)-1]
+			i++
+		case strings.ContainsRune(predicateOperatorChars, r):
+			i++
+		default:
+			end := i
+			for end < len(runes) && !strings.ContainsRune(predicateOperatorChars+" \t\n\r\"'", runes[end]) {
+				end++
+			}
+			words = append(words, predicateToken{text: string(runes[i:end]), offset: i})
+			i = end
+		}
+	}
+
+	if len(open) > 0 {
+		last := open[len(open)-1]
+		return nil, fmt.Errorf("%q at offset %d is not closed", last.text, last.offset)
+	}
+	return words, nil
+}
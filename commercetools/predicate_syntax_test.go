@@ -0,0 +1,40 @@
+package commercetools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPredicateSyntax(t *testing.T) {
+	cases := []struct {
+		predicate string
+		err       string
+	}{
+		{`1=1`, ""},
+		{`sku in ("a", "b)") and attributes.sizes contains any ["s", "m"]`, ""},
+		{`sku = "a \" b"`, ""},
+		{`sku = 'a`, "string starting at offset 6 is not terminated"},
+		{`(sku = "a"`, `"(" at offset 0 is not closed`},
+		{`sku = "a")`, `unexpected ')' at offset 9`},
+		{`sku in ("a"]`, `unexpected ']' at offset 11`},
+	}
+
+	for _, c := range cases {
+		err := checkPredicateSyntax(c.predicate)
+		if c.err == "" {
+			assert.NoError(t, err, c.predicate)
+		} else {
+			assert.EqualError(t, err, c.err, c.predicate)
+		}
+	}
+}
+
+func TestCheckLineItemPredicate(t *testing.T) {
+	assert.NoError(t, checkLineItemPredicate("lineItems", `custom.country = "DE" and attributes.store = "x"`))
+	assert.NoError(t, checkLineItemPredicate("lineItems", `sku = "customer.email"`))
+	assert.EqualError(t,
+		checkLineItemPredicate("customLineItems", `shippingInfo.shippingMethodName = "Express"`),
+		"shippingInfo.shippingMethodName at offset 0 is a cart predicate field, the predicate of a "+
+			"customLineItems target is evaluated for every item and can only use the fields of the item")
+}
@@ -1,13 +1,25 @@
 package commercetools
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/labd/commercetools-go-sdk/ctutils"
 	"github.com/labd/commercetools-go-sdk/platform"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
@@ -17,17 +29,29 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"client_id": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CTP_CLIENT_ID", nil),
-				Description: "The OAuth Client ID for a commercetools platform project. https://docs.commercetools.com/http-api-authorization",
-				Sensitive:   true,
+				Description: "The OAuth Client ID for a commercetools platform project. https://docs.commercetools.com/http-api-authorization. " +
+					"Required unless `access_token` is set",
+				Sensitive: true,
 			},
 			"client_secret": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CTP_CLIENT_SECRET", nil),
-				Description: "The OAuth Client Secret for a commercetools platform project. https://docs.commercetools.com/http-api-authorization",
-				Sensitive:   true,
+				Description: "The OAuth Client Secret for a commercetools platform project. https://docs.commercetools.com/http-api-authorization. " +
+					"Required unless `access_token` is set",
+				Sensitive: true,
+			},
+			"access_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CTP_ACCESS_TOKEN", nil),
+				Description: "An OAuth access token for the project, for example one fetched by a CI system or a vault. " +
+					"The provider sends it as bearer token instead of requesting tokens with the `client_id` and " +
+					"`client_secret`, which are not used then. The token is not refreshed, so it must be valid for the " +
+					"whole run",
+				Sensitive: true,
 			},
 			"project_key": {
 				Type:        schema.TypeString,
@@ -38,67 +62,295 @@ func Provider() *schema.Provider {
 			},
 			"scopes": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CTP_SCOPES", nil),
-				Description: "A list as string of OAuth scopes assigned to a project key, to access resources in a commercetools platform project. https://docs.commercetools.com/http-api-authorization",
+				Description: "A list as string of OAuth scopes assigned to a project key, to access resources in a commercetools platform project. https://docs.commercetools.com/http-api-authorization. " +
+					"Required unless `access_token` is set",
 			},
 			"api_url": {
-				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("CTP_API_URL", nil),
-				Description: "The API URL of the commercetools platform. https://docs.commercetools.com/http-api",
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CTP_API_URL", nil),
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description: "The API URL of the commercetools platform. https://docs.commercetools.com/http-api. " +
+					"Defaults to the API URL of the `region`",
 			},
 			"token_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CTP_AUTH_URL", nil),
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description: "The authentication URL of the commercetools platform. https://docs.commercetools.com/http-api-authorization. " +
+					"Defaults to the authentication URL of the `region`",
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CTP_REGION", defaultRegion),
+				ValidateFunc: validation.StringInSlice(regions, false),
+				Description: "The [region](https://docs.commercetools.com/api/general-concepts#regions) of the " +
+					"project, used for the `api_url` and `token_url` when they are not set. One of " +
+					"`" + strings.Join(regions, "`, `") + "`",
+			},
+			"environment": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CTP_ENVIRONMENT", productionEnvironment),
+				ValidateFunc: validation.StringInSlice(environments, false),
+				Description: "The environment of the project, one of `" + strings.Join(environments, "`, `") + "`. " +
+					"Personal data of deleted discount codes is only erased in `" + productionEnvironment + "`, " +
+					"which makes tearing down other environments faster. Defaults to `" + productionEnvironment + "`",
+			},
+			"proxy_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CTP_PROXY_URL", nil),
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				Description: "The URL of a proxy for all requests to commercetools, for example " +
+					"`http://proxy.example.com:3128`. It takes precedence over the `HTTPS_PROXY`, `HTTP_PROXY` " +
+					"and `NO_PROXY` environment variables, which are used when it is not set",
+			},
+			"max_parallel_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CTP_MAX_PARALLEL_REQUESTS", defaultMaxParallelRequests),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description: "The maximum number of write requests (everything except GET and HEAD requests) the " +
+					"provider sends to commercetools at the same time, independent of the parallelism of terraform. " +
+					"Lower it when running into rate limits",
+			},
+			"retry_budget": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CTP_RETRY_BUDGET", ""),
+				ValidateFunc: validateDuration,
+				Description: "The total time all resources together can spend on waiting for retries of failed " +
+					"requests, for example `5m`. Once it is used up requests are no longer retried, so an apply " +
+					"fails fast when commercetools keeps failing. Unlimited by default",
+			},
+			"correlation_id_prefix": {
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("CTP_AUTH_URL", nil),
-				Description: "The authentication URL of the commercetools platform. https://docs.commercetools.com/http-api-authorization",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CTP_CORRELATION_ID_PREFIX", ""),
+				Description: "A prefix for the `X-Correlation-ID` header of every request to commercetools, for " +
+					"example the ID of the CI build, so the requests of a run can be found in the logs of " +
+					"commercetools. Requests without a correlation ID get a unique one with the prefix",
+			},
+			"wait_for_maintenance": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CTP_WAIT_FOR_MAINTENANCE", false),
+				Description: "Wait up to 30 minutes when the project is under maintenance and retry the requests once " +
+					"it is over, for example for scheduled applies. The wait ends with the timeout of the resource " +
+					"operation, which is 20 minutes or less for most resources. By default the apply fails right away",
+			},
+			"conflict_strategy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CTP_CONFLICT_STRATEGY", conflictStrategyRetry),
+				ValidateFunc: validation.StringInSlice(conflictStrategies, false),
+				Description: "What to do when an update conflicts with a change made outside of terraform, one of " +
+					"`" + strings.Join(conflictStrategies, "`, `") + "`. With `" + conflictStrategyRetry + "` the " +
+					"update is applied to the current version of the resource, which overwrites the other change " +
+					"of the updated fields. With `" + conflictStrategyFail + "` the update fails when the resource " +
+					"was changed after terraform read it, so the change can be reviewed with a new plan. Used by " +
+					"`commercetools_discount_code`, `commercetools_discount_code_batch` and " +
+					"`commercetools_cart_discount_activation`. Defaults to `" +
+					conflictStrategyRetry + "`",
+			},
+			"required_name_locales": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					ValidateFunc: validation.StringMatch(
+						regexp.MustCompile("^[a-z]{2}(-[A-Z]{2})?$"),
+						"Locales must match pattern ^[a-z]{2}(-[A-Z]{2})?$",
+					),
+				},
+				Description: "The locales which the `name` of every `commercetools_discount_code` must contain, " +
+					"for example `[\"en\"]`. A `description` must contain them as well when it is set. Violations " +
+					"fail the plan",
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"commercetools_api_client":         resourceAPIClient(),
-			"commercetools_api_extension":      resourceAPIExtension(),
-			"commercetools_cart_discount":      resourceCartDiscount(),
-			"commercetools_channel":            resourceChannel(),
-			"commercetools_custom_object":      resourceCustomObject(),
-			"commercetools_customer_group":     resourceCustomerGroup(),
-			"commercetools_discount_code":      resourceDiscountCode(),
-			"commercetools_product_type":       resourceProductType(),
-			"commercetools_project_settings":   resourceProjectSettings(),
-			"commercetools_shipping_method":    resourceShippingMethod(),
-			"commercetools_shipping_zone_rate": resourceShippingZoneRate(),
-			"commercetools_shipping_zone":      resourceShippingZone(),
-			"commercetools_state":              resourceState(),
-			"commercetools_store":              resourceStore(),
-			"commercetools_subscription":       resourceSubscription(),
-			"commercetools_tax_category_rate":  resourceTaxCategoryRate(),
-			"commercetools_tax_category":       resourceTaxCategory(),
-			"commercetools_category":           resourceCategory(),
-			"commercetools_type":               resourceType(),
+			"commercetools_api_client":                     resourceAPIClient(),
+			"commercetools_api_extension":                  resourceAPIExtension(),
+			"commercetools_cart_discount":                  resourceCartDiscount(),
+			"commercetools_cart_discount_activation":       resourceCartDiscountActivation(),
+			"commercetools_channel":                        resourceChannel(),
+			"commercetools_custom_object":                  resourceCustomObject(),
+			"commercetools_customer_group":                 resourceCustomerGroup(),
+			"commercetools_discount_code":                  resourceDiscountCode(),
+			"commercetools_discount_code_batch":            resourceDiscountCodeBatch(),
+			"commercetools_discount_code_group_membership": resourceDiscountCodeGroupMembership(),
+			"commercetools_payment":                        resourcePayment(),
+			"commercetools_product":                        resourceProduct(),
+			"commercetools_product_type":                   resourceProductType(),
+			"commercetools_project_settings":               resourceProjectSettings(),
+			"commercetools_shipping_method":                resourceShippingMethod(),
+			"commercetools_shipping_zone_rate":             resourceShippingZoneRate(),
+			"commercetools_shipping_zone":                  resourceShippingZone(),
+			"commercetools_shopping_list":                  resourceShoppingList(),
+			"commercetools_state":                          resourceState(),
+			"commercetools_store":                          resourceStore(),
+			"commercetools_subscription":                   resourceSubscription(),
+			"commercetools_tax_category_rate":              resourceTaxCategoryRate(),
+			"commercetools_tax_category":                   resourceTaxCategory(),
+			"commercetools_category":                       resourceCategory(),
+			"commercetools_type":                           resourceType(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"commercetools_api_extension":            dataSourceAPIExtension(),
+			"commercetools_cart_discount_stacking":   dataSourceCartDiscountStacking(),
+			"commercetools_cart_discounts":           dataSourceCartDiscounts(),
+			"commercetools_category":                 dataSourceCategory(),
+			"commercetools_customer":                 dataSourceCustomer(),
+			"commercetools_customer_group":           dataSourceCustomerGroup(),
+			"commercetools_discount_code":            dataSourceDiscountCode(),
+			"commercetools_discount_code_imports":    dataSourceDiscountCodeImports(),
+			"commercetools_discount_code_simulation": dataSourceDiscountCodeSimulation(),
+			"commercetools_discount_codes":           dataSourceDiscountCodes(),
+			"commercetools_line_item_predicate":      dataSourceLineItemPredicate(),
+			"commercetools_project_health":           dataSourceProjectHealth(),
+			"commercetools_shipping_method":          dataSourceShippingMethod(),
+			"commercetools_store":                    dataSourceStore(),
+			"commercetools_tax_category":             dataSourceTaxCategory(),
+			"commercetools_type":                     dataSourceType(),
+			"commercetools_zone":                     dataSourceZone(),
 		},
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
 	}
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	clientID := d.Get("client_id").(string)
-	clientSecret := d.Get("client_secret").(string)
+// providerMeta is passed as meta to all resources and data sources. It holds
+// the configured client and state which is shared by all resources of the
+// provider, like cached API responses.
+type providerMeta struct {
+	client      *platform.ByProjectKeyRequestBuilder
+	projectKey  string
+	environment string
+
+	// tokenSource returns the token of the provider credentials, it is only
+	// used to inspect the token since the client requests its own tokens
+	tokenSource oauth2.TokenSource
+
+	// writeSemaphore limits the number of concurrent write requests, it is
+	// used by the transport of the client
+	writeSemaphore chan struct{}
+
+	// retryBudget is shared by the retries of all resources, nil when the
+	// retries are not limited
+	retryBudget *retryBudget
+
+	// waitForMaintenance is set when requests which fail because of a
+	// maintenance of the project are retried
+	waitForMaintenance bool
+
+	// conflictStrategy is one of conflictStrategies, an empty strategy
+	// retries like conflictStrategyRetry
+	conflictStrategy string
+
+	// requiredNameLocales are the locales discount code names must contain
+	requiredNameLocales []string
+
+	// clientID is the API client of the provider credentials, empty when an
+	// access token is configured
+	clientID string
+
+	// plannedCodes are the codes of the discount code resources planned by
+	// this provider, see planDiscountCode
+	plannedCodesMu sync.Mutex
+	plannedCodes   map[string]plannedDiscountCode
+
+	projectMu sync.Mutex
+	project   *platform.Project
+}
+
+// getProviderMeta returns the typed meta of the provider
+func getProviderMeta(m interface{}) *providerMeta {
+	return m.(*providerMeta)
+}
+
+// dataErasure returns whether personal data should be erased when deleting
+// resources, which is only required in production
+func (p *providerMeta) dataErasure() bool {
+	return p.environment == "" || p.environment == productionEnvironment
+}
+
+// cachedProject returns the project settings, which are fetched once. Only use
+// it for validations which can handle slightly outdated settings, the project
+// can be changed by a commercetools_project_settings resource in the same run.
+func (p *providerMeta) cachedProject(ctx context.Context) (*platform.Project, error) {
+	p.projectMu.Lock()
+	defer p.projectMu.Unlock()
+
+	if p.project == nil {
+		project, err := p.client.Get().Execute(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.project = project
+	}
+	return p.project, nil
+}
+
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	return configureProvider(ctx, d, noopRequestHook{})
+}
+
+// configureProvider configures the client of the provider, the hook is called
+// around all of its requests
+func configureProvider(ctx context.Context, d *schema.ResourceData, hook RequestHook) (interface{}, diag.Diagnostics) {
 	projectKey := d.Get("project_key").(string)
-	scopesRaw := d.Get("scopes").(string)
-	apiURL := d.Get("api_url").(string)
-	authURL := d.Get("token_url").(string)
+	apiURL, authURL := regionURLs(
+		d.Get("region").(string), d.Get("api_url").(string), d.Get("token_url").(string))
 
-	oauthScopes := strings.Split(scopesRaw, " ")
+	var budget *retryBudget
+	if val := d.Get("retry_budget").(string); val != "" {
+		total, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		budget = newRetryBudget(total)
+	}
 
-	oauth2Config := &clientcredentials.Config{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		Scopes:       oauthScopes,
-		TokenURL:     fmt.Sprintf("%s/oauth/token", authURL),
+	base, err := baseTransport(d.Get("proxy_url").(string))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	var transport http.RoundTripper = &requestHookTransport{
+		base: base,
+		hook: hook,
+	}
+	if prefix := d.Get("correlation_id_prefix").(string); prefix != "" {
+		transport = &correlationIDTransport{
+			base:   transport,
+			prefix: prefix,
+		}
 	}
 
+	writeSemaphore := make(chan struct{}, d.Get("max_parallel_requests").(int))
 	httpCLient := &http.Client{
-		Transport: ctutils.DebugTransport,
+		Transport: &rateLimitTransport{
+			base: &writeLimitTransport{
+				base:      transport,
+				semaphore: writeSemaphore,
+			},
+		},
+	}
+
+	oauth2Config, tokenSource, err := providerCredentials(d, authURL, httpCLient)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	if oauth2Config == nil {
+		// The SDK only supports client credentials, so the static token is
+		// added by the transport of the client instead
+		httpCLient = &http.Client{
+			Transport: &oauth2.Transport{Source: tokenSource, Base: httpCLient.Transport},
+		}
 	}
 
 	client, err := platform.NewClient(&platform.ClientConfig{
@@ -109,10 +361,227 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, diag.FromErr(err)
+	}
+
+	clientID := ""
+	if oauth2Config != nil {
+		clientID = oauth2Config.ClientID
+	}
+
+	return &providerMeta{
+		client:         client.WithProjectKey(projectKey),
+		clientID:       clientID,
+		projectKey:     projectKey,
+		environment:    d.Get("environment").(string),
+		tokenSource:    tokenSource,
+		writeSemaphore: writeSemaphore,
+		retryBudget:    budget,
+
+		waitForMaintenance:  d.Get("wait_for_maintenance").(bool),
+		conflictStrategy:    d.Get("conflict_strategy").(string),
+		requiredNameLocales: expandStringArray(d.Get("required_name_locales").([]interface{})),
+	}, nil
+}
+
+// providerCredentials returns the client credentials configuration and its
+// token source. When an access token is configured the configuration is nil
+// and the token source returns the access token.
+func providerCredentials(d *schema.ResourceData, authURL string, httpClient *http.Client) (*clientcredentials.Config, oauth2.TokenSource, error) {
+	if accessToken := d.Get("access_token").(string); accessToken != "" {
+		return nil, oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: accessToken,
+			TokenType:   "Bearer",
+		}), nil
+	}
+
+	clientID := d.Get("client_id").(string)
+	clientSecret := d.Get("client_secret").(string)
+	scopesRaw := d.Get("scopes").(string)
+	if clientID == "" || clientSecret == "" || scopesRaw == "" {
+		return nil, nil, fmt.Errorf(
+			"either access_token or client_id, client_secret and scopes must be set in the provider configuration")
+	}
+
+	oauth2Config := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       strings.Split(scopesRaw, " "),
+		TokenURL:     fmt.Sprintf("%s/oauth/token", authURL),
+	}
+	tokenSource := oauth2Config.TokenSource(
+		context.WithValue(context.Background(), oauth2.HTTPClient, httpClient))
+	return oauth2Config, tokenSource, nil
+}
+
+// regions are the regions of the commercetools cloud
+var regions = []string{
+	"europe-west1.gcp",
+	"us-central1.gcp",
+	"australia-southeast1.gcp",
+	"eu-central-1.aws",
+	"us-east-2.aws",
+}
+
+const defaultRegion = "europe-west1.gcp"
+
+// regionURLs returns the API and authentication URLs. URLs which are not
+// set default to the URLs of the region.
+func regionURLs(region, apiURL, authURL string) (string, string) {
+	if apiURL == "" {
+		apiURL = fmt.Sprintf("https://api.%s.commercetools.com", region)
+	}
+	if authURL == "" {
+		authURL = fmt.Sprintf("https://auth.%s.commercetools.com", region)
+	}
+	return strings.TrimSuffix(apiURL, "/"), strings.TrimSuffix(authURL, "/")
+}
+
+const productionEnvironment = "production"
+
+// environments are the supported values of the environment setting
+var environments = []string{
+	productionEnvironment,
+	"staging",
+	"development",
+	"test",
+}
+
+// baseTransport returns the transport which sends the requests to
+// commercetools. Without a proxy URL this is the debug transport of the SDK,
+// which uses the proxy environment variables like the default transport. The
+// transport of the SDK can't be configured, so with a proxy URL the requests
+// are logged by debugTransport instead.
+func baseTransport(proxyURL string) (http.RoundTripper, error) {
+	if proxyURL == "" {
+		return ctutils.DebugTransport, nil
+	}
+	proxy, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy_url: %w", err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxy)
+	return &debugTransport{base: transport}, nil
+}
+
+// debugTransport logs the requests and responses when CTP_DEBUG is set, like
+// the debug transport of the SDK
+type debugTransport struct {
+	base http.RoundTripper
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	debug := os.Getenv("CTP_DEBUG") != ""
+	if debug {
+		if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+			log.Printf("[DEBUG] Request to commercetools:\n%s", dump)
+		}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if debug {
+		if err != nil {
+			log.Printf("[DEBUG] Request to commercetools failed: %s", err)
+		} else if dump, dumpErr := httputil.DumpResponse(resp, true); dumpErr == nil {
+			log.Printf("[DEBUG] Response from commercetools:\n%s", dump)
+		}
+	}
+	return resp, err
+}
+
+// defaultMaxParallelRequests is high enough to not limit terraform with its
+// default parallelism of 10, but caps runaway concurrency
+const defaultMaxParallelRequests = 20
+
+// writeLimitTransport limits the number of write requests which are sent at
+// the same time. Reads are not limited.
+type writeLimitTransport struct {
+	base      http.RoundTripper
+	semaphore chan struct{}
+}
+
+func (t *writeLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	select {
+	case t.semaphore <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-t.semaphore }()
+
+	return t.base.RoundTrip(req)
+}
+
+// correlationIDTransport prefixes the X-Correlation-ID header of the requests,
+// so the requests of a run can be found in the logs of commercetools. Requests
+// without a correlation ID get a unique one.
+type correlationIDTransport struct {
+	base   http.RoundTripper
+	prefix string
+}
+
+func (t *correlationIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	correlationID := req.Header.Get("X-Correlation-ID")
+	if correlationID == "" {
+		correlationID = newCorrelationID()
+	}
+
+	// A transport must not modify the request, which is reused for retries
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Correlation-ID", t.prefix+correlationID)
+	return t.base.RoundTrip(req)
+}
+
+// maxRetryAfter caps the time to wait for a rate limited request, so a bogus
+// Retry-After header doesn't block terraform
+const maxRetryAfter = time.Minute
+
+// rateLimitError is returned for 429 responses. The SDK doesn't handle the
+// status code and drops the response headers, so the transport returns the
+// Retry-After value as an error which handleCommercetoolsError can inspect.
+type rateLimitError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("rate limited by commercetools, retry after %s", e.retryAfter)
+}
+
+// rateLimitTransport converts 429 responses into a rateLimitError
+type rateLimitTransport struct {
+	base http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	resp.Body.Close()
+	return nil, &rateLimitError{retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date. Missing or invalid values result in 0.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	var result time.Duration
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		result = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		result = date.Sub(now)
 	}
 
-	return client.WithProjectKey(projectKey), err
+	switch {
+	case result < 0:
+		return 0
+	case result > maxRetryAfter:
+		return maxRetryAfter
+	}
+	return result
 }
 
 // This is a global MutexKV for use within this plugin.
@@ -2,11 +2,18 @@ package commercetools
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/labd/commercetools-go-sdk/ctutils"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
 )
 
 var testAccProviders map[string]*schema.Provider
@@ -29,6 +36,109 @@ func TestProvider_impl(t *testing.T) {
 	var _ = Provider()
 }
 
+func TestRegionURLs(t *testing.T) {
+	apiURL, authURL := regionURLs("us-central1.gcp", "", "")
+	assert.Equal(t, "https://api.us-central1.gcp.commercetools.com", apiURL)
+	assert.Equal(t, "https://auth.us-central1.gcp.commercetools.com", authURL)
+
+	apiURL, authURL = regionURLs(defaultRegion, "https://api.example.com/", "")
+	assert.Equal(t, "https://api.example.com", apiURL)
+	assert.Equal(t, "https://auth.europe-west1.gcp.commercetools.com", authURL)
+}
+
+func TestProviderMetaDataErasure(t *testing.T) {
+	assert.True(t, (&providerMeta{}).dataErasure())
+	assert.True(t, (&providerMeta{environment: productionEnvironment}).dataErasure())
+	assert.False(t, (&providerMeta{environment: "development"}).dataErasure())
+}
+
+func TestProviderMetaCachedProject(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/my-project", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"key": "my-project", "currencies": ["EUR", "USD"]}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+
+	meta := &providerMeta{
+		client:     client.WithProjectKey("my-project"),
+		projectKey: "my-project",
+	}
+	assert.Equal(t, meta.client, getClient(meta))
+
+	for i := 0; i < 2; i++ {
+		project, err := meta.cachedProject(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"EUR", "USD"}, project.Currencies)
+	}
+	assert.Equal(t, 1, requests)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 2*time.Second, parseRetryAfter("2", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("Sun, 01 Aug 2021 12:00:30 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Sun, 01 Aug 2021 11:00:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, maxRetryAfter, parseRetryAfter("3600", now))
+}
+
+func TestWriteLimitTransport(t *testing.T) {
+	var mu sync.Mutex
+	current, max := map[string]int{}, map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		current[r.Method]++
+		if current[r.Method] > max[r.Method] {
+			max[r.Method] = current[r.Method]
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		current[r.Method]--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &writeLimitTransport{
+			base:      http.DefaultTransport,
+			semaphore: make(chan struct{}, 2),
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		for _, method := range []string{http.MethodGet, http.MethodPost} {
+			wg.Add(1)
+			go func(method string) {
+				defer wg.Done()
+				req, _ := http.NewRequest(method, server.URL, nil)
+				resp, err := client.Do(req)
+				if assert.NoError(t, err) {
+					resp.Body.Close()
+				}
+			}(method)
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, max[http.MethodPost])
+	assert.Greater(t, max[http.MethodGet], 2)
+}
+
 func testAccPreCheck(t *testing.T) {
 	requiredEnvs := []string{
 		"CTP_CLIENT_ID",
@@ -55,3 +165,126 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestProviderConfigureAccessToken(t *testing.T) {
+	for _, name := range []string{"CTP_CLIENT_ID", "CTP_CLIENT_SECRET", "CTP_SCOPES", "CTP_ACCESS_TOKEN"} {
+		t.Setenv(name, "")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/my-project", r.URL.Path)
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"key": "my-project"}`))
+	}))
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"access_token": "my-token",
+		"project_key":  "my-project",
+		"api_url":      server.URL,
+	})
+	meta, diags := providerConfigure(context.Background(), d)
+	assert.False(t, diags.HasError())
+
+	project, err := getProviderMeta(meta).client.Get().Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "my-project", project.Key)
+
+	token, err := getProviderMeta(meta).tokenSource.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "my-token", token.AccessToken)
+}
+
+func TestProviderConfigureMissingCredentials(t *testing.T) {
+	for _, name := range []string{"CTP_CLIENT_ID", "CTP_CLIENT_SECRET", "CTP_SCOPES", "CTP_ACCESS_TOKEN"} {
+		t.Setenv(name, "")
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"client_id":   "my-client",
+		"project_key": "my-project",
+		"scopes":      "manage_project:my-project",
+	})
+	_, diags := providerConfigure(context.Background(), d)
+	if assert.Len(t, diags, 1) {
+		assert.Contains(t, diags[0].Summary, "either access_token or client_id, client_secret and scopes must be set")
+	}
+}
+
+func TestProviderConfigureProxyURL(t *testing.T) {
+	for _, name := range []string{"CTP_CLIENT_ID", "CTP_CLIENT_SECRET", "CTP_SCOPES", "CTP_PROXY_URL"} {
+		t.Setenv(name, "")
+	}
+
+	var host string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.URL.Host
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"key": "my-project"}`))
+	}))
+	defer proxy.Close()
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"access_token": "my-token",
+		"project_key":  "my-project",
+		"api_url":      "http://api.commercetools.invalid",
+		"proxy_url":    proxy.URL,
+	})
+	meta, diags := providerConfigure(context.Background(), d)
+	assert.False(t, diags.HasError())
+
+	_, err := getProviderMeta(meta).client.Get().Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "api.commercetools.invalid", host)
+}
+
+func TestProviderConfigureCorrelationIDPrefix(t *testing.T) {
+	for _, name := range []string{"CTP_CLIENT_ID", "CTP_CLIENT_SECRET", "CTP_SCOPES", "CTP_CORRELATION_ID_PREFIX"} {
+		t.Setenv(name, "")
+	}
+
+	var correlationIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationIDs = append(correlationIDs, r.Header.Get("X-Correlation-ID"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"key": "my-project"}`))
+	}))
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"access_token":          "my-token",
+		"project_key":           "my-project",
+		"api_url":               server.URL,
+		"correlation_id_prefix": "build-42-",
+	})
+	meta, diags := providerConfigure(context.Background(), d)
+	assert.False(t, diags.HasError())
+
+	client := getProviderMeta(meta).client
+	_, err := client.Get().Execute(context.Background())
+	assert.NoError(t, err)
+	headers := http.Header{}
+	headers.Set("X-Correlation-ID", "create-code")
+	_, err = client.Get().WithHeaders(headers).Execute(context.Background())
+	assert.NoError(t, err)
+
+	if assert.Len(t, correlationIDs, 2) {
+		assert.Regexp(t, "^build-42-terraform-provider-commercetools-[0-9]+$", correlationIDs[0])
+		assert.Equal(t, "build-42-create-code", correlationIDs[1])
+	}
+	assert.Equal(t, "create-code", headers.Get("X-Correlation-ID"))
+}
+
+func TestBaseTransport(t *testing.T) {
+	transport, err := baseTransport("")
+	assert.NoError(t, err)
+	assert.Equal(t, ctutils.DebugTransport, transport)
+
+	transport, err = baseTransport("http://proxy.example.com:3128")
+	assert.NoError(t, err)
+	req, _ := http.NewRequest(http.MethodGet, "https://api.europe-west1.gcp.commercetools.com", nil)
+	proxyURL, err := transport.(*debugTransport).base.(*http.Transport).Proxy(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "proxy.example.com:3128", proxyURL.Host)
+}
@@ -0,0 +1,65 @@
+package commercetools
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// RequestHook is notified about every request the provider sends to
+// commercetools, including the requests for OAuth tokens and retries, for
+// example to export traces or metrics to your own telemetry. The request
+// context is the context of the resource operation.
+//
+// The hook is called concurrently. It must not modify the request or read the
+// body of the response.
+type RequestHook interface {
+	// OnRequest is called before the request is sent
+	OnRequest(req *http.Request)
+
+	// OnResponse is called when the response is received, or with the error
+	// when the request failed
+	OnResponse(req *http.Request, resp *http.Response, err error, duration time.Duration)
+}
+
+// noopRequestHook is the RequestHook of Provider
+type noopRequestHook struct{}
+
+func (noopRequestHook) OnRequest(req *http.Request) {}
+
+func (noopRequestHook) OnResponse(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+}
+
+// ProviderWithRequestHook returns the provider like Provider, with a hook
+// which is called around every request to commercetools. Use it in your own
+// build of the provider to observe the API interactions:
+//
+//	plugin.Serve(&plugin.ServeOpts{
+//		ProviderFunc: func() *schema.Provider {
+//			return commercetools.ProviderWithRequestHook(myHook)
+//		},
+//	})
+func ProviderWithRequestHook(hook RequestHook) *schema.Provider {
+	provider := Provider()
+	provider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return configureProvider(ctx, d, hook)
+	}
+	return provider
+}
+
+// requestHookTransport calls the hook around the requests
+type requestHookTransport struct {
+	base http.RoundTripper
+	hook RequestHook
+}
+
+func (t *requestHookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.hook.OnRequest(req)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	t.hook.OnResponse(req, resp, err, time.Since(start))
+	return resp, err
+}
@@ -0,0 +1,71 @@
+package commercetools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingRequestHook records the requests and the status of their responses
+type recordingRequestHook struct {
+	mu        sync.Mutex
+	requests  []string
+	responses []int
+	errors    []error
+}
+
+func (h *recordingRequestHook) OnRequest(req *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests = append(h.requests, req.Method+" "+req.URL.Path)
+}
+
+func (h *recordingRequestHook) OnResponse(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err != nil {
+		h.errors = append(h.errors, err)
+		return
+	}
+	h.responses = append(h.responses, resp.StatusCode)
+}
+
+func TestProviderWithRequestHook(t *testing.T) {
+	for _, name := range []string{"CTP_CLIENT_ID", "CTP_CLIENT_SECRET", "CTP_SCOPES", "CTP_ACCESS_TOKEN", "CTP_PROXY_URL"} {
+		t.Setenv(name, "")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"key": "my-project"}`))
+	}))
+	defer server.Close()
+
+	hook := &recordingRequestHook{}
+	provider := ProviderWithRequestHook(hook)
+	d := schema.TestResourceDataRaw(t, provider.Schema, map[string]interface{}{
+		"access_token": "my-token",
+		"project_key":  "my-project",
+		"api_url":      server.URL,
+	})
+	meta, diags := provider.ConfigureContextFunc(context.Background(), d)
+	assert.False(t, diags.HasError())
+
+	_, err := getProviderMeta(meta).client.Get().Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GET /my-project"}, hook.requests)
+	assert.Equal(t, []int{200}, hook.responses)
+
+	// Failed requests are passed to the hook with their error
+	server.Close()
+	_, err = getProviderMeta(meta).client.Get().Execute(context.Background())
+	assert.Error(t, err)
+	assert.Len(t, hook.requests, 2)
+	assert.Len(t, hook.errors, 1)
+}
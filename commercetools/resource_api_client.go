@@ -65,7 +65,7 @@ func resourceAPIClientCreate(ctx context.Context, d *schema.ResourceData, m inte
 
 	var apiClient *platform.ApiClient
 
-	err := resource.RetryContext(ctx, 20*time.Second, func() *resource.RetryError {
+	err := retryContext(ctx, m, "create api client", 20*time.Second, func() *resource.RetryError {
 		var err error
 
 		apiClient, err = client.ApiClients().Post(draft).Execute(ctx)
@@ -76,7 +76,7 @@ func resourceAPIClientCreate(ctx context.Context, d *schema.ResourceData, m inte
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	d.SetId(apiClient.ID)
@@ -96,7 +96,7 @@ func resourceAPIClientRead(ctx context.Context, d *schema.ResourceData, m interf
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	d.SetId(apiClient.ID)
@@ -112,7 +112,7 @@ func resourceAPIClientDelete(ctx context.Context, d *schema.ResourceData, m inte
 
 	_, err := client.ApiClients().WithId(d.Id()).Delete().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
@@ -23,13 +23,19 @@ func resourceAPIExtension() *schema.Resource {
 		ReadContext:   resourceAPIExtensionRead,
 		UpdateContext: resourceAPIExtensionUpdate,
 		DeleteContext: resourceAPIExtensionDelete,
-		SchemaVersion: 1,
+		CustomizeDiff: validateExtensionDestination,
+		SchemaVersion: 2,
 		StateUpgraders: []schema.StateUpgrader{
 			{
 				Type:    resourceAPIExtensionResourceV0().CoreConfigSchema().ImpliedType(),
 				Upgrade: migrateAPIExtensionStateV0toV1,
 				Version: 0,
 			},
+			{
+				Type:    resourceAPIExtensionResourceV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: migrateAPIExtensionStateV1toV2,
+				Version: 1,
+			},
 		},
 		Schema: map[string]*schema.Schema{
 			"key": {
@@ -46,36 +52,79 @@ func resourceAPIExtension() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"type": {
+							Description:  "HTTP or AWSLambda",
 							Type:         schema.TypeString,
 							Required:     true,
 							ValidateFunc: validateDestinationType,
 						},
 						// HTTP specific fields
 						"url": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"azure_authentication": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Description: "HTTP destination specific field. The URL of the extension",
+							Type:        schema.TypeString,
+							Optional:    true,
 						},
 						"authorization_header": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Description: "HTTP destination specific field. Authenticate using the " +
+								"[Authorization header](https://docs.commercetools.com/api/projects/api-extensions#authorizationheader)",
+							Type:          schema.TypeList,
+							MaxItems:      1,
+							Optional:      true,
+							ConflictsWith: []string{"destination.0.azure_authentication"},
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"header_value": {
+										Description: "The value of the Authorization header, for example `Basic 12345`",
+										Type:        schema.TypeString,
+										Required:    true,
+										Sensitive:   true,
+									},
+								},
+							},
+						},
+						"azure_authentication": {
+							Description: "HTTP destination specific field. Authenticate using " +
+								"[Azure Functions](https://docs.commercetools.com/api/projects/api-extensions#azurefunctions)",
+							Type:          schema.TypeList,
+							MaxItems:      1,
+							Optional:      true,
+							ConflictsWith: []string{"destination.0.authorization_header"},
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"key": {
+										Description: "The key of the Azure function",
+										Type:        schema.TypeString,
+										Required:    true,
+										Sensitive:   true,
+									},
+								},
+							},
 						},
 
 						// AWSLambda specific fields
 						"arn": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Description: "AWSLambda destination specific field. The ARN of the Lambda function",
+							Type:        schema.TypeString,
+							Optional:    true,
 						},
-						"access_key": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"access_secret": {
-							Type:     schema.TypeString,
+						"aws_credentials": {
+							Description: "AWSLambda destination specific field. The credentials of an IAM user " +
+								"which is allowed to invoke the Lambda function",
+							Type:     schema.TypeList,
+							MaxItems: 1,
 							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"access_key": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"access_secret": {
+										Type:      schema.TypeString,
+										Required:  true,
+										Sensitive: true,
+									},
+								},
+							},
 						},
 					},
 				},
@@ -128,6 +177,53 @@ func validateDestinationType(val interface{}, key string) (warns []string, errs
 	return
 }
 
+// validateExtensionDestination checks that only the fields belonging to the
+// destination type are set, so for example AWS credentials are not silently
+// ignored on an HTTP destination.
+func validateExtensionDestination(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	data := d.Get("destination").([]interface{})
+	if len(data) == 0 || data[0] == nil {
+		return nil
+	}
+	return validateExtensionDestinationFields(data[0].(map[string]interface{}))
+}
+
+func validateExtensionDestinationFields(input map[string]interface{}) error {
+	isSet := func(key string) bool {
+		switch v := input[key].(type) {
+		case string:
+			return v != ""
+		case []interface{}:
+			return len(v) > 0
+		}
+		return false
+	}
+
+	var allowed, forbidden []string
+	switch strings.ToLower(input["type"].(string)) {
+	case "http":
+		allowed = []string{"url", "authorization_header", "azure_authentication"}
+		forbidden = []string{"arn", "aws_credentials"}
+	case "awslambda":
+		allowed = []string{"arn", "aws_credentials"}
+		forbidden = []string{"url", "authorization_header", "azure_authentication"}
+		if !isSet("aws_credentials") {
+			return fmt.Errorf("the aws_credentials block is required for an AWSLambda destination")
+		}
+	default:
+		return nil
+	}
+
+	for _, key := range forbidden {
+		if isSet(key) {
+			return fmt.Errorf(
+				"%s cannot be used for a destination of type %s, only %s can be set",
+				key, input["type"], strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
 func resourceAPIExtensionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := getClient(m)
 	var extension *platform.Extension
@@ -135,7 +231,7 @@ func resourceAPIExtensionCreate(ctx context.Context, d *schema.ResourceData, m i
 	triggers := unmarshallExtensionTriggers(d)
 	destination, err := unmarshallExtensionDestination(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	draft := platform.ExtensionDraft{
@@ -145,7 +241,7 @@ func resourceAPIExtensionCreate(ctx context.Context, d *schema.ResourceData, m i
 		TimeoutInMs: intRef(d.Get("timeout_in_ms")),
 	}
 
-	err = resource.RetryContext(ctx, 20*time.Second, func() *resource.RetryError {
+	err = retryContext(ctx, m, "create api extension", 20*time.Second, func() *resource.RetryError {
 		var err error
 
 		extension, err = client.Extensions().Post(draft).Execute(ctx)
@@ -156,7 +252,7 @@ func resourceAPIExtensionCreate(ctx context.Context, d *schema.ResourceData, m i
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if extension == nil {
@@ -182,7 +278,7 @@ func resourceAPIExtensionRead(ctx context.Context, d *schema.ResourceData, m int
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if extension == nil {
@@ -226,7 +322,7 @@ func resourceAPIExtensionUpdate(ctx context.Context, d *schema.ResourceData, m i
 	if d.HasChange("destination") {
 		destination, err := unmarshallExtensionDestination(d)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		input.Actions = append(
 			input.Actions,
@@ -242,7 +338,7 @@ func resourceAPIExtensionUpdate(ctx context.Context, d *schema.ResourceData, m i
 
 	_, err := client.Extensions().WithId(d.Id()).Post(input).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceAPIExtensionRead(ctx, d, m)
@@ -253,7 +349,7 @@ func resourceAPIExtensionDelete(ctx context.Context, d *schema.ResourceData, m i
 	version := d.Get("version").(int)
 	_, err := client.Extensions().WithId(d.Id()).Delete().Version(version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 	return nil
 }
@@ -277,13 +373,21 @@ func unmarshallExtensionDestination(d *schema.ResourceData) (platform.Destinatio
 
 		return platform.HttpDestination{
 			Url:            input["url"].(string),
-			Authentication: &auth,
+			Authentication: auth,
 		}, nil
 	case "awslambda":
+		credentials, err := elementFromSlice(input, "aws_credentials")
+		if err != nil {
+			return nil, err
+		}
+		if credentials == nil {
+			return nil, fmt.Errorf("the aws_credentials block is required for an AWSLambda destination")
+		}
+
 		return platform.AWSLambdaDestination{
 			Arn:          input["arn"].(string),
-			AccessKey:    input["access_key"].(string),
-			AccessSecret: input["access_secret"].(string),
+			AccessKey:    credentials["access_key"].(string),
+			AccessSecret: credentials["access_secret"].(string),
 		}, nil
 	default:
 		return nil, fmt.Errorf("extension type %s not implemented", input["type"])
@@ -291,66 +395,65 @@ func unmarshallExtensionDestination(d *schema.ResourceData) (platform.Destinatio
 }
 
 func unmarshallExtensionDestinationAuthentication(destInput map[string]interface{}) (platform.HttpDestinationAuthentication, error) {
-	authKeys := [2]string{"authorization_header", "azure_authentication"}
-	count := 0
-	for _, key := range authKeys {
-		if value, ok := destInput[key]; ok {
-			if value != "" {
-				count++
-			}
-		}
+	header, err := elementFromSlice(destInput, "authorization_header")
+	if err != nil {
+		return nil, err
+	}
+	azure, err := elementFromSlice(destInput, "azure_authentication")
+	if err != nil {
+		return nil, err
 	}
-	if count > 1 {
+
+	if header != nil && azure != nil {
 		return nil, fmt.Errorf(
-			"in the destination only one of the auth values should be definied: %q", authKeys)
+			"in the destination only one of authorization_header and azure_authentication should be defined")
 	}
 
-	if val, ok := isNotEmpty(destInput, "authorization_header"); ok {
+	if header != nil {
 		return &platform.AuthorizationHeaderAuthentication{
-			HeaderValue: val.(string),
+			HeaderValue: header["header_value"].(string),
 		}, nil
 	}
-	if val, ok := isNotEmpty(destInput, "azure_authentication"); ok {
+	if azure != nil {
 		return &platform.AzureFunctionsAuthentication{
-			Key: val.(string),
+			Key: azure["key"].(string),
 		}, nil
 	}
 
 	return nil, nil
 }
 
-func marshallExtensionDestination(d platform.Destination) []map[string]string {
+func marshallExtensionDestination(d platform.Destination) []map[string]interface{} {
 	switch v := d.(type) {
 	case platform.HttpDestination:
+		result := map[string]interface{}{
+			"type": "HTTP",
+			"url":  v.Url,
+		}
 		switch a := v.Authentication.(type) {
 		case platform.AuthorizationHeaderAuthentication:
-			return []map[string]string{{
-				"type":                 "HTTP",
-				"url":                  v.Url,
-				"authorization_header": a.HeaderValue,
+			result["authorization_header"] = []map[string]interface{}{{
+				"header_value": a.HeaderValue,
 			}}
 		case platform.AzureFunctionsAuthentication:
-			return []map[string]string{{
-				"type":                 "HTTP",
-				"url":                  v.Url,
-				"azure_authentication": a.Key,
+			result["azure_authentication"] = []map[string]interface{}{{
+				"key": a.Key,
 			}}
 		}
-		return []map[string]string{{
-			"type": "HTTP",
-			"url":  v.Url,
-		}}
+		return []map[string]interface{}{result}
 
 	case platform.AWSLambdaDestination:
-		return []map[string]string{{
-			"type":          "awslambda",
-			"access_key":    v.AccessKey,
-			"access_secret": v.AccessSecret,
-			"arn":           v.Arn,
+		return []map[string]interface{}{{
+			"type": "awslambda",
+			"arn":  v.Arn,
+			"aws_credentials": []map[string]interface{}{{
+				"access_key":    v.AccessKey,
+				"access_secret": v.AccessSecret,
+			}},
 		}}
 
 	}
-	return []map[string]string{}
+	return []map[string]interface{}{}
 }
 
 func marshallExtensionTriggers(triggers []platform.ExtensionTrigger) []map[string]interface{} {
@@ -451,7 +554,125 @@ func resourceAPIExtensionResourceV0() *schema.Resource {
 	}
 }
 
+func resourceAPIExtensionResourceV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Description: "User-specific unique identifier for the extension",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"destination": {
+				Description: "[Destination](https://docs.commercetools.com/api/projects/api-extensions#destination) " +
+					"Details where the extension can be reached",
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateDestinationType,
+						},
+						// HTTP specific fields
+						"url": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"azure_authentication": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"authorization_header": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						// AWSLambda specific fields
+						"arn": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"access_key": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"access_secret": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"trigger": {
+				Description: "Array of [Trigger](https://docs.commercetools.com/api/projects/api-extensions#trigger) " +
+					"Describes what triggers the extension",
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource_type_id": {
+							Description: "Currently, cart, order, payment, and customer are supported",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"actions": {
+							Description: "Currently, Create and Update are supported",
+							Type:        schema.TypeList,
+							Required:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"timeout_in_ms": {
+				Description: "Extension timeout in milliseconds",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
 func migrateAPIExtensionStateV0toV1(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
 	transformToList(rawState, "destination")
 	return rawState, nil
 }
+
+// migrateAPIExtensionStateV1toV2 moves the flat authentication fields of the
+// destination into the nested blocks per authentication mode
+func migrateAPIExtensionStateV1toV2(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	destinations, ok := rawState["destination"].([]interface{})
+	if !ok || len(destinations) == 0 {
+		return rawState, nil
+	}
+	destination, ok := destinations[0].(map[string]interface{})
+	if !ok {
+		return rawState, nil
+	}
+
+	moveToBlock := func(block string, fields map[string]string) {
+		values := map[string]interface{}{}
+		for oldKey, newKey := range fields {
+			if val, ok := isNotEmpty(destination, oldKey); ok {
+				values[newKey] = val
+			}
+			delete(destination, oldKey)
+		}
+		if len(values) > 0 {
+			destination[block] = []interface{}{values}
+		} else {
+			delete(destination, block)
+		}
+	}
+
+	moveToBlock("authorization_header", map[string]string{"authorization_header": "header_value"})
+	moveToBlock("azure_authentication", map[string]string{"azure_authentication": "key"})
+	moveToBlock("aws_credentials", map[string]string{"access_key": "access_key", "access_secret": "access_secret"})
+	return rawState, nil
+}
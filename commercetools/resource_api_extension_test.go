@@ -16,10 +16,14 @@ import (
 
 func TestAPIExtensionUnmarshallExtensionDestination(t *testing.T) {
 	rawDestination := map[string]interface{}{
-		"type":          "AWSLambda",
-		"arn":           "arn:aws:lambda:eu-west-1:111111111:function:api_extensions",
-		"access_key":    "ABCSDF123123123",
-		"access_secret": "****abc/",
+		"type": "AWSLambda",
+		"arn":  "arn:aws:lambda:eu-west-1:111111111:function:api_extensions",
+		"aws_credentials": []interface{}{
+			map[string]interface{}{
+				"access_key":    "ABCSDF123123123",
+				"access_secret": "****abc/",
+			},
+		},
 	}
 
 	resourceDataMap := map[string]interface{}{
@@ -49,8 +53,12 @@ func TestAPIExtensionUnmarshallExtensionDestination(t *testing.T) {
 
 func TestAPIExtensionUnmarshallExtensionDestinationAuthentication(t *testing.T) {
 	var input = map[string]interface{}{
-		"authorization_header": "12345",
-		"azure_authentication": "AzureKey",
+		"authorization_header": []interface{}{
+			map[string]interface{}{"header_value": "12345"},
+		},
+		"azure_authentication": []interface{}{
+			map[string]interface{}{"key": "AzureKey"},
+		},
 	}
 
 	auth, err := unmarshallExtensionDestinationAuthentication(input)
@@ -58,15 +66,149 @@ func TestAPIExtensionUnmarshallExtensionDestinationAuthentication(t *testing.T)
 	assert.NotNil(t, err)
 
 	input = map[string]interface{}{
-		"authorization_header": "12345",
+		"authorization_header": []interface{}{
+			map[string]interface{}{"header_value": "12345"},
+		},
+		"azure_authentication": []interface{}{},
 	}
 
 	auth, err = unmarshallExtensionDestinationAuthentication(input)
 	httpAuth, ok := auth.(*platform.AuthorizationHeaderAuthentication)
 	assert.True(t, ok)
 	assert.Equal(t, "12345", httpAuth.HeaderValue)
-	assert.NotNil(t, auth)
 	assert.Nil(t, err)
+
+	input = map[string]interface{}{
+		"authorization_header": []interface{}{},
+		"azure_authentication": []interface{}{
+			map[string]interface{}{"key": "AzureKey"},
+		},
+	}
+
+	auth, err = unmarshallExtensionDestinationAuthentication(input)
+	azureAuth, ok := auth.(*platform.AzureFunctionsAuthentication)
+	assert.True(t, ok)
+	assert.Equal(t, "AzureKey", azureAuth.Key)
+	assert.Nil(t, err)
+
+	auth, err = unmarshallExtensionDestinationAuthentication(map[string]interface{}{})
+	assert.Nil(t, auth)
+	assert.Nil(t, err)
+}
+
+func TestAPIExtensionMarshallExtensionDestination(t *testing.T) {
+	result := marshallExtensionDestination(platform.HttpDestination{
+		Url:            "https://example.com",
+		Authentication: platform.AuthorizationHeaderAuthentication{HeaderValue: "Basic 12345"},
+	})
+	assert.Equal(t, []map[string]interface{}{{
+		"header_value": "Basic 12345",
+	}}, result[0]["authorization_header"])
+	assert.NotContains(t, result[0], "azure_authentication")
+
+	result = marshallExtensionDestination(platform.HttpDestination{
+		Url:            "https://example.com",
+		Authentication: platform.AzureFunctionsAuthentication{Key: "AzureKey"},
+	})
+	assert.Equal(t, []map[string]interface{}{{
+		"key": "AzureKey",
+	}}, result[0]["azure_authentication"])
+	assert.NotContains(t, result[0], "authorization_header")
+
+	result = marshallExtensionDestination(platform.AWSLambdaDestination{
+		Arn:          "arn:aws:lambda:eu-west-1:111111111:function:api_extensions",
+		AccessKey:    "ABCSDF123123123",
+		AccessSecret: "****abc/",
+	})
+	assert.Equal(t, "awslambda", result[0]["type"])
+	assert.Equal(t, []map[string]interface{}{{
+		"access_key":    "ABCSDF123123123",
+		"access_secret": "****abc/",
+	}}, result[0]["aws_credentials"])
+}
+
+func TestAPIExtensionValidateDestinationFields(t *testing.T) {
+	credentials := []interface{}{
+		map[string]interface{}{"access_key": "key", "access_secret": "secret"},
+	}
+	header := []interface{}{
+		map[string]interface{}{"header_value": "Basic 12345"},
+	}
+	azure := []interface{}{
+		map[string]interface{}{"key": "AzureKey"},
+	}
+
+	cases := []struct {
+		input map[string]interface{}
+		valid bool
+	}{
+		{map[string]interface{}{"type": "HTTP", "url": "https://example.com", "authorization_header": header}, true},
+		{map[string]interface{}{"type": "HTTP", "url": "https://example.com", "azure_authentication": azure}, true},
+		{map[string]interface{}{"type": "HTTP", "url": "https://example.com", "aws_credentials": credentials}, false},
+		{map[string]interface{}{"type": "HTTP", "url": "https://example.com", "arn": "arn:aws:lambda"}, false},
+		{map[string]interface{}{"type": "awslambda", "arn": "arn:aws:lambda", "aws_credentials": credentials}, true},
+		{map[string]interface{}{"type": "awslambda", "arn": "arn:aws:lambda", "aws_credentials": []interface{}{}}, false},
+		{map[string]interface{}{"type": "awslambda", "arn": "arn:aws:lambda", "aws_credentials": credentials, "authorization_header": header}, false},
+		{map[string]interface{}{"type": "AWSLambda", "arn": "arn:aws:lambda", "aws_credentials": credentials, "azure_authentication": azure}, false},
+	}
+	for _, c := range cases {
+		err := validateExtensionDestinationFields(c.input)
+		if c.valid {
+			assert.NoError(t, err, c.input)
+		} else {
+			assert.Error(t, err, c.input)
+		}
+	}
+}
+
+func TestMigrateAPIExtensionStateV1toV2(t *testing.T) {
+	state := map[string]interface{}{
+		"destination": []interface{}{
+			map[string]interface{}{
+				"type":                 "HTTP",
+				"url":                  "https://example.com",
+				"authorization_header": "Basic 12345",
+				"azure_authentication": "",
+				"arn":                  "",
+				"access_key":           "",
+				"access_secret":        "",
+			},
+		},
+	}
+	result, err := migrateAPIExtensionStateV1toV2(context.Background(), state, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"type": "HTTP",
+		"url":  "https://example.com",
+		"arn":  "",
+		"authorization_header": []interface{}{
+			map[string]interface{}{"header_value": "Basic 12345"},
+		},
+	}, result["destination"].([]interface{})[0])
+
+	state = map[string]interface{}{
+		"destination": []interface{}{
+			map[string]interface{}{
+				"type":                 "awslambda",
+				"url":                  "",
+				"authorization_header": "",
+				"azure_authentication": "",
+				"arn":                  "arn:aws:lambda",
+				"access_key":           "key",
+				"access_secret":        "secret",
+			},
+		},
+	}
+	result, err = migrateAPIExtensionStateV1toV2(context.Background(), state, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"type": "awslambda",
+		"url":  "",
+		"arn":  "arn:aws:lambda",
+		"aws_credentials": []interface{}{
+			map[string]interface{}{"access_key": "key", "access_secret": "secret"},
+		},
+	}, result["destination"].([]interface{})[0])
 }
 
 func TestUnmarshallExtensionTriggers(t *testing.T) {
@@ -143,9 +285,12 @@ resource "commercetools_api_extension" "ext" {
   timeout_in_ms = %d
 
   destination {
-    type                 = "HTTP"
-    url                  = "https://example.com"
-    authorization_header = "Basic 12345"
+    type = "HTTP"
+    url  = "https://example.com"
+
+    authorization_header {
+      header_value = "Basic 12345"
+    }
   }
 
   trigger {
@@ -163,9 +308,12 @@ resource "commercetools_api_extension" "ext" {
   timeout_in_ms = %d
 
   destination {
-    type                 = "HTTP"
-    url                  = "https://example.com"
-    authorization_header = "Basic 12345"
+    type = "HTTP"
+    url  = "https://example.com"
+
+    authorization_header {
+      header_value = "Basic 12345"
+    }
   }
 
   trigger {
@@ -2,13 +2,19 @@ package commercetools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"math/big"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/labd/commercetools-go-sdk/ctutils"
 	"github.com/labd/commercetools-go-sdk/platform"
 )
@@ -22,8 +28,17 @@ func resourceCartDiscount() *schema.Resource {
 		UpdateContext: resourceCartDiscountUpdate,
 		DeleteContext: resourceCartDiscountDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceCartDiscountImportState,
 		},
+		CustomizeDiff: customdiff.All(
+			validatePredicateReferences("predicate", "target.0.predicate"),
+			validateCartDiscountValue,
+			validateCartDiscountMoney,
+			validateCartDiscountTarget,
+			validateCartDiscountPredicates,
+			validateCartDiscountDistributionChannel,
+			validateCartDiscountUnconditional,
+		),
 		SchemaVersion: 1,
 		StateUpgraders: []schema.StateUpgrader{
 			{
@@ -41,13 +56,13 @@ func resourceCartDiscount() *schema.Resource {
 			"name": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Required:         true,
 			},
 			"description": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"value": {
@@ -65,14 +80,30 @@ func resourceCartDiscount() *schema.Resource {
 							ValidateFunc: validateValueType,
 						},
 						"permyriad": {
-							Description: "Relative discount specific fields",
-							Type:        schema.TypeInt,
-							Optional:    true,
+							Description: "Relative discount specific fields. The discount in 1/10000, so 1000 " +
+								"means a discount of 10%. Computed when `percent` is used. Discounted amounts with " +
+								"fractions of a cent are rounded by commercetools, the rounding can't be configured " +
+								"for a discount or the project",
+							Type:          schema.TypeInt,
+							Optional:      true,
+							Computed:      true,
+							ValidateFunc:  validation.IntBetween(0, 10000),
+							ConflictsWith: []string{"value.0.percent"},
+						},
+						"percent": {
+							Description: "Relative discount specific fields. Convenience alternative to `permyriad` " +
+								"which takes the discount as a percentage, so 10 means a discount of 10%. At most " +
+								"two decimals can be used, since the discount is stored as `permyriad`",
+							Type:          schema.TypeFloat,
+							Optional:      true,
+							ValidateFunc:  validation.All(validation.FloatBetween(0, 100), validatePercentPrecision),
+							ConflictsWith: []string{"value.0.permyriad"},
 						},
 						"money": {
-							Description: "Absolute discount specific fields",
-							Type:        schema.TypeList,
-							Optional:    true,
+							Description: "Absolute discount specific fields. One amount per currency, every " +
+								"currency must be configured in the project and can only be used once",
+							Type:     schema.TypeList,
+							Optional: true,
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"currency_code": {
@@ -105,7 +136,7 @@ func resourceCartDiscount() *schema.Resource {
 							Optional:    true,
 						},
 						"distribution_channel_id": {
-							Description: "Gift Line Item discount specific field",
+							Description: "Gift Line Item discount specific field, the channel needs the ProductDistribution role",
 							Type:        schema.TypeString,
 							Optional:    true,
 						},
@@ -113,9 +144,10 @@ func resourceCartDiscount() *schema.Resource {
 				},
 			},
 			"predicate": {
-				Description: "A valid [Cart Predicate](https://docs.commercetools.com/api/projects/predicates#cart-predicates)",
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      "A valid [Cart Predicate](https://docs.commercetools.com/api/projects/predicates#cart-predicates)",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: diffSuppressPredicate,
 			},
 			"target": {
 				Description: "Empty when the value has type giftLineItem, otherwise a " +
@@ -126,15 +158,19 @@ func resourceCartDiscount() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"type": {
-							Description:  "Supports lineItems/customLineItems/shipping",
+							Description:  "Supports lineItems/customLineItems/shipping/totalPrice",
 							Type:         schema.TypeString,
 							Required:     true,
 							ValidateFunc: validateTargetType,
 						},
 						"predicate": {
-							Description: "LineItems/CustomLineItems target specific fields",
-							Type:        schema.TypeString,
-							Optional:    true,
+							Description: "Required for lineItems/customLineItems targets. A " +
+								"[Line Item Predicate](https://docs.commercetools.com/api/projects/predicates#lineitem-field-identifiers) " +
+								"for the items to discount, which can only use the fields of the item and not cart fields " +
+								"like `customer` or `lineItemExists`",
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: diffSuppressPredicate,
 						},
 					},
 				},
@@ -142,9 +178,20 @@ func resourceCartDiscount() *schema.Resource {
 			"sort_order": {
 				Description: "The string must contain a number between 0 and 1. All matching cart discounts are " +
 					"applied to a cart in the order defined by this field. A discount with greater sort order is " +
-					"prioritized higher than a discount with lower sort order. The sort order is unambiguous among all cart discounts",
+					"prioritized higher than a discount with lower sort order. The sort order is unambiguous among all cart discounts. " +
+					"When omitted a free sort order is assigned on creation, below all existing cart discounts " +
+					"or right below `sort_order_below`. The assigned sort order is the middle of its neighbors, so " +
+					"it has one more decimal place than the lowest existing sort order, or than the neighbors",
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
+			},
+			"sort_order_below": {
+				Description: "The ID of a cart discount. When `sort_order` is omitted, the cart discount gets a " +
+					"sort order between that cart discount and the next lower one, so it is applied right after " +
+					"it. Only used when the cart discount is created",
+				Type:     schema.TypeString,
+				Optional: true,
 			},
 			"is_active": {
 				Description: "Only active discount can be applied to the cart",
@@ -153,18 +200,16 @@ func resourceCartDiscount() *schema.Resource {
 				Default:     true,
 			},
 			"valid_from": {
-				Type:     schema.TypeString,
-				Optional: true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					return compareCartDiscountValidDates(old, new)
-				},
+				Description:      "The time from which the discount can be applied on a cart. Before that time the discount is inactive",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressTime,
 			},
 			"valid_until": {
-				Type:     schema.TypeString,
-				Optional: true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					return compareCartDiscountValidDates(old, new)
-				},
+				Description:      "The time until the discount can be applied on a cart. After that time the discount is inactive",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressTime,
 			},
 			"requires_discount_code": {
 				Description: "States whether the discount can only be used in a connection with a " +
@@ -173,6 +218,14 @@ func resourceCartDiscount() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			"allow_unconditional": {
+				Description: "Confirm that the cart discount applies to every cart. Planning fails for an active " +
+					"cart discount which doesn't require a discount code and whose predicate matches every cart, " +
+					"like `1=1`, unless this is set, so a site-wide discount is never created by accident",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"stacking_mode": {
 				Description:  "Specifies whether the application of this discount causes the following discounts to be ignored",
 				Type:         schema.TypeString,
@@ -180,6 +233,7 @@ func resourceCartDiscount() *schema.Resource {
 				ValidateFunc: validateStackingMode,
 				Default:      "Stacking",
 			},
+			"validate_predicate_references": validatePredicateReferencesSchema(),
 			"version": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -201,12 +255,25 @@ func validateValueType(val interface{}, key string) (warns []string, errs []erro
 	return
 }
 
+// validatePercentPrecision rejects percentages which can't be converted to a
+// permyriad without rounding, like 12.345, so the configured discount is the
+// discount commercetools applies
+func validatePercentPrecision(val interface{}, key string) (warns []string, errs []error) {
+	permyriad := val.(float64) * 100
+	if math.Abs(permyriad-math.Round(permyriad)) > 1e-6 {
+		errs = append(errs, fmt.Errorf(
+			"%q has more than two decimals, which can't be stored as permyriad: %v", key, val))
+	}
+	return
+}
+
 func validateTargetType(val interface{}, key string) (warns []string, errs []error) {
 	switch val {
 	case
 		"lineItems",
 		"customLineItems",
-		"shipping":
+		"shipping",
+		"totalPrice":
 		return
 	default:
 		errs = append(errs, fmt.Errorf("%q not a valid value for %q", val, key))
@@ -235,12 +302,21 @@ func resourceCartDiscountCreate(ctx context.Context, d *schema.ResourceData, m i
 
 	value, err := unmarshallCartDiscountValue(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	stackingMode, err := unmarshallCartDiscountStackingMode(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
+	}
+
+	sortOrder := d.Get("sort_order").(string)
+	assignSortOrder := sortOrder == ""
+	if assignSortOrder {
+		// Cart discounts created in parallel would get the same sort order,
+		// so the sort order is assigned by one create at a time
+		ctMutexKV.Lock(cartDiscountSortOrderLock)
+		defer ctMutexKV.Unlock(cartDiscountSortOrderLock)
 	}
 
 	draft := platform.CartDiscountDraft{
@@ -249,7 +325,7 @@ func resourceCartDiscountCreate(ctx context.Context, d *schema.ResourceData, m i
 		Description:          &description,
 		Value:                &value,
 		CartPredicate:        d.Get("predicate").(string),
-		SortOrder:            d.Get("sort_order").(string),
+		SortOrder:            sortOrder,
 		IsActive:             boolRef(d.Get("is_active")),
 		RequiresDiscountCode: ctutils.BoolRef(d.Get("requires_discount_code").(bool)),
 		StackingMode:         &stackingMode,
@@ -258,37 +334,51 @@ func resourceCartDiscountCreate(ctx context.Context, d *schema.ResourceData, m i
 	if val, err := unmarshallCartDiscountTarget(d); err == nil {
 		draft.Target = val
 	} else {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if val := d.Get("valid_from").(string); len(val) > 0 {
 		validFrom, err := unmarshallTime(val)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		draft.ValidFrom = &validFrom
 	}
 	if val := d.Get("valid_until").(string); len(val) > 0 {
 		validUntil, err := unmarshallTime(val)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		draft.ValidUntil = &validUntil
 	}
 
-	errorResponse := resource.RetryContext(ctx, 1*time.Minute, func() *resource.RetryError {
+	errorResponse := retryContext(ctx, m, "create cart discount", 1*time.Minute, func() *resource.RetryError {
 		var err error
 
+		if assignSortOrder {
+			draft.SortOrder, err = nextCartDiscountSortOrder(ctx, client, d.Get("sort_order_below").(string))
+			if err != nil {
+				return handleCommercetoolsError(err)
+			}
+			log.Printf("[DEBUG] Assigned sort order %s to new cart discount", draft.SortOrder)
+		}
+
 		cartDiscount, err = client.CartDiscounts().Post(draft).Execute(ctx)
 
 		if err != nil {
+			if _, ok := duplicateFieldError(err, "sortOrder"); ok && assignSortOrder {
+				// Taken by a cart discount created outside of this run,
+				// retry with a new sort order
+				log.Printf("[DEBUG] Sort order %s is already used, assigning another one", draft.SortOrder)
+				return resource.RetryableError(err)
+			}
 			return handleCommercetoolsError(err)
 		}
 		return nil
 	})
 
 	if errorResponse != nil {
-		return diag.FromErr(errorResponse)
+		return diagnosticsFromError(errorResponse)
 	}
 
 	if cartDiscount == nil {
@@ -298,7 +388,11 @@ func resourceCartDiscountCreate(ctx context.Context, d *schema.ResourceData, m i
 	d.SetId(cartDiscount.ID)
 	d.Set("version", cartDiscount.Version)
 
-	return resourceCartDiscountRead(ctx, d, m)
+	var diags diag.Diagnostics
+	if summary := cartDiscountRolloutSummary(d, time.Now()); summary != nil {
+		diags = append(diags, *summary)
+	}
+	return append(diags, resourceCartDiscountRead(ctx, d, m)...)
 }
 
 func resourceCartDiscountRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -315,7 +409,7 @@ func resourceCartDiscountRead(ctx context.Context, d *schema.ResourceData, m int
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if cartDiscount == nil {
@@ -329,9 +423,9 @@ func resourceCartDiscountRead(ctx context.Context, d *schema.ResourceData, m int
 		d.Set("key", cartDiscount.Key)
 		d.Set("name", cartDiscount.Name)
 		d.Set("description", cartDiscount.Description)
-		d.Set("value", marshallCartDiscountValue(cartDiscount.Value))
+		d.Set("value", marshallCartDiscountValue(cartDiscount.Value, d.Get("value.0.percent").(float64) != 0))
 		d.Set("predicate", cartDiscount.CartPredicate)
-		d.Set("target", marshallCartDiscountTarget(cartDiscount.Target))
+		d.Set("target", marshallCartDiscountTarget(cartDiscount.Target, cartDiscount.Value))
 		d.Set("sort_order", cartDiscount.SortOrder)
 		d.Set("is_active", cartDiscount.IsActive)
 		d.Set("valid_from", marshallTime(cartDiscount.ValidFrom))
@@ -347,7 +441,7 @@ func resourceCartDiscountUpdate(ctx context.Context, d *schema.ResourceData, m i
 	client := getClient(m)
 	cartDiscount, err := client.CartDiscounts().WithId(d.Id()).Get().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input := platform.CartDiscountUpdate{
@@ -379,7 +473,7 @@ func resourceCartDiscountUpdate(ctx context.Context, d *schema.ResourceData, m i
 	if d.HasChange("value") {
 		value, err := unmarshallCartDiscountValue(d)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		input.Actions = append(
 			input.Actions,
@@ -403,7 +497,7 @@ func resourceCartDiscountUpdate(ctx context.Context, d *schema.ResourceData, m i
 				return diag.Errorf("Cannot change target to empty")
 			}
 		} else {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 
 	}
@@ -422,37 +516,11 @@ func resourceCartDiscountUpdate(ctx context.Context, d *schema.ResourceData, m i
 			&platform.CartDiscountChangeIsActiveAction{IsActive: newIsActive})
 	}
 
-	if d.HasChange("valid_from") {
-		if val := d.Get("valid_from").(string); len(val) > 0 {
-			newValidFrom, err := unmarshallTime(d.Get("valid_from").(string))
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			input.Actions = append(
-				input.Actions,
-				&platform.CartDiscountSetValidFromAction{ValidFrom: &newValidFrom})
-		} else {
-			input.Actions = append(
-				input.Actions,
-				&platform.CartDiscountSetValidFromAction{})
-		}
-	}
-
-	if d.HasChange("valid_until") {
-		if val := d.Get("valid_until").(string); len(val) > 0 {
-			newValidUntil, err := unmarshallTime(d.Get("valid_until").(string))
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			input.Actions = append(
-				input.Actions,
-				&platform.CartDiscountSetValidUntilAction{ValidUntil: &newValidUntil})
-		} else {
-			input.Actions = append(
-				input.Actions,
-				&platform.CartDiscountSetValidUntilAction{})
-		}
+	validityActions, err := cartDiscountValidityActions(d)
+	if err != nil {
+		return diagnosticsFromError(err)
 	}
+	input.Actions = append(input.Actions, validityActions...)
 
 	if d.HasChange("requires_discount_code") {
 		newRequiresDiscountCode := d.Get("requires_discount_code").(bool)
@@ -464,7 +532,7 @@ func resourceCartDiscountUpdate(ctx context.Context, d *schema.ResourceData, m i
 	if d.HasChange("stacking_mode") {
 		newStackingMode, err := unmarshallCartDiscountStackingMode(d)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		input.Actions = append(
 			input.Actions,
@@ -480,10 +548,95 @@ func resourceCartDiscountUpdate(ctx context.Context, d *schema.ResourceData, m i
 		if ctErr, ok := err.(platform.ErrorResponse); ok {
 			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
+	}
+
+	var diags diag.Diagnostics
+	if d.HasChanges(cartDiscountRolloutFields...) {
+		if summary := cartDiscountRolloutSummary(d, time.Now()); summary != nil {
+			diags = append(diags, *summary)
+		}
+	}
+	return append(diags, resourceCartDiscountRead(ctx, d, m)...)
+}
+
+// validateCartDiscountUnconditional fails the plan when the cart discount is
+// applied to every cart, unless allow_unconditional confirms it. Existing
+// cart discounts are only checked when one of the fields changes, so other
+// changes of a site-wide cart discount can still be applied.
+func validateCartDiscountUnconditional(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	fields := []string{"is_active", "predicate", "requires_discount_code", "allow_unconditional"}
+	changed := d.Id() == ""
+	for _, field := range fields {
+		if !d.NewValueKnown(field) {
+			return nil
+		}
+		changed = changed || d.HasChange(field)
+	}
+	if !changed || d.Get("allow_unconditional").(bool) || !isUnconditionalCartDiscount(d) {
+		return nil
+	}
+	return fmt.Errorf("the cart discount applies to every cart, because it is active, does not require a " +
+		"discount code and its predicate matches every cart. Set allow_unconditional to true to confirm " +
+		"this, or set requires_discount_code to true or use a more specific predicate")
+}
+
+// isUnconditionalCartDiscount returns true when the cart discount is applied
+// to every cart: it is active, doesn't require a discount code and the
+// predicate matches every cart.
+func isUnconditionalCartDiscount(d resourceChange) bool {
+	return d.Get("is_active").(bool) && !d.Get("requires_discount_code").(bool) &&
+		isUnconditionalPredicate(d.Get("predicate").(string))
+}
+
+// isUnconditionalPredicate returns true for predicates which match every cart
+func isUnconditionalPredicate(predicate string) bool {
+	normalized := normalizePredicate(predicate)
+	return normalized == "" || normalized == "1=1"
+}
+
+// cartDiscountRolloutFields are the fields which determine when and to which
+// carts the cart discount is applied
+var cartDiscountRolloutFields = []string{
+	"is_active", "valid_from", "valid_until", "predicate", "requires_discount_code",
+}
+
+// cartDiscountRolloutSummary returns a warning with the activation window of
+// an active cart discount whose predicate matches every cart, when its
+// valid_from is after now. Without a discount code the plan already required
+// allow_unconditional to confirm the cart discount applies to every cart, the
+// summary adds when that starts, since it goes live without another apply.
+func cartDiscountRolloutSummary(d resourceChange, now time.Time) *diag.Diagnostic {
+	if !d.Get("is_active").(bool) || !isUnconditionalPredicate(d.Get("predicate").(string)) {
+		return nil
+	}
+	validFrom, err := unmarshallOptionalTime(d.Get("valid_from").(string))
+	if err != nil || validFrom == nil || !validFrom.After(now) {
+		return nil
+	}
+	validUntil, err := unmarshallOptionalTime(d.Get("valid_until").(string))
+	if err != nil {
+		return nil
+	}
+
+	window := fmt.Sprintf("from %s without an end", validFrom.Format(time.RFC3339))
+	if validUntil != nil {
+		window = fmt.Sprintf("from %s until %s", validFrom.Format(time.RFC3339), validUntil.Format(time.RFC3339))
+	}
+	scope := "every cart"
+	reason := "allow_unconditional confirms that the cart discount applies to every cart"
+	if !isUnconditionalCartDiscount(d) {
+		scope = "every cart with one of its discount codes"
+		reason = "The predicate of the cart discount matches every cart"
 	}
 
-	return resourceCartDiscountRead(ctx, d, m)
+	return &diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Cart discount is scheduled to apply to %s", scope),
+		Detail: fmt.Sprintf("%s, so it applies to %s %s without another apply. Check that the valid_from "+
+			"is the intended start of the rollout, or set is_active to false to activate it manually.",
+			reason, scope, window),
+	}
 }
 
 func resourceCartDiscountDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -491,12 +644,373 @@ func resourceCartDiscountDelete(ctx context.Context, d *schema.ResourceData, m i
 	version := d.Get("version").(int)
 	_, err := client.CartDiscounts().WithId(d.Id()).Delete().Version(version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 	return nil
 }
 
-func marshallCartDiscountValue(val platform.CartDiscountValue) []map[string]interface{} {
+// resourceCartDiscountImportState imports a cart discount by its ID, its key
+// or its sort order. Import IDs which are a UUID are used as the ID, the
+// `key=` and `sortOrder=` prefixes select the other fields and any other value
+// is used as the key. Both the key and the sort order are unique within a
+// project so they select at most one cart discount.
+func resourceCartDiscountImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	field, value := parseCartDiscountImportID(d.Id())
+	client := getClient(m)
+
+	switch field {
+	case "key":
+		cartDiscount, err := client.CartDiscounts().WithKey(value).Get().Execute(ctx)
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil, fmt.Errorf("no cart discount found with key %q", value)
+			}
+			return nil, err
+		}
+		d.SetId(cartDiscount.ID)
+	case "sortOrder":
+		result, err := client.CartDiscounts().Get().
+			Where([]string{fmt.Sprintf("sortOrder = %s", quotePredicateString(value))}).
+			Limit(1).
+			Execute(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Results) == 0 {
+			return nil, fmt.Errorf("no cart discount found with sort order %s", value)
+		}
+		d.SetId(result.Results[0].ID)
+	default:
+		d.SetId(value)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// parseCartDiscountImportID returns the field the import ID selects the cart
+// discount by, one of id, key or sortOrder, and the value of the field
+func parseCartDiscountImportID(id string) (string, string) {
+	switch {
+	case strings.HasPrefix(id, "sortOrder="):
+		return "sortOrder", strings.TrimSpace(strings.TrimPrefix(id, "sortOrder="))
+	case strings.HasPrefix(id, "key="):
+		return "key", strings.TrimPrefix(id, "key=")
+	case uuidRegex.MatchString(id):
+		return "id", id
+	default:
+		return "key", id
+	}
+}
+
+// cartDiscountSortOrderLock is the ctMutexKV key which serializes the creates
+// of cart discounts with an assigned sort order, it is project wide since the
+// sort order is unique among all cart discounts
+const cartDiscountSortOrderLock = "cart-discount-sort-order"
+
+// nextCartDiscountSortOrder returns a free sort order for a new cart discount.
+// When below is the ID of a cart discount the sort order is between that cart
+// discount and the next lower one, otherwise it is lower than the sort order
+// of all existing cart discounts, so the new cart discount is applied last.
+func nextCartDiscountSortOrder(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, below string) (string, error) {
+	if below == "" {
+		result, err := client.CartDiscounts().Get().
+			Sort([]string{"sortOrder asc"}).
+			Limit(1).
+			Execute(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		lowest := "1"
+		if len(result.Results) > 0 {
+			lowest = result.Results[0].SortOrder
+		}
+		return sortOrderBetween("0", lowest)
+	}
+
+	neighbor, err := client.CartDiscounts().WithId(below).Get().Execute(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch cart discount %s of sort_order_below: %w", below, err)
+	}
+	result, err := client.CartDiscounts().Get().
+		Where([]string{fmt.Sprintf("sortOrder < %s", quotePredicateString(neighbor.SortOrder))}).
+		Sort([]string{"sortOrder desc"}).
+		Limit(1).
+		Execute(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	lower := "0"
+	if len(result.Results) > 0 {
+		lower = result.Results[0].SortOrder
+	}
+	return sortOrderBetween(lower, neighbor.SortOrder)
+}
+
+// sortOrderBetween returns the decimal in the middle of the two given sort
+// orders, using exact arithmetic so the result never collides with a neighbor
+func sortOrderBetween(lower, upper string) (string, error) {
+	low, ok := new(big.Rat).SetString(lower)
+	if !ok {
+		return "", fmt.Errorf("invalid sort order %q", lower)
+	}
+	high, ok := new(big.Rat).SetString(upper)
+	if !ok {
+		return "", fmt.Errorf("invalid sort order %q", upper)
+	}
+	if low.Cmp(high) >= 0 {
+		return "", fmt.Errorf("sort order %q is not lower than %q", lower, upper)
+	}
+
+	middle := new(big.Rat).Add(low, high)
+	middle.Quo(middle, big.NewRat(2, 1))
+
+	// Halving a decimal adds at most one decimal place
+	precision := decimalPlaces(lower)
+	if p := decimalPlaces(upper); p > precision {
+		precision = p
+	}
+	value := strings.TrimRight(middle.FloatString(precision+1), "0")
+	return strings.TrimSuffix(value, "."), nil
+}
+
+func decimalPlaces(value string) int {
+	if i := strings.Index(value, "."); i >= 0 {
+		return len(value) - i - 1
+	}
+	return 0
+}
+
+// validateCartDiscountValue checks that the value has the fields of its type.
+// A change of the type replaces the complete value with a single changeValue
+// action, so the fields of the new type have to be set and the fields of the
+// old type removed.
+func validateCartDiscountValue(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("value") {
+		return nil
+	}
+	values := d.Get("value").([]interface{})
+	if len(values) == 0 || values[0] == nil {
+		return nil
+	}
+	return checkCartDiscountValue(values[0].(map[string]interface{}))
+}
+
+// checkCartDiscountValue returns an error when a field of the value type is
+// missing or a field of another value type is set. The permyriad is not
+// checked for other types, since it is computed and keeps the value of a
+// previous relative value in the plan.
+func checkCartDiscountValue(value map[string]interface{}) error {
+	valueType, _ := value["type"].(string)
+	permyriad, _ := value["permyriad"].(int)
+	percent, _ := value["percent"].(float64)
+	money, _ := value["money"].([]interface{})
+	productID, _ := value["product_id"].(string)
+	variant, _ := value["variant"].(int)
+
+	var missing, unexpected []string
+	switch valueType {
+	case "relative":
+		if permyriad == 0 && percent == 0 {
+			missing = append(missing, "permyriad or percent")
+		}
+		if len(money) > 0 {
+			unexpected = append(unexpected, "money")
+		}
+		if productID != "" {
+			unexpected = append(unexpected, "product_id")
+		}
+	case "absolute":
+		if len(money) == 0 {
+			missing = append(missing, "money")
+		}
+		if percent != 0 {
+			unexpected = append(unexpected, "percent")
+		}
+		if productID != "" {
+			unexpected = append(unexpected, "product_id")
+		}
+	case "giftLineItem":
+		if productID == "" {
+			missing = append(missing, "product_id")
+		}
+		if variant == 0 {
+			missing = append(missing, "variant")
+		}
+		if len(money) > 0 {
+			unexpected = append(unexpected, "money")
+		}
+		if percent != 0 {
+			unexpected = append(unexpected, "percent")
+		}
+	default:
+		return nil
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("value type %s requires %s", valueType, strings.Join(missing, " and "))
+	}
+	if len(unexpected) > 0 {
+		return fmt.Errorf("%s can't be set for value type %s", strings.Join(unexpected, " and "), valueType)
+	}
+	return nil
+}
+
+// validateCartDiscountMoney checks the money of an absolute discount value.
+// Every currency can only be used once and has to be one of the currencies
+// configured in the project.
+func validateCartDiscountMoney(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("value") || !d.NewValueKnown("value") {
+		return nil
+	}
+
+	var currencies []string
+	for _, raw := range d.Get("value.0.money").([]interface{}) {
+		if item, ok := raw.(map[string]interface{}); ok {
+			currencies = append(currencies, item["currency_code"].(string))
+		}
+	}
+	if len(currencies) == 0 {
+		return nil
+	}
+
+	if err := checkCartDiscountCurrencies(currencies, nil); err != nil {
+		return err
+	}
+
+	project, err := getProviderMeta(m).cachedProject(ctx)
+	if err != nil {
+		return err
+	}
+	return checkCartDiscountCurrencies(currencies, project.Currencies)
+}
+
+// validateCartDiscountDistributionChannel checks that the distribution
+// channel of a gift line item has the ProductDistribution role. Otherwise
+// adding the gift line item to a cart fails at checkout.
+func validateCartDiscountDistributionChannel(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("value") || !d.NewValueKnown("value") {
+		return nil
+	}
+	if d.Get("value.0.type").(string) != "giftLineItem" {
+		return nil
+	}
+	channelID := d.Get("value.0.distribution_channel_id").(string)
+	if channelID == "" {
+		return nil
+	}
+
+	channel, err := getClient(m).Channels().WithId(channelID).Get().Execute(ctx)
+	if err != nil {
+		if isNotFoundError(err) {
+			return fmt.Errorf("value.0.distribution_channel_id: channel %s does not exist", channelID)
+		}
+		return err
+	}
+	return checkCartDiscountDistributionChannel(channel)
+}
+
+func checkCartDiscountDistributionChannel(channel *platform.Channel) error {
+	for _, role := range channel.Roles {
+		if role == platform.ChannelRoleEnumProductDistribution {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"value.0.distribution_channel_id: channel %s does not have the %s role, which is required for the "+
+			"distribution channel of a gift line item", channel.ID, platform.ChannelRoleEnumProductDistribution)
+}
+
+// validateCartDiscountTarget checks that the target matches the value of the
+// cart discount
+func validateCartDiscountTarget(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("target") || !d.NewValueKnown("value") {
+		return nil
+	}
+	return checkCartDiscountTarget(
+		d.Get("target.0.type").(string),
+		d.Get("target.0.predicate").(string),
+		d.Get("value.0.type").(string))
+}
+
+// validateCartDiscountPredicates checks the syntax of the predicates, so
+// errors are reported with their offset when planning
+func validateCartDiscountPredicates(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.NewValueKnown("predicate") {
+		if err := checkPredicateSyntax(d.Get("predicate").(string)); err != nil {
+			return fmt.Errorf("predicate: %w", err)
+		}
+	}
+	if d.NewValueKnown("target") {
+		return checkCartDiscountTargetPredicate(
+			d.Get("target.0.type").(string),
+			d.Get("target.0.predicate").(string))
+	}
+	return nil
+}
+
+// checkCartDiscountTargetPredicate returns an error when the predicate of a
+// lineItems or customLineItems target is missing or invalid
+func checkCartDiscountTargetPredicate(targetType string, predicate string) error {
+	if targetType != "lineItems" && targetType != "customLineItems" {
+		return nil
+	}
+	if strings.TrimSpace(predicate) == "" {
+		return fmt.Errorf("target.0.predicate is required for a %s target, use 1=1 to match all items", targetType)
+	}
+	if err := checkLineItemPredicate(targetType, predicate); err != nil {
+		return fmt.Errorf("target.0.predicate: %w", err)
+	}
+	return nil
+}
+
+// checkCartDiscountTarget returns an error when a totalPrice target is used
+// with a predicate or with a value which can't be applied to the total price
+func checkCartDiscountTarget(targetType string, predicate string, valueType string) error {
+	if targetType != "totalPrice" {
+		return nil
+	}
+	if predicate != "" {
+		return fmt.Errorf("target.0.predicate can't be set for a totalPrice target")
+	}
+	switch valueType {
+	case "relative", "absolute":
+		return nil
+	default:
+		return fmt.Errorf("a totalPrice target can only be used with a relative or absolute value, not %s", valueType)
+	}
+}
+
+// checkCartDiscountCurrencies returns an error when a currency is used more
+// than once. When projectCurrencies is given every currency needs to be in it.
+func checkCartDiscountCurrencies(currencies []string, projectCurrencies []string) error {
+	seen := make(map[string]bool, len(currencies))
+	for _, currency := range currencies {
+		if seen[currency] {
+			return fmt.Errorf("currency %s is used more than once in value.0.money", currency)
+		}
+		seen[currency] = true
+	}
+
+	if projectCurrencies == nil {
+		return nil
+	}
+
+	allowed := make(map[string]bool, len(projectCurrencies))
+	for _, currency := range projectCurrencies {
+		allowed[currency] = true
+	}
+	for _, currency := range currencies {
+		if !allowed[currency] {
+			return fmt.Errorf(
+				"currency %s in value.0.money is not configured in the project (%s)",
+				currency, strings.Join(projectCurrencies, ", "))
+		}
+	}
+	return nil
+}
+
+func marshallCartDiscountValue(val platform.CartDiscountValue, usePercent bool) []map[string]interface{} {
 	if val == nil {
 		return []map[string]interface{}{}
 	}
@@ -505,25 +1019,37 @@ func marshallCartDiscountValue(val platform.CartDiscountValue) []map[string]inte
 	case platform.CartDiscountValueAbsolute:
 		return []map[string]interface{}{{
 			"type":  "absolute",
-			"money": marshallTypedMoney(v.Money),
+			"money": marshallTypedMoneyList(v.Money),
 		}}
 	case platform.CartDiscountValueFixed:
 		return []map[string]interface{}{{
 			"type":  "fixed",
-			"money": marshallTypedMoney(v.Money),
+			"money": marshallTypedMoneyList(v.Money),
 		}}
 	case platform.CartDiscountValueGiftLineItem:
-		return []map[string]interface{}{{
-			"type":                    "giftLineItem",
-			"supply_channel_id":       v.SupplyChannel.ID,
-			"distribution_channel_id": v.DistributionChannel.ID,
-			"product_id":              v.Product.ID,
-		}}
+		result := map[string]interface{}{
+			"type":       "giftLineItem",
+			"product_id": v.Product.ID,
+			"variant":    v.VariantId,
+		}
+		if v.SupplyChannel != nil {
+			result["supply_channel_id"] = v.SupplyChannel.ID
+		}
+		if v.DistributionChannel != nil {
+			result["distribution_channel_id"] = v.DistributionChannel.ID
+		}
+		return []map[string]interface{}{result}
 	case platform.CartDiscountValueRelative:
-		return []map[string]interface{}{{
+		result := map[string]interface{}{
 			"type":      "relative",
 			"permyriad": v.Permyriad,
-		}}
+		}
+		// Only keep the percent value in the state when it was used in the
+		// configuration, otherwise it would conflict with permyriad
+		if usePercent {
+			result["percent"] = float64(v.Permyriad) / 100
+		}
+		return []map[string]interface{}{result}
 	}
 	panic("Unable to marshall cart discount value")
 }
@@ -532,8 +1058,12 @@ func unmarshallCartDiscountValue(d *schema.ResourceData) (platform.CartDiscountV
 	value := d.Get("value").([]interface{})[0].(map[string]interface{})
 	switch value["type"].(string) {
 	case "relative":
+		permyriad := value["permyriad"].(int)
+		if percent, ok := value["percent"].(float64); ok && percent != 0 {
+			permyriad = int(math.Round(percent * 100))
+		}
 		return platform.CartDiscountValueRelativeDraft{
-			Permyriad: value["permyriad"].(int),
+			Permyriad: permyriad,
 		}, nil
 	case "absolute":
 		money := unmarshallTypedMoney(value)
@@ -562,7 +1092,27 @@ func unmarshallCartDiscountValue(d *schema.ResourceData) (platform.CartDiscountV
 	}
 }
 
-func marshallCartDiscountTarget(val platform.CartDiscountTarget) []map[string]interface{} {
+// cartDiscountTotalPriceTarget applies the discount to the total price of the
+// cart. The SDK doesn't support this target type yet.
+type cartDiscountTotalPriceTarget struct{}
+
+func (obj cartDiscountTotalPriceTarget) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"type": "totalPrice"})
+}
+
+func marshallCartDiscountTarget(val platform.CartDiscountTarget, value platform.CartDiscountValue) []map[string]interface{} {
+	if val == nil {
+		// A discount with a giftLineItem value has no target. For any other
+		// value the SDK didn't recognize the target, which is a totalPrice
+		// target since all other target types are supported by the SDK.
+		if _, ok := value.(platform.CartDiscountValueGiftLineItem); ok || value == nil {
+			return []map[string]interface{}{}
+		}
+		return []map[string]interface{}{{
+			"type": "totalPrice",
+		}}
+	}
+
 	switch v := val.(type) {
 	case platform.CartDiscountLineItemsTarget:
 		return []map[string]interface{}{{
@@ -604,6 +1154,8 @@ func unmarshallCartDiscountTarget(d *schema.ResourceData) (platform.CartDiscount
 		}, nil
 	case "shipping":
 		return platform.CartDiscountShippingCostTarget{}, nil
+	case "totalPrice":
+		return cartDiscountTotalPriceTarget{}, nil
 	default:
 		return nil, fmt.Errorf("target type %s not implemented", input["type"])
 	}
@@ -621,19 +1173,38 @@ func unmarshallCartDiscountStackingMode(d *schema.ResourceData) (platform.Stacki
 	}
 }
 
-func compareCartDiscountValidDates(old, new string) bool {
-	if old == "" && new == "" {
-		return false
+// cartDiscountValidityActions returns the actions to update valid_from and
+// valid_until. When both changed a single SetValidFromAndUntil is used, so the
+// validity period is never invalid between two actions.
+func cartDiscountValidityActions(d *schema.ResourceData) ([]platform.CartDiscountUpdateAction, error) {
+	fromChanged, untilChanged := d.HasChange("valid_from"), d.HasChange("valid_until")
+	if !fromChanged && !untilChanged {
+		return nil, nil
 	}
-	oldDate, err := unmarshallTime(old)
+
+	validFrom, err := unmarshallOptionalTime(d.Get("valid_from").(string))
 	if err != nil {
-		return true
+		return nil, err
 	}
-	newDate, err := unmarshallTime(new)
+	validUntil, err := unmarshallOptionalTime(d.Get("valid_until").(string))
 	if err != nil {
-		return true
+		return nil, err
+	}
+
+	switch {
+	case fromChanged && untilChanged:
+		return []platform.CartDiscountUpdateAction{
+			&platform.CartDiscountSetValidFromAndUntilAction{ValidFrom: validFrom, ValidUntil: validUntil},
+		}, nil
+	case fromChanged:
+		return []platform.CartDiscountUpdateAction{
+			&platform.CartDiscountSetValidFromAction{ValidFrom: validFrom},
+		}, nil
+	default:
+		return []platform.CartDiscountUpdateAction{
+			&platform.CartDiscountSetValidUntilAction{ValidUntil: validUntil},
+		}, nil
 	}
-	return oldDate.Unix() == newDate.Unix()
 }
 
 func resourceCartDiscountResourceV0() *schema.Resource {
@@ -647,13 +1218,13 @@ func resourceCartDiscountResourceV0() *schema.Resource {
 			"name": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Required:         true,
 			},
 			"description": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"value": {
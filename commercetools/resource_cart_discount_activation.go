@@ -0,0 +1,186 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func resourceCartDiscountActivation() *schema.Resource {
+	return &schema.Resource{
+		Description: "Activates or deactivates a set of cart discounts at once, for example to start or end a " +
+			"campaign. The cart discounts are managed elsewhere, this resource only manages their `is_active` " +
+			"state, so don't manage `is_active` of the same cart discounts in a `commercetools_cart_discount` " +
+			"resource as well.\n\n" +
+			"The cart discounts are updated in parallel. When some of the updates fail the other cart discounts " +
+			"are still updated and every failure is reported. The next plan shows the cart discounts which " +
+			"don't have the configured state yet, so applying again retries only these.\n\n" +
+			"Destroying the resource leaves the cart discounts in their current state.",
+		CreateContext: resourceCartDiscountActivationCreate,
+		ReadContext:   resourceCartDiscountActivationRead,
+		UpdateContext: resourceCartDiscountActivationUpdate,
+		DeleteContext: resourceCartDiscountActivationDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"cart_discount_ids": {
+				Description: "The IDs of the cart discounts",
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"is_active": {
+				Description: "Whether the cart discounts are active",
+				Type:        schema.TypeBool,
+				Required:    true,
+			},
+			"max_concurrency": {
+				Description: "The number of cart discounts which are updated at the same time. The " +
+					"`max_parallel_requests` setting of the provider still limits the requests of all resources",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5,
+				ValidateFunc: validation.IntBetween(1, 20),
+			},
+			"mismatched_ids": {
+				Description: "The IDs of the cart discounts which didn't have the configured state when they " +
+					"were last read, for example because an update failed or they were changed elsewhere",
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceCartDiscountActivationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(resource.UniqueId())
+	return resourceCartDiscountActivationApply(ctx, d, m, d.Timeout(schema.TimeoutCreate))
+}
+
+func resourceCartDiscountActivationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceCartDiscountActivationApply(ctx, d, m, d.Timeout(schema.TimeoutUpdate))
+}
+
+func resourceCartDiscountActivationApply(ctx context.Context, d *schema.ResourceData, m interface{}, timeout time.Duration) diag.Diagnostics {
+	ids := expandStringArray(d.Get("cart_discount_ids").(*schema.Set).List())
+	sort.Strings(ids)
+	isActive := d.Get("is_active").(bool)
+
+	log.Printf("[DEBUG] Setting isActive of %d cart discounts to %t", len(ids), isActive)
+	failures := forEachConcurrently(ctx, ids, d.Get("max_concurrency").(int), func(id string) error {
+		return setCartDiscountActive(ctx, m, id, isActive, timeout)
+	})
+
+	diags := cartDiscountActivationDiagnostics(failures, isActive)
+	return append(diags, resourceCartDiscountActivationRead(ctx, d, m)...)
+}
+
+func resourceCartDiscountActivationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	ids := expandStringArray(d.Get("cart_discount_ids").(*schema.Set).List())
+	isActive := d.Get("is_active").(bool)
+
+	log.Printf("[DEBUG] Reading %d cart discounts from commercetools", len(ids))
+	cartDiscounts, err := queryCartDiscounts(ctx, getClient(m), []string{
+		fmt.Sprintf("id in (%s)", quotePredicateStrings(ids)),
+	}, nil)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	mismatched := cartDiscountActivationMismatches(ids, cartDiscounts, isActive)
+	d.Set("mismatched_ids", mismatched)
+	if len(mismatched) > 0 {
+		// Flip the state so the next plan updates the cart discounts again
+		d.Set("is_active", !isActive)
+	}
+	return nil
+}
+
+func resourceCartDiscountActivationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Removing cart discount activation %s, the cart discounts are not changed", d.Id())
+	return nil
+}
+
+// cartDiscountActivationMismatches returns the IDs which don't have the given
+// state, including the IDs of cart discounts which don't exist, sorted
+func cartDiscountActivationMismatches(ids []string, cartDiscounts []platform.CartDiscount, isActive bool) []string {
+	found := make(map[string]bool, len(cartDiscounts))
+	mismatched := map[string]bool{}
+	for _, cartDiscount := range cartDiscounts {
+		found[cartDiscount.ID] = true
+		if cartDiscount.IsActive != isActive {
+			mismatched[cartDiscount.ID] = true
+		}
+	}
+	for _, id := range ids {
+		if !found[id] {
+			mismatched[id] = true
+		}
+	}
+	return sortedKeys(mismatched)
+}
+
+// setCartDiscountActive changes isActive of a single cart discount. The cart
+// discount is read first so cart discounts which already have the state are
+// not updated, and the update is retried with the current version when the
+// cart discount was changed in between, unless the provider fails on
+// conflicts.
+func setCartDiscountActive(ctx context.Context, m interface{}, id string, isActive bool, timeout time.Duration) error {
+	client := getClient(m)
+	return retryContext(ctx, m, fmt.Sprintf("activate cart discount %s", id), timeout, func() *resource.RetryError {
+		cartDiscount, err := client.CartDiscounts().WithId(id).Get().Execute(ctx)
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+		if cartDiscount.IsActive == isActive {
+			return nil
+		}
+
+		input := platform.CartDiscountUpdate{
+			Version: cartDiscount.Version,
+			Actions: []platform.CartDiscountUpdateAction{
+				&platform.CartDiscountChangeIsActiveAction{IsActive: isActive},
+			},
+		}
+		_, err = client.CartDiscounts().WithId(id).Post(input).Execute(ctx)
+		if err != nil {
+			if isConflictError(err) && retryOnConflict(m) {
+				return resource.RetryableError(err)
+			}
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+}
+
+// cartDiscountActivationDiagnostics returns an error for every cart discount
+// which could not be updated, sorted by ID
+func cartDiscountActivationDiagnostics(failures map[string]error, isActive bool) diag.Diagnostics {
+	ids := make([]string, 0, len(failures))
+	for id := range failures {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var diags diag.Diagnostics
+	for _, id := range ids {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Could not set is_active of cart discount %s to %t", id, isActive),
+			Detail:   failures[id].Error(),
+		})
+	}
+	return diags
+}
@@ -0,0 +1,84 @@
+package commercetools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceCartDiscountActivationCreatePartialFailure(t *testing.T) {
+	var mu sync.Mutex
+	active := map[string]bool{"id-1": false, "id-2": true, "id-3": false}
+	updated := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/my-project/cart-discounts" {
+			results := []map[string]interface{}{}
+			for _, id := range []string{"id-1", "id-2", "id-3"} {
+				if strings.Contains(r.URL.Query().Get("where"), `"`+id+`"`) {
+					results = append(results, map[string]interface{}{"id": id, "version": 1, "isActive": active[id]})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"count": len(results), "results": results})
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/my-project/cart-discounts/")
+		if r.Method == http.MethodPost {
+			if id == "id-3" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"statusCode": 400,
+					"message":    "Invalid cart discount",
+				})
+				return
+			}
+			active[id] = true
+			updated = append(updated, id)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": id, "version": 1, "isActive": active[id]})
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, resourceCartDiscountActivation().Schema, map[string]interface{}{
+		"cart_discount_ids": []interface{}{"id-1", "id-2", "id-3"},
+		"is_active":         true,
+	})
+	diags := resourceCartDiscountActivationCreate(context.Background(), d, meta)
+
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "Could not set is_active of cart discount id-3 to true", diags[0].Summary)
+		assert.Contains(t, diags[0].Detail, "Invalid cart discount")
+	}
+	assert.Equal(t, []string{"id-1"}, updated)
+	assert.NotEmpty(t, d.Id())
+	assert.Equal(t, false, d.Get("is_active"))
+	assert.Equal(t, []interface{}{"id-3"}, d.Get("mismatched_ids").(*schema.Set).List())
+}
+
+func TestCartDiscountActivationMismatches(t *testing.T) {
+	result := cartDiscountActivationMismatches([]string{"id-1", "id-2", "id-3"}, []platform.CartDiscount{
+		{ID: "id-1", IsActive: true},
+		{ID: "id-2", IsActive: false},
+	}, true)
+	assert.Equal(t, []string{"id-2", "id-3"}, result)
+}
@@ -2,13 +2,748 @@ package commercetools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
 )
 
+func TestUnmarshallCartDiscountValueRelative(t *testing.T) {
+	testCases := []struct {
+		value    map[string]interface{}
+		expected int
+	}{
+		{
+			value: map[string]interface{}{
+				"type":      "relative",
+				"permyriad": 1000,
+			},
+			expected: 1000,
+		},
+		{
+			value: map[string]interface{}{
+				"type":    "relative",
+				"percent": 10.0,
+			},
+			expected: 1000,
+		},
+		{
+			value: map[string]interface{}{
+				"type":    "relative",
+				"percent": 12.345,
+			},
+			expected: 1235,
+		},
+	}
+
+	for _, tc := range testCases {
+		d := schema.TestResourceDataRaw(t, resourceCartDiscount().Schema, map[string]interface{}{
+			"value": []interface{}{tc.value},
+		})
+		value, err := unmarshallCartDiscountValue(d)
+		assert.NoError(t, err)
+		assert.Equal(t, platform.CartDiscountValueRelativeDraft{Permyriad: tc.expected}, value)
+	}
+}
+
+func TestMarshallCartDiscountValueRelative(t *testing.T) {
+	value := platform.CartDiscountValueRelative{Permyriad: 1250}
+
+	result := marshallCartDiscountValue(value, false)
+	assert.Equal(t, 1250, result[0]["permyriad"])
+	assert.NotContains(t, result[0], "percent")
+
+	result = marshallCartDiscountValue(value, true)
+	assert.Equal(t, 1250, result[0]["permyriad"])
+	assert.Equal(t, 12.5, result[0]["percent"])
+}
+
+func TestCartDiscountValueAbsoluteMultipleCurrencies(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCartDiscount().Schema, map[string]interface{}{
+		"value": []interface{}{
+			map[string]interface{}{
+				"type": "absolute",
+				"money": []interface{}{
+					map[string]interface{}{"currency_code": "EUR", "cent_amount": 500},
+					map[string]interface{}{"currency_code": "USD", "cent_amount": 600},
+				},
+			},
+		},
+	})
+	value, err := unmarshallCartDiscountValue(d)
+	assert.NoError(t, err)
+	assert.Equal(t, platform.CartDiscountValueAbsoluteDraft{
+		Money: []platform.Money{
+			{CurrencyCode: "EUR", CentAmount: 500},
+			{CurrencyCode: "USD", CentAmount: 600},
+		},
+	}, value)
+
+	result := marshallCartDiscountValue(platform.CartDiscountValueAbsolute{
+		Money: []platform.TypedMoney{
+			platform.CentPrecisionMoney{CurrencyCode: "EUR", CentAmount: 500},
+			platform.CentPrecisionMoney{CurrencyCode: "USD", CentAmount: 600},
+		},
+	}, false)
+	assert.Equal(t, []map[string]interface{}{
+		{"currency_code": "EUR", "cent_amount": 500},
+		{"currency_code": "USD", "cent_amount": 600},
+	}, result[0]["money"])
+}
+
+func TestCheckCartDiscountCurrencies(t *testing.T) {
+	projectCurrencies := []string{"EUR", "USD"}
+
+	assert.NoError(t, checkCartDiscountCurrencies([]string{"EUR", "USD"}, projectCurrencies))
+	assert.EqualError(t,
+		checkCartDiscountCurrencies([]string{"EUR", "USD", "GBP"}, projectCurrencies),
+		"currency GBP in value.0.money is not configured in the project (EUR, USD)")
+	assert.EqualError(t,
+		checkCartDiscountCurrencies([]string{"EUR", "EUR"}, nil),
+		"currency EUR is used more than once in value.0.money")
+}
+
+func TestCartDiscountTotalPriceTarget(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCartDiscount().Schema, map[string]interface{}{
+		"target": []interface{}{
+			map[string]interface{}{"type": "totalPrice"},
+		},
+	})
+	target, err := unmarshallCartDiscountTarget(d)
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(platform.CartDiscountChangeTargetAction{Target: target})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"action": "changeTarget", "target": {"type": "totalPrice"}}`, string(data))
+
+	var cartDiscount platform.CartDiscount
+	err = json.Unmarshal([]byte(`{
+		"id": "cart-discount-id",
+		"value": {"type": "relative", "permyriad": 1000},
+		"target": {"type": "totalPrice"}
+	}`), &cartDiscount)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"type": "totalPrice"}},
+		marshallCartDiscountTarget(cartDiscount.Target, cartDiscount.Value))
+
+	gift := platform.CartDiscountValueGiftLineItem{}
+	assert.Equal(t, []map[string]interface{}{}, marshallCartDiscountTarget(nil, gift))
+}
+
+func TestValidateCartDiscountUnconditional(t *testing.T) {
+	diff := func(state *terraform.InstanceState, raw map[string]interface{}) error {
+		values := map[string]interface{}{
+			"name":   map[string]interface{}{"en": "Sale"},
+			"target": []interface{}{map[string]interface{}{"type": "shipping"}},
+			"value":  []interface{}{map[string]interface{}{"type": "relative", "permyriad": 1000}},
+		}
+		for key, value := range raw {
+			values[key] = value
+		}
+		config := terraform.NewResourceConfigRaw(values)
+		_, err := resourceCartDiscount().SimpleDiff(context.Background(), state, config, nil)
+		return err
+	}
+
+	cases := []struct {
+		config   map[string]interface{}
+		expected bool
+	}{
+		{map[string]interface{}{"predicate": "1 = 1"}, true},
+		{map[string]interface{}{"predicate": " 1=1 "}, true},
+		{map[string]interface{}{"predicate": "1 = 1", "allow_unconditional": true}, false},
+		{map[string]interface{}{"predicate": "1 = 1", "requires_discount_code": true}, false},
+		{map[string]interface{}{"predicate": "1 = 1", "is_active": false}, false},
+		{map[string]interface{}{"predicate": `customer.customerGroup.key = "vip"`}, false},
+	}
+	for _, c := range cases {
+		err := diff(&terraform.InstanceState{}, c.config)
+		if !c.expected {
+			assert.NoError(t, err, c.config)
+			continue
+		}
+		if assert.Error(t, err, c.config) {
+			assert.Contains(t, err.Error(), "the cart discount applies to every cart")
+			assert.Contains(t, err.Error(), "Set allow_unconditional to true")
+		}
+	}
+
+	// Changes of other fields of an existing site-wide cart discount are
+	// planned, enabling it is not
+	state := &terraform.InstanceState{
+		ID: "cart-discount-id",
+		Attributes: map[string]string{
+			"id":                     "cart-discount-id",
+			"name.%":                 "1",
+			"name.en":                "Sale",
+			"predicate":              "1 = 1",
+			"is_active":              "true",
+			"requires_discount_code": "false",
+			"allow_unconditional":    "false",
+		},
+	}
+	assert.NoError(t, diff(state, map[string]interface{}{"predicate": "1 = 1", "name": map[string]interface{}{"en": "Summer sale"}}))
+
+	state.Attributes["is_active"] = "false"
+	err := diff(state, map[string]interface{}{"predicate": "1 = 1"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "the cart discount applies to every cart")
+	}
+	assert.NoError(t, diff(state, map[string]interface{}{"predicate": "1 = 1", "allow_unconditional": true}))
+}
+
+func TestCartDiscountRolloutSummary(t *testing.T) {
+	now := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		config  map[string]interface{}
+		summary string
+		detail  string
+	}{
+		{
+			map[string]interface{}{"predicate": "1 = 1", "valid_from": "2021-09-01T00:00:00Z", "allow_unconditional": true},
+			"Cart discount is scheduled to apply to every cart",
+			"allow_unconditional confirms that the cart discount applies to every cart, so it applies to every " +
+				"cart from 2021-09-01T00:00:00Z without an end",
+		},
+		{
+			map[string]interface{}{
+				"predicate":              "1=1",
+				"valid_from":             "2021-09-01T00:00:00Z",
+				"valid_until":            "2021-09-30T00:00:00Z",
+				"requires_discount_code": true,
+			},
+			"Cart discount is scheduled to apply to every cart with one of its discount codes",
+			"from 2021-09-01T00:00:00Z until 2021-09-30T00:00:00Z",
+		},
+		{map[string]interface{}{"predicate": "1 = 1"}, "", ""},
+		{map[string]interface{}{"predicate": "1 = 1", "valid_from": "2021-07-01T00:00:00Z"}, "", ""},
+		{map[string]interface{}{"predicate": "1 = 1", "valid_from": "2021-09-01T00:00:00Z", "is_active": false}, "", ""},
+		{map[string]interface{}{"predicate": `customer.customerGroup.key = "vip"`, "valid_from": "2021-09-01T00:00:00Z"}, "", ""},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceCartDiscount().Schema, c.config)
+		summary := cartDiscountRolloutSummary(d, now)
+		if c.summary == "" {
+			assert.Nil(t, summary, c.config)
+			continue
+		}
+		if assert.NotNil(t, summary, c.config) {
+			assert.Equal(t, diag.Warning, summary.Severity)
+			assert.Equal(t, c.summary, summary.Summary)
+			assert.Contains(t, summary.Detail, c.detail)
+		}
+	}
+}
+
+func TestCheckCartDiscountDistributionChannel(t *testing.T) {
+	assert.NoError(t, checkCartDiscountDistributionChannel(&platform.Channel{
+		ID:    "channel-1",
+		Roles: []platform.ChannelRoleEnum{platform.ChannelRoleEnumInventorySupply, platform.ChannelRoleEnumProductDistribution},
+	}))
+
+	err := checkCartDiscountDistributionChannel(&platform.Channel{
+		ID:    "channel-2",
+		Roles: []platform.ChannelRoleEnum{platform.ChannelRoleEnumInventorySupply},
+	})
+	assert.EqualError(t, err, "value.0.distribution_channel_id: channel channel-2 does not have the "+
+		"ProductDistribution role, which is required for the distribution channel of a gift line item")
+}
+
+func TestCheckCartDiscountTarget(t *testing.T) {
+	assert.NoError(t, checkCartDiscountTarget("totalPrice", "", "relative"))
+	assert.NoError(t, checkCartDiscountTarget("totalPrice", "", "absolute"))
+	assert.NoError(t, checkCartDiscountTarget("lineItems", "1=1", "relative"))
+	assert.EqualError(t,
+		checkCartDiscountTarget("totalPrice", "", "giftLineItem"),
+		"a totalPrice target can only be used with a relative or absolute value, not giftLineItem")
+	assert.EqualError(t,
+		checkCartDiscountTarget("totalPrice", "1=1", "relative"),
+		"target.0.predicate can't be set for a totalPrice target")
+}
+
+func TestCheckCartDiscountTargetPredicate(t *testing.T) {
+	assert.NoError(t, checkCartDiscountTargetPredicate("lineItems", `sku = "shirt-1" and quantity > 1`))
+	assert.NoError(t, checkCartDiscountTargetPredicate("customLineItems", "1=1"))
+	assert.NoError(t, checkCartDiscountTargetPredicate("shipping", ""))
+	assert.EqualError(t,
+		checkCartDiscountTargetPredicate("lineItems", " "),
+		"target.0.predicate is required for a lineItems target, use 1=1 to match all items")
+	assert.EqualError(t,
+		checkCartDiscountTargetPredicate("lineItems", `sku = "shirt-1" and customer.email = "a@example.com"`),
+		"target.0.predicate: customer.email at offset 20 is a cart predicate field, the predicate of a "+
+			"lineItems target is evaluated for every item and can only use the fields of the item")
+	assert.EqualError(t,
+		checkCartDiscountTargetPredicate("lineItems", `sku in ("shirt-1", "shirt-2"`),
+		`target.0.predicate: "(" at offset 7 is not closed`)
+}
+
+func TestValidateCartDiscountPredicates(t *testing.T) {
+	diff := func(predicate, targetPredicate string) error {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":      map[string]interface{}{"en": "Shirts"},
+			"predicate": predicate,
+			"target": []interface{}{map[string]interface{}{
+				"type":      "lineItems",
+				"predicate": targetPredicate,
+			}},
+			"value": []interface{}{map[string]interface{}{"type": "relative", "permyriad": 1000}},
+		})
+		_, err := resourceCartDiscount().SimpleDiff(context.Background(), &terraform.InstanceState{}, config, nil)
+		return err
+	}
+
+	assert.NoError(t, diff(`customer.email = "a@example.com"`, `sku = "shirt-1"`))
+	err := diff(`customer.email = "a@example.com`, "1=1")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "predicate: string starting at offset 17 is not terminated")
+	}
+	err = diff("1=1", `lineItemCount(sku = "a") > 1`)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "target.0.predicate: lineItemCount at offset 0 is a cart predicate field, the predicate of a "+
+			"lineItems target is evaluated for every item and can only use the fields of the item")
+	}
+}
+
+func TestCartDiscountValueValidation(t *testing.T) {
+	s := resourceCartDiscount().Schema["value"].Elem.(*schema.Resource).Schema
+
+	_, errs := s["permyriad"].ValidateFunc(10001, "permyriad")
+	assert.NotEmpty(t, errs)
+	_, errs = s["permyriad"].ValidateFunc(-1, "permyriad")
+	assert.NotEmpty(t, errs)
+	_, errs = s["permyriad"].ValidateFunc(1000, "permyriad")
+	assert.Empty(t, errs)
+
+	_, errs = s["percent"].ValidateFunc(100.5, "percent")
+	assert.NotEmpty(t, errs)
+	_, errs = s["percent"].ValidateFunc(10.0, "percent")
+	assert.Empty(t, errs)
+}
+
+func TestCheckCartDiscountValue(t *testing.T) {
+	money := []interface{}{map[string]interface{}{"currency_code": "EUR", "cent_amount": 500}}
+
+	assert.NoError(t, checkCartDiscountValue(map[string]interface{}{"type": "relative", "permyriad": 1000}))
+	assert.NoError(t, checkCartDiscountValue(map[string]interface{}{"type": "relative", "percent": 10.0}))
+	assert.NoError(t, checkCartDiscountValue(map[string]interface{}{"type": "absolute", "money": money}))
+	assert.NoError(t, checkCartDiscountValue(map[string]interface{}{
+		"type": "giftLineItem", "product_id": "product-1", "variant": 1}))
+
+	// The computed permyriad of a previous relative value is ignored
+	assert.NoError(t, checkCartDiscountValue(map[string]interface{}{
+		"type": "absolute", "money": money, "permyriad": 1000}))
+
+	assert.EqualError(t,
+		checkCartDiscountValue(map[string]interface{}{"type": "relative"}),
+		"value type relative requires permyriad or percent")
+	assert.EqualError(t,
+		checkCartDiscountValue(map[string]interface{}{"type": "absolute", "money": []interface{}{}}),
+		"value type absolute requires money")
+	assert.EqualError(t,
+		checkCartDiscountValue(map[string]interface{}{"type": "giftLineItem"}),
+		"value type giftLineItem requires product_id and variant")
+	assert.EqualError(t,
+		checkCartDiscountValue(map[string]interface{}{"type": "relative", "percent": 10.0, "money": money}),
+		"money can't be set for value type relative")
+	assert.EqualError(t,
+		checkCartDiscountValue(map[string]interface{}{"type": "absolute", "money": money, "percent": 10.0}),
+		"percent can't be set for value type absolute")
+	assert.EqualError(t,
+		checkCartDiscountValue(map[string]interface{}{
+			"type": "giftLineItem", "product_id": "product-1", "variant": 1, "money": money}),
+		"money can't be set for value type giftLineItem")
+}
+
+func TestResourceCartDiscountUpdateValueType(t *testing.T) {
+	values := map[string]map[string]interface{}{
+		"relative": {"type": "relative", "permyriad": 1000},
+		"absolute": {"type": "absolute", "money": []interface{}{
+			map[string]interface{}{"currency_code": "EUR", "cent_amount": 500},
+		}},
+		"giftLineItem": {"type": "giftLineItem", "product_id": "product-1", "variant": 1},
+	}
+	apiValues := map[string]string{
+		"relative":     `{"type": "relative", "permyriad": 1000}`,
+		"absolute":     `{"type": "absolute", "money": [{"type": "centPrecision", "currencyCode": "EUR", "centAmount": 500, "fractionDigits": 2}]}`,
+		"giftLineItem": `{"type": "giftLineItem", "product": {"typeId": "product", "id": "product-1"}, "variantId": 1}`,
+	}
+	config := func(valueType string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":       map[string]interface{}{"en": "Summer"},
+			"predicate":  "1=1",
+			"sort_order": "0.5",
+			"target":     []interface{}{map[string]interface{}{"type": "shipping"}},
+			"value":      []interface{}{values[valueType]},
+		}
+	}
+
+	for _, from := range []string{"relative", "absolute", "giftLineItem"} {
+		for _, to := range []string{"relative", "absolute", "giftLineItem"} {
+			if from == to {
+				continue
+			}
+			t.Run(fmt.Sprintf("%s to %s", from, to), func(t *testing.T) {
+				var actions []map[string]interface{}
+				current := apiValues[from]
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodPost {
+						var update struct {
+							Actions []map[string]interface{} `json:"actions"`
+						}
+						json.NewDecoder(r.Body).Decode(&update)
+						actions = update.Actions
+						current = apiValues[to]
+					}
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprintf(w, `{"id": "cart-discount-1", "version": 1, "name": {"en": "Summer"},
+						"cartPredicate": "1=1", "sortOrder": "0.5", "isActive": true, "stackingMode": "Stacking",
+						"target": {"type": "shipping"}, "value": %s}`, current)
+				}))
+				defer server.Close()
+
+				client, err := platform.NewClient(&platform.ClientConfig{
+					URL:        server.URL,
+					HTTPClient: server.Client(),
+				})
+				assert.NoError(t, err)
+
+				d := testResourceDataChange(t, resourceCartDiscount().Schema, config(from), config(to))
+				assert.NoError(t, checkCartDiscountValue(d.Get("value.0").(map[string]interface{})))
+
+				diags := resourceCartDiscountUpdate(
+					context.Background(), d, &providerMeta{client: client.WithProjectKey("my-project")})
+				assert.False(t, diags.HasError())
+				if assert.Len(t, actions, 1) {
+					assert.Equal(t, "changeValue", actions[0]["action"])
+					assert.Equal(t, to, actions[0]["value"].(map[string]interface{})["type"])
+				}
+				assert.Equal(t, to, d.Get("value.0.type"))
+			})
+		}
+	}
+}
+
+// fakeCartDiscountSortOrders stores the sort orders of created cart discounts
+// and rejects a create with a used sort order, like commercetools
+type fakeCartDiscountSortOrders struct {
+	mu         sync.Mutex
+	sortOrders map[string]string
+	duplicates int
+}
+
+func (f *fakeCartDiscountSortOrders) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	respond := func(id, sortOrder string) {
+		fmt.Fprintf(w, `{"id": %q, "version": 1, "name": {"en": "Summer"}, "cartPredicate": "1=1",
+			"sortOrder": %q, "isActive": true, "stackingMode": "Stacking", "target": {"type": "shipping"},
+			"value": {"type": "relative", "permyriad": 1000}}`, id, sortOrder)
+	}
+
+	if r.Method == http.MethodPost {
+		var draft struct {
+			SortOrder string `json:"sortOrder"`
+		}
+		json.NewDecoder(r.Body).Decode(&draft)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for _, sortOrder := range f.sortOrders {
+			if sortOrder == draft.SortOrder {
+				f.duplicates++
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, `{"statusCode": 400, "message": "duplicate", "errors": [{"code": "DuplicateField",
+					"message": "duplicate", "field": "sortOrder", "duplicateValue": %q}]}`, draft.SortOrder)
+				return
+			}
+		}
+		id := fmt.Sprintf("cart-discount-%d", len(f.sortOrders)+1)
+		f.sortOrders[id] = draft.SortOrder
+		w.WriteHeader(http.StatusCreated)
+		respond(id, draft.SortOrder)
+		return
+	}
+
+	if id := strings.TrimPrefix(r.URL.Path, "/my-project/cart-discounts/"); id != r.URL.Path {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		respond(id, f.sortOrders[id])
+		return
+	}
+
+	// The next lower sort order of sort_order_below
+	if where := r.URL.Query().Get("where"); where != "" {
+		upper := strings.Trim(strings.TrimPrefix(where, "sortOrder < "), `"`)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		lower := ""
+		for _, sortOrder := range f.sortOrders {
+			if sortOrder < upper && sortOrder > lower {
+				lower = sortOrder
+			}
+		}
+		if lower == "" {
+			w.Write([]byte(`{"count": 0, "results": []}`))
+			return
+		}
+		fmt.Fprintf(w, `{"count": 1, "results": [{"id": "lower", "sortOrder": %q}]}`, lower)
+		return
+	}
+
+	// Give a parallel create the time to read the same lowest sort order
+	f.mu.Lock()
+	lowest := "1"
+	for _, sortOrder := range f.sortOrders {
+		if sortOrder < lowest {
+			lowest = sortOrder
+		}
+	}
+	f.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	fmt.Fprintf(w, `{"count": 1, "results": [{"id": "lowest", "sortOrder": %q}]}`, lowest)
+}
+
+func TestResourceCartDiscountCreateConcurrentSortOrder(t *testing.T) {
+	fake := &fakeCartDiscountSortOrders{sortOrders: map[string]string{"existing": "0.5"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d := schema.TestResourceDataRaw(t, resourceCartDiscount().Schema, map[string]interface{}{
+				"name":      map[string]interface{}{"en": "Summer"},
+				"predicate": "1=1",
+				"target":    []interface{}{map[string]interface{}{"type": "shipping"}},
+				"value":     []interface{}{map[string]interface{}{"type": "relative", "permyriad": 1000}},
+			})
+			diags := resourceCartDiscountCreate(context.Background(), d, meta)
+			assert.False(t, diags.HasError(), diags)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 0, fake.duplicates)
+	assert.ElementsMatch(t, []string{"0.5", "0.25", "0.125"}, []string{
+		fake.sortOrders["existing"], fake.sortOrders["cart-discount-2"], fake.sortOrders["cart-discount-3"],
+	})
+}
+
+func TestResourceCartDiscountCreateSortOrderBelow(t *testing.T) {
+	fake := &fakeCartDiscountSortOrders{sortOrders: map[string]string{"high": "0.5", "low": "0.25"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	create := func(below string) string {
+		d := schema.TestResourceDataRaw(t, resourceCartDiscount().Schema, map[string]interface{}{
+			"name":             map[string]interface{}{"en": "Summer"},
+			"predicate":        "1=1",
+			"sort_order_below": below,
+			"target":           []interface{}{map[string]interface{}{"type": "shipping"}},
+			"value":            []interface{}{map[string]interface{}{"type": "relative", "permyriad": 1000}},
+		})
+		diags := resourceCartDiscountCreate(context.Background(), d, meta)
+		assert.False(t, diags.HasError(), diags)
+		return d.Get("sort_order").(string)
+	}
+
+	// Between the cart discount and the next lower one
+	assert.Equal(t, "0.375", create("high"))
+	assert.Equal(t, "0.4375", create("high"))
+	// Below the lowest cart discount
+	assert.Equal(t, "0.125", create("low"))
+	assert.Equal(t, 0, fake.duplicates)
+}
+
+func TestCartDiscountValidityActions(t *testing.T) {
+	base := map[string]interface{}{
+		"name":      map[string]interface{}{"en": "Summer"},
+		"predicate": "1=1",
+	}
+	with := func(values map[string]interface{}) map[string]interface{} {
+		result := map[string]interface{}{}
+		for k, v := range base {
+			result[k] = v
+		}
+		for k, v := range values {
+			result[k] = v
+		}
+		return result
+	}
+
+	// Schedule a future activation of an existing cart discount
+	d := testResourceDataChange(t, resourceCartDiscount().Schema, base, with(map[string]interface{}{
+		"valid_from": "2030-06-01T00:00:00Z",
+	}))
+	actions, err := cartDiscountValidityActions(d)
+	assert.NoError(t, err)
+	validFrom := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []platform.CartDiscountUpdateAction{
+		&platform.CartDiscountSetValidFromAction{ValidFrom: &validFrom},
+	}, actions)
+
+	// Changing both results in a single action
+	d = testResourceDataChange(t, resourceCartDiscount().Schema, base, with(map[string]interface{}{
+		"valid_from":  "2030-06-01T00:00:00Z",
+		"valid_until": "2030-09-01T00:00:00Z",
+	}))
+	actions, err = cartDiscountValidityActions(d)
+	assert.NoError(t, err)
+	validUntil := time.Date(2030, 9, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []platform.CartDiscountUpdateAction{
+		&platform.CartDiscountSetValidFromAndUntilAction{ValidFrom: &validFrom, ValidUntil: &validUntil},
+	}, actions)
+
+	// Removing the end of the period clears it
+	d = testResourceDataChange(t, resourceCartDiscount().Schema,
+		with(map[string]interface{}{"valid_until": "2030-09-01T00:00:00Z"}), base)
+	actions, err = cartDiscountValidityActions(d)
+	assert.NoError(t, err)
+	assert.Equal(t, []platform.CartDiscountUpdateAction{
+		&platform.CartDiscountSetValidUntilAction{},
+	}, actions)
+
+	// The same instant in another format is not a change
+	d = testResourceDataChange(t, resourceCartDiscount().Schema,
+		with(map[string]interface{}{"valid_from": "2030-06-01T00:00:00Z"}),
+		with(map[string]interface{}{"valid_from": "2030-06-01T02:00:00+02:00"}))
+	actions, err = cartDiscountValidityActions(d)
+	assert.NoError(t, err)
+	assert.Empty(t, actions)
+}
+
+func TestSortOrderBetween(t *testing.T) {
+	cases := []struct {
+		lower, upper, expected string
+	}{
+		{"0", "1", "0.5"},
+		{"0", "0.9", "0.45"},
+		{"0", "0.5", "0.25"},
+		{"0.1", "0.2", "0.15"},
+		{"0.123", "0.1231", "0.12305"},
+	}
+	for _, c := range cases {
+		result, err := sortOrderBetween(c.lower, c.upper)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, result)
+	}
+
+	_, err := sortOrderBetween("0.5", "0.5")
+	assert.Error(t, err)
+	_, err = sortOrderBetween("0", "abc")
+	assert.Error(t, err)
+}
+
+func TestParseCartDiscountImportID(t *testing.T) {
+	cases := []struct {
+		id    string
+		field string
+		value string
+	}{
+		{"sortOrder=0.9", "sortOrder", "0.9"},
+		{"2845b936-e407-4f29-957b-f8deb0fcba97", "id", "2845b936-e407-4f29-957b-f8deb0fcba97"},
+		{"summer-sale", "key", "summer-sale"},
+		{"key=2845b936-e407-4f29-957b-f8deb0fcba97", "key", "2845b936-e407-4f29-957b-f8deb0fcba97"},
+	}
+	for _, c := range cases {
+		field, value := parseCartDiscountImportID(c.id)
+		assert.Equal(t, c.field, field, c.id)
+		assert.Equal(t, c.value, value, c.id)
+	}
+}
+
+func TestResourceCartDiscountImportStateKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/my-project/cart-discounts/key=summer-sale" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"statusCode": 404, "message": "The Resource with key 'other' was not found."}`))
+			return
+		}
+		w.Write([]byte(`{"id": "2845b936-e407-4f29-957b-f8deb0fcba97", "key": "summer-sale"}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, resourceCartDiscount().Schema, map[string]interface{}{})
+	d.SetId("summer-sale")
+	result, err := resourceCartDiscountImportState(context.Background(), d, meta)
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "2845b936-e407-4f29-957b-f8deb0fcba97", result[0].Id())
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceCartDiscount().Schema, map[string]interface{}{})
+	d.SetId("key=other")
+	_, err = resourceCartDiscountImportState(context.Background(), d, meta)
+	assert.EqualError(t, err, `no cart discount found with key "other"`)
+}
+
+func TestResourceCartDiscountImportStateSortOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("where") != `sortOrder = "0.85"` {
+			w.Write([]byte(`{"count": 0, "results": []}`))
+			return
+		}
+		w.Write([]byte(`{"count": 1, "results": [{"id": "2845b936-e407-4f29-957b-f8deb0fcba97", "sortOrder": "0.85"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, resourceCartDiscount().Schema, map[string]interface{}{})
+	d.SetId("sortOrder=0.85")
+	result, err := resourceCartDiscountImportState(context.Background(), d, meta)
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "2845b936-e407-4f29-957b-f8deb0fcba97", result[0].Id())
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceCartDiscount().Schema, map[string]interface{}{})
+	d.SetId("sortOrder=0.9")
+	_, err = resourceCartDiscountImportState(context.Background(), d, meta)
+	assert.EqualError(t, err, "no cart discount found with sort order 0.9")
+}
+
 func TestAccCartDiscountCreate_basic(t *testing.T) {
 
 	resource.Test(t, resource.TestCase{
@@ -266,3 +1001,14 @@ func testAccCheckCartDiscountDestroy(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestValidatePercentPrecision(t *testing.T) {
+	for _, value := range []float64{0, 10, 12.5, 33.33, 99.99, 100} {
+		_, errs := validatePercentPrecision(value, "value.0.percent")
+		assert.Empty(t, errs, "%v", value)
+	}
+	for _, value := range []float64{12.345, 0.001, 33.3333} {
+		_, errs := validatePercentPrecision(value, "value.0.percent")
+		assert.Len(t, errs, 1, "%v", value)
+	}
+}
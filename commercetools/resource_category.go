@@ -39,18 +39,18 @@ func resourceCategory() *schema.Resource {
 			},
 			"name": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Required:         true,
 				ForceNew:         true,
 			},
 			"description": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"slug": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Required:         true,
 				Description:      "Human readable identifiers, needs to be unique",
 			},
@@ -66,17 +66,17 @@ func resourceCategory() *schema.Resource {
 			},
 			"meta_title": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"meta_description": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"meta_keywords": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"assets": {
@@ -96,12 +96,12 @@ func resourceCategory() *schema.Resource {
 						},
 						"name": {
 							Type:             TypeLocalizedString,
-							ValidateDiagFunc: validateLocalizedStringKey,
+							ValidateDiagFunc: validateLocalizedString,
 							Required:         true,
 						},
 						"description": {
 							Type:             TypeLocalizedString,
-							ValidateDiagFunc: validateLocalizedStringKey,
+							ValidateDiagFunc: validateLocalizedString,
 							Optional:         true,
 						},
 						"sources": {
@@ -211,7 +211,7 @@ func resourceCategoryCreate(ctx context.Context, d *schema.ResourceData, m inter
 		draft.Assets = assets
 	}
 
-	err := resource.RetryContext(ctx, 1*time.Minute, func() *resource.RetryError {
+	err := retryContext(ctx, m, "create category", 1*time.Minute, func() *resource.RetryError {
 		var err error
 
 		category, err = client.Categories().Post(draft).Execute(ctx)
@@ -223,7 +223,7 @@ func resourceCategoryCreate(ctx context.Context, d *schema.ResourceData, m inter
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if category == nil {
@@ -249,7 +249,7 @@ func resourceCategoryRead(ctx context.Context, d *schema.ResourceData, m interfa
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if category == nil {
@@ -292,7 +292,7 @@ func resourceCategoryUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	category, err := client.Categories().WithId(d.Id()).Get().Execute(ctx)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input := platform.CategoryUpdate{
@@ -401,7 +401,7 @@ func resourceCategoryUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		if ctErr, ok := err.(platform.ErrorResponse); ok {
 			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceCategoryRead(ctx, d, m)
@@ -413,7 +413,7 @@ func resourceCategoryDelete(ctx context.Context, d *schema.ResourceData, m inter
 	version := d.Get("version").(int)
 	_, err := client.Categories().WithId(d.Id()).Delete().Version(version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
@@ -561,18 +561,18 @@ func resourceCategoryResourceV0() *schema.Resource {
 			},
 			"name": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Required:         true,
 				ForceNew:         true,
 			},
 			"description": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"slug": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Required:         true,
 				Description:      "Human readable identifiers, needs to be unique",
 			},
@@ -588,17 +588,17 @@ func resourceCategoryResourceV0() *schema.Resource {
 			},
 			"meta_title": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"meta_description": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"meta_keywords": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"assets": {
@@ -614,12 +614,12 @@ func resourceCategoryResourceV0() *schema.Resource {
 						},
 						"name": {
 							Type:             TypeLocalizedString,
-							ValidateDiagFunc: validateLocalizedStringKey,
+							ValidateDiagFunc: validateLocalizedString,
 							Required:         true,
 						},
 						"description": {
 							Type:             TypeLocalizedString,
-							ValidateDiagFunc: validateLocalizedStringKey,
+							ValidateDiagFunc: validateLocalizedString,
 							Optional:         true,
 						},
 						"sources": {
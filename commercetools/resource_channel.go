@@ -38,13 +38,13 @@ func resourceChannel() *schema.Resource {
 			"name": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"description": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"version": {
@@ -74,7 +74,7 @@ func resourceChannelCreate(ctx context.Context, d *schema.ResourceData, m interf
 	client := getClient(m)
 	var channel *platform.Channel
 
-	err := resource.RetryContext(ctx, 20*time.Second, func() *resource.RetryError {
+	err := retryContext(ctx, m, "create channel", 20*time.Second, func() *resource.RetryError {
 		var err error
 
 		channel, err = client.Channels().Post(draft).Execute(ctx)
@@ -85,7 +85,7 @@ func resourceChannelCreate(ctx context.Context, d *schema.ResourceData, m interf
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	d.SetId(channel.ID)
@@ -104,7 +104,7 @@ func resourceChannelRead(ctx context.Context, d *schema.ResourceData, m interfac
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	d.SetId(channel.ID)
@@ -161,7 +161,7 @@ func resourceChannelUpdate(ctx context.Context, d *schema.ResourceData, m interf
 
 	_, err := client.Channels().WithId(d.Id()).Post(input).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceChannelRead(ctx, d, m)
@@ -172,7 +172,7 @@ func resourceChannelDelete(ctx context.Context, d *schema.ResourceData, m interf
 	version := d.Get("version").(int)
 	_, err := client.Channels().WithId(d.Id()).Delete().Version(version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
@@ -3,10 +3,14 @@ package commercetools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"reflect"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/labd/commercetools-go-sdk/platform"
 )
 
@@ -16,13 +20,15 @@ func resourceCustomObject() *schema.Resource {
 			"It allows you to persist data that does not fit the standard data model. This frees your application " +
 			"completely from any third-party persistence solution and means that all your data stays on the " +
 			"commercetools platform.\n\n" +
+			"The value is written with the upsert semantics of the custom objects API, differences in the " +
+			"formatting of the JSON value are ignored.\n\n" +
 			"See also the [Custom Object API Documentation](https://docs.commercetools.com/api/projects/custom-objects)",
 		CreateContext: resourceCustomObjectCreate,
 		ReadContext:   resourceCustomObjectRead,
 		UpdateContext: resourceCustomObjectUpdate,
 		DeleteContext: resourceCustomObjectDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceCustomObjectImportState,
 		},
 		Schema: map[string]*schema.Schema{
 			"container": {
@@ -36,9 +42,11 @@ func resourceCustomObject() *schema.Resource {
 				Required:    true,
 			},
 			"value": {
-				Description: "JSON types Number, String, Boolean, Array, Object",
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      "JSON types Number, String, Boolean, Array, Object",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: diffSuppressJSON,
 			},
 			"version": {
 				Type:     schema.TypeInt,
@@ -59,7 +67,7 @@ func resourceCustomObjectCreate(ctx context.Context, d *schema.ResourceData, m i
 	}
 	customObject, err := client.CustomObjects().Post(draft).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	d.SetId(customObject.ID)
@@ -76,13 +84,11 @@ func resourceCustomObjectRead(ctx context.Context, d *schema.ResourceData, m int
 
 	customObject, err := client.CustomObjects().WithContainerAndKey(container, key).Get().Execute(ctx)
 	if err != nil {
-		if ctErr, ok := err.(platform.ErrorResponse); ok {
-			if ctErr.StatusCode == 404 {
-				d.SetId("")
-				return nil
-			}
+		if isNotFoundError(err) {
+			d.SetId("")
+			return nil
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if customObject == nil {
@@ -91,6 +97,7 @@ func resourceCustomObjectRead(ctx context.Context, d *schema.ResourceData, m int
 	} else {
 		log.Print("[DEBUG] Found following custom object:")
 		log.Print(stringFormatObject(customObject))
+		d.SetId(customObject.ID)
 		d.Set("container", customObject.Container)
 		d.Set("key", customObject.Key)
 		d.Set("value", marshallCustomObjectValue(customObject))
@@ -117,7 +124,7 @@ func resourceCustomObjectUpdate(ctx context.Context, d *schema.ResourceData, m i
 		}
 		customObject, err := client.CustomObjects().Post(draft).Execute(ctx)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		d.SetId(customObject.ID)
 		d.Set("version", customObject.Version)
@@ -131,7 +138,7 @@ func resourceCustomObjectUpdate(ctx context.Context, d *schema.ResourceData, m i
 			Execute(ctx)
 
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 	} else {
 		// Update the value by creating an object with the same key/value.
@@ -145,7 +152,7 @@ func resourceCustomObjectUpdate(ctx context.Context, d *schema.ResourceData, m i
 		}
 		customObject, err := client.CustomObjects().Post(draft).Execute(ctx)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 
 		d.SetId(customObject.ID)
@@ -172,7 +179,7 @@ func resourceCustomObjectDelete(ctx context.Context, d *schema.ResourceData, m i
 		Execute(ctx)
 	if err != nil {
 		var diags diag.Diagnostics
-		diags = append(diags, diag.FromErr(err)...)
+		diags = append(diags, diagnosticsFromError(err)...)
 		diags = append(diags, diag.Errorf("could not get custom object with container %s and key %s", container, key)...)
 		return diags
 	}
@@ -186,19 +193,52 @@ func resourceCustomObjectDelete(ctx context.Context, d *schema.ResourceData, m i
 		Execute(ctx)
 	if err != nil {
 		var diags diag.Diagnostics
-		diags = append(diags, diag.FromErr(err)...)
+		diags = append(diags, diagnosticsFromError(err)...)
 		diags = append(diags, diag.Errorf("could not delete custom object with container %s and key %s", container, key)...)
 		return diags
 	}
 	return nil
 }
 
+// resourceCustomObjectImportState imports a custom object by `container/key`,
+// the custom objects API doesn't support reading an object by its ID
+func resourceCustomObjectImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	container, key, err := parseCustomObjectImportID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("container", container)
+	d.Set("key", key)
+	return []*schema.ResourceData{d}, nil
+}
+
+func parseCustomObjectImportID(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid import ID %q, expected container/key", id)
+	}
+	return parts[0], parts[1], nil
+}
+
 func _decodeCustomObjectValue(value string) interface{} {
-	data := make(map[string]interface{})
+	var data interface{}
 	json.Unmarshal([]byte(value), &data)
 	return data
 }
 
+// diffSuppressJSON suppresses the diff between two JSON documents which only
+// differ in formatting or the order of object keys
+func diffSuppressJSON(k, old, new string, d *schema.ResourceData) bool {
+	var oldValue, newValue interface{}
+	if err := json.Unmarshal([]byte(old), &oldValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldValue, newValue)
+}
+
 func marshallCustomObjectValue(o *platform.CustomObject) string {
 	val, err := json.Marshal(o.Value)
 	if err != nil {
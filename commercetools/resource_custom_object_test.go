@@ -6,10 +6,38 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
+func TestParseCustomObjectImportID(t *testing.T) {
+	container, key, err := parseCustomObjectImportID("my-container/my/key")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-container", container)
+	assert.Equal(t, "my/key", key)
+
+	_, _, err = parseCustomObjectImportID("my-key")
+	assert.EqualError(t, err, `invalid import ID "my-key", expected container/key`)
+	_, _, err = parseCustomObjectImportID("/my-key")
+	assert.Error(t, err)
+}
+
+func TestDiffSuppressJSON(t *testing.T) {
+	assert.True(t, diffSuppressJSON("value", `{"a":1,"b":[1,2]}`, `{ "b": [1, 2], "a": 1 }`, nil))
+	assert.True(t, diffSuppressJSON("value", `10`, ` 10 `, nil))
+	assert.False(t, diffSuppressJSON("value", `{"a":1}`, `{"a":2}`, nil))
+	assert.False(t, diffSuppressJSON("value", `[1,2]`, `[2,1]`, nil))
+	assert.False(t, diffSuppressJSON("value", ``, `{}`, nil))
+}
+
+func TestDecodeCustomObjectValue(t *testing.T) {
+	assert.Equal(t, float64(10), _decodeCustomObjectValue(`10`))
+	assert.Equal(t, "text", _decodeCustomObjectValue(`"text"`))
+	assert.Equal(t, []interface{}{true, false}, _decodeCustomObjectValue(`[true,false]`))
+	assert.Equal(t, map[string]interface{}{"a": "b"}, _decodeCustomObjectValue(`{"a":"b"}`))
+}
+
 func TestAccCustomObjectCreate_basic(t *testing.T) {
 
 	resource.Test(t, resource.TestCase{
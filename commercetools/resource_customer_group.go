@@ -51,7 +51,7 @@ func resourceCustomerGroupCreate(ctx context.Context, d *schema.ResourceData, m
 		Key:       stringRef(d.Get("key")),
 	}
 
-	errorResponse := resource.RetryContext(ctx, 1*time.Minute, func() *resource.RetryError {
+	errorResponse := retryContext(ctx, m, "create customer group", 1*time.Minute, func() *resource.RetryError {
 		var err error
 
 		customerGroup, err = client.CustomerGroups().Post(draft).Execute(ctx)
@@ -63,7 +63,7 @@ func resourceCustomerGroupCreate(ctx context.Context, d *schema.ResourceData, m
 	})
 
 	if errorResponse != nil {
-		return diag.FromErr(errorResponse)
+		return diagnosticsFromError(errorResponse)
 	}
 
 	if customerGroup == nil {
@@ -90,7 +90,7 @@ func resourceCustomerGroupRead(ctx context.Context, d *schema.ResourceData, m in
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if customerGroup == nil {
@@ -112,7 +112,7 @@ func resourceCustomerGroupUpdate(ctx context.Context, d *schema.ResourceData, m
 	client := getClient(m)
 	customerGroup, err := client.CustomerGroups().WithId(d.Id()).Get().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input := platform.CustomerGroupUpdate{
@@ -143,7 +143,7 @@ func resourceCustomerGroupUpdate(ctx context.Context, d *schema.ResourceData, m
 		if ctErr, ok := err.(platform.ErrorResponse); ok {
 			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceCustomerGroupRead(ctx, d, m)
@@ -2,10 +2,19 @@ package commercetools
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/labd/commercetools-go-sdk/platform"
@@ -16,42 +25,70 @@ func resourceDiscountCode() *schema.Resource {
 		Description: "With discount codes it is possible to give specific cart discounts to an eligible set of users. " +
 			"They are defined by a string value which can be added to a cart so that specific cart discounts " +
 			"can be applied to the cart.\n\n" +
+			"All changes are sent in a single update request. A change of the custom type is applied first, " +
+			"changes to the custom fields last, so fields of a new type can be set in the same apply.\n\n" +
+			"commercetools doesn't schedule changes of `is_active`. Use `valid_from` and `valid_until` to " +
+			"limit when a code can be used, `effective_status` shows whether the code can be used right now.\n\n" +
 			"See also the [Discount Code Api Documentation](https://docs.commercetools.com/api/projects/discountCodes)",
 		CreateContext: resourceDiscountCodeCreate,
 		ReadContext:   resourceDiscountCodeRead,
 		UpdateContext: resourceDiscountCodeUpdate,
 		DeleteContext: resourceDiscountCodeDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceDiscountCodeImportState,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Read:   schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+		CustomizeDiff: customdiff.All(
+			validateDiscountCodeUnique,
+			validateDiscountCodeStores,
+			validateDiscountCodeLocales,
+			validatePredicateReferences("predicate"),
+			validateCustomFieldTypes(),
+			planDiscountCodeUpdateActions,
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+				Description: "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring). " +
+					"Setting an empty map does not clear an existing name, remove the attribute to clear it",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
+				DiffSuppressFunc: diffSuppressEmptyLocalizedString,
 				Optional:         true,
 			},
 			"description": {
-				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+				Description: "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring). " +
+					"Setting an empty map does not clear an existing description, remove the attribute to clear it",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
+				DiffSuppressFunc: diffSuppressEmptyLocalizedString,
 				Optional:         true,
 			},
 			"code": {
-				Description: "Unique identifier of this discount code. This value is added to the cart to enable " +
-					"the related cart discounts in the cart",
+				Description: "The redeemable string of this discount code, unique within the project. This value " +
+					"is added to the cart to enable the related cart discounts in the cart. It is not the ID of the " +
+					"discount code, which is generated by commercetools. When planning it is checked that no other " +
+					"resource in the configuration and no discount code in the project uses the same code. Discount " +
+					"codes which are deactivated or were created by the API client of the provider are not reported, " +
+					"so replacing a discount code or moving its code to another resource doesn't fail the plan",
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"valid_from": {
-				Description: "The time from which the discount can be applied on a cart. Before that time the code is invalid",
-				Type:        schema.TypeString,
-				Optional:    true,
+				Description:      "The time from which the discount can be applied on a cart. Before that time the code is invalid",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressTime,
 			},
 			"valid_until": {
-				Description: "The time until the discount can be applied on a cart. After that time the code is invalid",
-				Type:        schema.TypeString,
-				Optional:    true,
+				Description:      "The time until the discount can be applied on a cart. After that time the code is invalid",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressTime,
 			},
 			"is_active": {
 				Type:     schema.TypeBool,
@@ -59,14 +96,25 @@ func resourceDiscountCode() *schema.Resource {
 				Default:  true,
 			},
 			"predicate": {
-				Description: "[Cart Predicate](https://docs.commercetools.com/api/projects/predicates#cart-predicates)",
-				Type:        schema.TypeString,
-				Optional:    true,
+				Description:      "[Cart Predicate](https://docs.commercetools.com/api/projects/predicates#cart-predicates)",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressPredicate,
+			},
+			"stores": {
+				Description: "Keys of the stores in which the discount code can be used. This is a convenience " +
+					"attribute which adds a `store.key in (...)` clause to the cart predicate, the stores are read " +
+					"back from that clause",
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"max_applications_per_customer": {
-				Description: "The discount code can only be applied maxApplicationsPerCustomer times per customer",
-				Type:        schema.TypeInt,
-				Optional:    true,
+				Description: "The discount code can only be applied maxApplicationsPerCustomer times per customer. " +
+					"Must be at least 1, omit it to allow unlimited applications",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validateMaxApplicationsPerCustomer,
 			},
 			"max_applications": {
 				Description: "The discount code can only be applied maxApplications times",
@@ -80,15 +128,94 @@ func resourceDiscountCode() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 			"cart_discounts": {
-				Description: "The referenced matching cart discounts can be applied to the cart once the DiscountCode is added",
-				Type:        schema.TypeList,
-				Required:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The referenced matching cart discounts can be applied to the cart once the DiscountCode " +
+					"is added. Cart discounts are referenced by their ID, or by their key for values which are not a UUID",
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"validate_predicate_references": validatePredicateReferencesSchema(),
+			"adopt_existing": {
+				Description: "When creating the discount code fails because the code already exists, adopt the " +
+					"existing discount code into the state and update it to match the configuration. Useful " +
+					"when a previous apply was interrupted after the discount code was created",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"import_if_exists": {
+				Description: "Before creating the discount code, look up an existing discount code with the same " +
+					"code. When it exists it is imported into the state instead of created, and updated where its " +
+					"attributes differ from the configuration. Useful to adopt the codes of a shared project",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"wait_for_create_consistency": {
+				Description: "Retry reading the discount code after it was created while commercetools returns a " +
+					"404 for it, until the create timeout expires. Disable to fail fast, for example in CI, the " +
+					"created discount code is then tainted when it can't be read right away",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"disable_on_destroy": {
+				Description: "Deactivate the discount code instead of deleting it when it is destroyed. The code " +
+					"is removed from the state but stays in commercetools, so its redemption history is kept and " +
+					"the code can't be created again by Terraform until it is deleted or imported",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"refuse_delete_if_redeemed": {
+				Description: "Fail to destroy the discount code when an order used it, to protect its redemption " +
+					"history. commercetools doesn't expose the number of redemptions, so the orders are queried " +
+					"for the code, which requires the `view_orders` scope. Has no effect with `disable_on_destroy`",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
+			"custom":                 customFieldSchema(),
+			"validate_custom_fields": validateCustomFieldsSchema(),
 			"version": {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_by": {
+				Description: "The client or user which created the discount code",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        modifiedBySchema(),
+			},
+			"last_modified_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"effective_status": {
+				Description: "Whether the code can be used when it was last read: `active`, `inactive` when " +
+					"`is_active` is false, `scheduled` before `valid_from` or `expired` after `valid_until`",
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"planned_actions": {
+				Description: "The update actions sent to commercetools for the last applied change, encoded " +
+					"as JSON. The plan of an update shows the actions which will be sent, so reviewers can see " +
+					"the API calls which are made",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"last_modified_by": {
+				Description: "The client or user which last modified the discount code, use it to detect changes " +
+					"made outside of Terraform",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     modifiedBySchema(),
+			},
 		},
 	}
 }
@@ -97,76 +224,324 @@ func resourceDiscountCodeCreate(ctx context.Context, d *schema.ResourceData, m i
 	client := getClient(m)
 	var discountCode *platform.DiscountCode
 
+	draft, err := unmarshallDiscountCodeDraft(d, d.Get("code").(string))
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	if d.Get("import_if_exists").(bool) {
+		existing, err := findDiscountCodeByCode(ctx, client, draft.Code)
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+		if existing != nil {
+			return resourceDiscountCodeImportExisting(ctx, d, m, existing)
+		}
+	}
+
+	// Send our own correlation ID, so a failed request can be found in the
+	// logs of commercetools
+	correlationID := newCorrelationID()
+	headers := http.Header{}
+	headers.Set("X-Correlation-ID", correlationID)
+	log.Printf("[DEBUG] Creating discount code with correlation id %s:\n%s", correlationID, stringFormatObject(draft))
+
+	start := time.Now()
+	attempts := 0
+	errorResponse := retryContext(ctx, m, "create discount code", d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		var err error
+
+		attempts++
+		discountCode, err = client.DiscountCodes().Post(draft).WithHeaders(headers).Execute(ctx)
+
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+
+	if errorResponse != nil {
+		_, duplicate := duplicateFieldError(errorResponse, "code")
+		if duplicate && d.Get("adopt_existing").(bool) {
+			return resourceDiscountCodeAdopt(ctx, d, m)
+		}
+		// A request which timed out, or which was retried because it got no
+		// response, may have created the discount code, which would be
+		// orphaned without its ID in the state. The context of the create
+		// has expired after a timeout, so the discount code is looked up with
+		// a new one.
+		if isRetryTimeout(errorResponse) || (duplicate && attempts > 1) {
+			recoverCtx, cancel := context.WithTimeout(context.Background(), discountCodeRecoverTimeout)
+			defer cancel()
+			if created := findCreatedDiscountCode(recoverCtx, client, draft.Code, start); created != nil {
+				return resourceDiscountCodeRecoverCreate(recoverCtx, d, m, created)
+			}
+		}
+		return diagnosticsFromError(errorResponse)
+	}
+
+	if discountCode == nil {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("No discount code created for code %q", draft.Code),
+			Detail: fmt.Sprintf(
+				"commercetools accepted the request but returned no discount code. "+
+					"The correlation id of the request is %s", correlationID),
+		}}
+	}
+
+	d.SetId(discountCode.ID)
+	d.Set("version", discountCode.Version)
+
+	if d.Get("wait_for_create_consistency").(bool) {
+		err := waitForConsistency(ctx, m, "read created discount code", d.Timeout(schema.TimeoutCreate), func() error {
+			_, err := client.DiscountCodes().WithId(discountCode.ID).Get().Execute(ctx)
+			return err
+		})
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+	} else {
+		// The read removes a discount code which is not found from the state,
+		// which would orphan the created discount code. Failing keeps the ID,
+		// so terraform taints the discount code instead.
+		_, err := client.DiscountCodes().WithId(discountCode.ID).Get().Execute(ctx)
+		if isNotFoundError(err) {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Discount code %s was created but can't be read yet", draft.Code),
+				Detail: fmt.Sprintf("commercetools created the discount code with id %s, but returned a 404 "+
+					"when reading it and wait_for_create_consistency is disabled. The discount code is kept in "+
+					"the state as tainted, so the next apply replaces it.", discountCode.ID),
+			}}
+		}
+	}
+
+	d.Set("planned_actions", []string{})
+	diags := checkDiscountCodeCartDiscounts(ctx, client, draft.CartDiscounts)
+	readDiags := resourceDiscountCodeRead(ctx, d, m)
+	if warning := discountCodeExpiredWarning(draft.Code, draft.ValidUntil, time.Now()); warning != nil {
+		// The read warns that the code is active but expired, which is the
+		// same problem, so only the more specific warning is shown
+		diags = append(diags, *warning)
+		for _, readDiag := range readDiags {
+			if readDiag.Summary != discountCodeActiveExpiredSummary(draft.Code) {
+				diags = append(diags, readDiag)
+			}
+		}
+		return diags
+	}
+	return append(diags, readDiags...)
+}
+
+// unmarshallDiscountCodeDraft returns the draft of a discount code with the
+// given code and the other attributes of d
+func unmarshallDiscountCodeDraft(d *schema.ResourceData, code string) (platform.DiscountCodeDraft, error) {
 	name := unmarshallLocalizedString(d.Get("name"))
 	description := unmarshallLocalizedString(d.Get("description"))
 
+	custom, err := unmarshallCustomFieldsDraft(d)
+	if err != nil {
+		return platform.DiscountCodeDraft{}, err
+	}
+
 	draft := platform.DiscountCodeDraft{
 		Name:                       &name,
 		Description:                &description,
-		Code:                       d.Get("code").(string),
-		CartPredicate:              stringRef(d.Get("predicate")),
+		Code:                       code,
+		CartPredicate:              stringRef(unmarshallDiscountCodePredicate(d)),
 		IsActive:                   boolRef(d.Get("is_active")),
-		MaxApplicationsPerCustomer: intRef(d.Get("max_applications_per_customer")),
+		MaxApplicationsPerCustomer: unmarshallMaxApplicationsPerCustomer(d),
 		MaxApplications:            intRef(d.Get("max_applications")),
 		Groups:                     unmarshallDiscountCodeGroups(d),
 		CartDiscounts:              unmarshallDiscountCodeCartDiscounts(d),
+		Custom:                     custom,
 	}
 
 	if val := d.Get("valid_from").(string); len(val) > 0 {
 		validFrom, err := unmarshallTime(val)
 		if err != nil {
-			return diag.FromErr(err)
+			return platform.DiscountCodeDraft{}, err
 		}
 		draft.ValidFrom = &validFrom
 	}
 	if val := d.Get("valid_until").(string); len(val) > 0 {
 		validUntil, err := unmarshallTime(val)
 		if err != nil {
-			return diag.FromErr(err)
+			return platform.DiscountCodeDraft{}, err
 		}
 		draft.ValidUntil = &validUntil
 	}
+	return draft, nil
+}
 
-	errorResponse := resource.RetryContext(ctx, 1*time.Minute, func() *resource.RetryError {
-		var err error
+// resourceDiscountCodeAdopt takes over an existing discount code with the
+// configured code, for example when a previous apply was interrupted after the
+// discount code was created. The configuration is applied to it with an update.
+func resourceDiscountCodeAdopt(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	code := d.Get("code").(string)
+
+	existing, err := findDiscountCodeByCode(ctx, getClient(m), code)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+	if existing == nil {
+		return diag.Errorf("discount code %s already exists but could not be found", code)
+	}
 
-		discountCode, err = client.DiscountCodes().Post(draft).Execute(ctx)
+	log.Printf("[INFO] Adopting existing discount code %s with id %s", code, existing.ID)
+	d.SetId(existing.ID)
+	d.Set("version", existing.Version)
 
-		if err != nil {
-			return handleCommercetoolsError(err)
-		}
-		return nil
-	})
+	diags := diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Adopted existing discount code %s", code),
+		Detail: fmt.Sprintf("A discount code with code %s already existed (id %s) and was adopted "+
+			"because adopt_existing is set. It is updated to match the configuration.", code, existing.ID),
+	}}
+	return append(diags, resourceDiscountCodeUpdate(ctx, d, m)...)
+}
 
-	if errorResponse != nil {
-		return diag.FromErr(errorResponse)
+// resourceDiscountCodeImportExisting imports the existing discount code with
+// the configured code into the state. Unlike adopt_existing, which applies
+// every configured attribute, only the attributes of the existing discount
+// code which differ from the configuration are updated, including the ones
+// which are not configured.
+func resourceDiscountCodeImportExisting(ctx context.Context, d *schema.ResourceData, m interface{}, existing *platform.DiscountCode) diag.Diagnostics {
+	log.Printf("[INFO] Importing existing discount code %s with id %s", existing.Code, existing.ID)
+	d.SetId(existing.ID)
+	d.Set("version", existing.Version)
+
+	change := discountCodeReconciliation{ResourceData: d, existing: existingDiscountCodeData(d, existing)}
+	diags := diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Imported existing discount code %s", existing.Code),
+		Detail: fmt.Sprintf("A discount code with code %s already existed (id %s) and was imported because "+
+			"import_if_exists is set. The attributes which differ from the configuration are updated.",
+			existing.Code, existing.ID),
+	}}
+	return append(diags, updateDiscountCode(ctx, d, change, m)...)
+}
+
+// discountCodeRecoverTimeout limits looking up and reading a discount code
+// which was created by a failed create
+const discountCodeRecoverTimeout = time.Minute
+
+// findCreatedDiscountCode returns the discount code with the code when it was
+// created after start, allowing for a minute of clock skew with commercetools.
+// Errors are only logged, since the create already failed.
+func findCreatedDiscountCode(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, code string, start time.Time) *platform.DiscountCode {
+	existing, err := findDiscountCodeByCode(ctx, client, code)
+	if err != nil {
+		log.Printf("[WARN] Unable to check if discount code %s was created: %s", code, err)
+		return nil
+	}
+	if existing == nil || existing.CreatedAt.Before(start.Add(-1*time.Minute)) {
+		return nil
 	}
+	return existing
+}
 
-	if discountCode == nil {
-		return diag.Errorf("No discount code created")
+// resourceDiscountCodeRecoverCreate puts a discount code which was created by
+// a request without a response into the state
+func resourceDiscountCodeRecoverCreate(ctx context.Context, d *schema.ResourceData, m interface{}, created *platform.DiscountCode) diag.Diagnostics {
+	log.Printf("[INFO] Discount code %s was created with id %s although the create failed", created.Code, created.ID)
+	d.SetId(created.ID)
+	d.Set("version", created.Version)
+
+	diags := diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Discount code %s was created although the create failed", created.Code),
+		Detail: fmt.Sprintf("The create did not get a successful response within its timeout, but the "+
+			"discount code was created at %s (id %s). It is added to the state.",
+			marshallTime(&created.CreatedAt), created.ID),
+	}}
+	return append(diags, resourceDiscountCodeRead(ctx, d, m)...)
+}
+
+// discountCodeReconciliation is the change from an existing discount code to
+// the configuration of d
+type discountCodeReconciliation struct {
+	*schema.ResourceData
+	existing *schema.ResourceData
+}
+
+func (r discountCodeReconciliation) GetChange(key string) (interface{}, interface{}) {
+	return r.existing.Get(key), r.ResourceData.Get(key)
+}
+
+func (r discountCodeReconciliation) HasChange(key string) bool {
+	old, new := r.GetChange(key)
+	if set, ok := old.(*schema.Set); ok {
+		return !set.Equal(new)
 	}
+	return !reflect.DeepEqual(old, new)
+}
 
-	d.SetId(discountCode.ID)
-	d.Set("version", discountCode.Version)
+// existingDiscountCodeData returns the attributes of the existing discount
+// code in the same format as the configuration of d, so they can be compared
+func existingDiscountCodeData(d *schema.ResourceData, existing *platform.DiscountCode) *schema.ResourceData {
+	result := resourceDiscountCode().Data(nil)
+	predicate, stores := marshallDiscountCodePredicate(existing.CartPredicate, d)
+	result.Set("predicate", predicate)
+	result.Set("stores", stores)
+	result.Set("name", existing.Name)
+	result.Set("description", existing.Description)
+	result.Set("cart_discounts", marshallDiscountCodeCartDiscounts(
+		existing.CartDiscounts, expandStringArray(d.Get("cart_discounts").([]interface{}))))
+	result.Set("groups", marshallDiscountCodeGroups(existing.Groups, d))
+	result.Set("is_active", existing.IsActive)
+	result.Set("valid_from", marshallTime(existing.ValidFrom))
+	result.Set("valid_until", marshallTime(existing.ValidUntil))
+	result.Set("max_applications_per_customer", existing.MaxApplicationsPerCustomer)
+	result.Set("max_applications", existing.MaxApplications)
+	result.Set("custom", marshallCustomFields(existing.Custom))
+	return result
+}
 
-	return resourceDiscountCodeRead(ctx, d, m)
+// findDiscountCodeByCode returns the discount code with the code, with the
+// cart discounts expanded, or nil when there is none
+func findDiscountCodeByCode(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, code string) (*platform.DiscountCode, error) {
+	result, err := client.DiscountCodes().Get().
+		Where([]string{fmt.Sprintf("code = %s", quotePredicateString(code))}).
+		Expand([]string{"cartDiscounts[*]"}).
+		Limit(1).
+		Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Results) == 0 {
+		return nil, nil
+	}
+	return &result.Results[0], nil
 }
 
 func resourceDiscountCodeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Reading discount code from commercetools, with discount code id: %s", d.Id())
 
 	client := getClient(m)
+	var discountCode *platform.DiscountCode
 
-	discountCode, err := client.DiscountCodes().WithId(d.Id()).Get().Execute(ctx)
+	err := retryContext(ctx, m, "read discount code", d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+		var err error
+		discountCode, err = client.DiscountCodes().WithId(d.Id()).Get().
+			Expand([]string{"cartDiscounts[*]"}).
+			Execute(ctx)
+		if err != nil {
+			if isNotFoundError(err) {
+				return resource.NonRetryableError(err)
+			}
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
 
 	if err != nil {
-		if ctErr, ok := err.(platform.ErrorResponse); ok {
-			if ctErr.StatusCode == 404 {
-				d.SetId("")
-				return nil
-			}
+		if isNotFoundError(err) {
+			d.SetId("")
+			return nil
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if discountCode == nil {
@@ -180,171 +555,940 @@ func resourceDiscountCodeRead(ctx context.Context, d *schema.ResourceData, m int
 		d.Set("code", discountCode.Code)
 		d.Set("name", discountCode.Name)
 		d.Set("description", discountCode.Description)
-		d.Set("predicate", discountCode.CartPredicate)
-		d.Set("cart_discounts", marshallDiscountCodeCartDiscounts(discountCode.CartDiscounts))
-		d.Set("groups", discountCode.Groups)
+		predicate, stores := marshallDiscountCodePredicate(discountCode.CartPredicate, d)
+		d.Set("predicate", predicate)
+		d.Set("stores", stores)
+		d.Set("cart_discounts", marshallDiscountCodeCartDiscounts(
+			discountCode.CartDiscounts, expandStringArray(d.Get("cart_discounts").([]interface{}))))
+		d.Set("groups", marshallDiscountCodeGroups(discountCode.Groups, d))
 		d.Set("is_active", discountCode.IsActive)
 		d.Set("valid_from", marshallTime(discountCode.ValidFrom))
 		d.Set("valid_until", marshallTime(discountCode.ValidUntil))
 		d.Set("max_applications_per_customer", discountCode.MaxApplicationsPerCustomer)
 		d.Set("max_applications", discountCode.MaxApplications)
+		d.Set("custom", marshallCustomFields(discountCode.Custom))
+		d.Set("created_at", marshallTime(&discountCode.CreatedAt))
+		d.Set("created_by", marshallCreatedBy(discountCode.CreatedBy))
+		d.Set("last_modified_at", marshallTime(&discountCode.LastModifiedAt))
+		d.Set("last_modified_by", marshallLastModifiedBy(discountCode.LastModifiedBy))
+
+		status := discountCodeEffectiveStatus(discountCode, time.Now())
+		d.Set("effective_status", status)
+		if status == discountCodeStatusExpired && discountCode.IsActive {
+			return diag.Diagnostics{{
+				Severity: diag.Warning,
+				Summary:  discountCodeActiveExpiredSummary(discountCode.Code),
+				Detail: fmt.Sprintf("The discount code is active, but its valid_until (%s) is in the past, so "+
+					"it can't be used anymore. Set is_active to false or extend valid_until.",
+					marshallTime(discountCode.ValidUntil)),
+			}}
+		}
 	}
 
 	return nil
 }
 
+func discountCodeActiveExpiredSummary(code string) string {
+	return fmt.Sprintf("Discount code %s is active but expired", code)
+}
+
+const (
+	discountCodeStatusActive    = "active"
+	discountCodeStatusInactive  = "inactive"
+	discountCodeStatusScheduled = "scheduled"
+	discountCodeStatusExpired   = "expired"
+)
+
+// discountCodeEffectiveStatus returns whether the discount code can be used
+// at the given time. commercetools only checks the validity period when the
+// code is added to a cart, the is_active flag is never changed by it.
+func discountCodeEffectiveStatus(discountCode *platform.DiscountCode, now time.Time) string {
+	switch {
+	case !discountCode.IsActive:
+		return discountCodeStatusInactive
+	case discountCode.ValidUntil != nil && now.After(*discountCode.ValidUntil):
+		return discountCodeStatusExpired
+	case discountCode.ValidFrom != nil && now.Before(*discountCode.ValidFrom):
+		return discountCodeStatusScheduled
+	}
+	return discountCodeStatusActive
+}
+
 func resourceDiscountCodeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return updateDiscountCode(ctx, d, d, m)
+}
+
+// updateDiscountCode sends the update actions of the change to the discount
+// code of d and reads it afterwards
+func updateDiscountCode(ctx context.Context, d *schema.ResourceData, change resourceChange, m interface{}) diag.Diagnostics {
 	client := getClient(m)
 	discountCode, err := client.DiscountCodes().WithId(d.Id()).Get().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
-	input := platform.DiscountCodeUpdate{
-		Version: discountCode.Version,
-		Actions: []platform.DiscountCodeUpdateAction{},
+	actions, err := buildDiscountCodeUpdateActions(change)
+	if err != nil {
+		return diagnosticsFromError(err)
 	}
 
-	if d.HasChange("name") {
-		newName := unmarshallLocalizedString(d.Get("name"))
-		input.Actions = append(
-			input.Actions,
-			&platform.DiscountCodeSetNameAction{Name: &newName})
+	var diags diag.Diagnostics
+	for _, key := range []string{"name", "description"} {
+		if change.HasChange(key) && skipEmptyLocalizedString(d, key) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The %s of the discount code is not cleared", key),
+				Detail: fmt.Sprintf("The %s is set to an empty map, which is ignored to prevent clearing it "+
+					"by accident. Remove the %s attribute from the configuration to clear it.", key, key),
+			})
+		}
+	}
+	if len(actions) == 0 {
+		return append(diags, resourceDiscountCodeRead(ctx, d, m)...)
 	}
 
-	if d.HasChange("description") {
-		newDescription := unmarshallLocalizedString(d.Get("description"))
-		input.Actions = append(
-			input.Actions,
-			&platform.DiscountCodeSetDescriptionAction{Description: &newDescription})
+	// The update is sent with the version terraform read when conflicts are
+	// not retried, so it fails when the discount code was changed since
+	version := discountCode.Version
+	if !retryOnConflict(m) {
+		version = d.Get("version").(int)
 	}
 
-	if d.HasChange("predicate") {
-		newPredicate := d.Get("predicate").(string)
-		input.Actions = append(
-			input.Actions,
-			&platform.DiscountCodeSetCartPredicateAction{CartPredicate: &newPredicate})
+	log.Printf(
+		"[DEBUG] Will perform update operation with the following actions:\n%s",
+		stringFormatActions(actions))
+
+	err = retryContext(ctx, m, "update discount code", d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		input := platform.DiscountCodeUpdate{
+			Version: version,
+			Actions: actions,
+		}
+		_, err := client.DiscountCodes().WithId(discountCode.ID).Post(input).Execute(ctx)
+		if err != nil {
+			if isConflictError(err) && retryOnConflict(m) {
+				current, getErr := client.DiscountCodes().WithId(discountCode.ID).Get().Execute(ctx)
+				if getErr != nil {
+					return handleCommercetoolsError(getErr)
+				}
+				log.Printf("[DEBUG] Discount code %s was changed, retrying with version %d", discountCode.ID, current.Version)
+				version = current.Version
+				return resource.RetryableError(err)
+			}
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		if ctErr, ok := err.(platform.ErrorResponse); ok {
+			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
+		}
+		if isConflictError(err) && !retryOnConflict(m) {
+			return append(diags, conflictDiagnostics("discount code", discountCode.ID, err)...)
+		}
+		return diagnosticsFromError(err)
 	}
 
-	if d.HasChange("max_applications") {
-		newMaxApplications := d.Get("max_applications").(int)
-		input.Actions = append(
-			input.Actions,
-			&platform.DiscountCodeSetMaxApplicationsAction{MaxApplications: &newMaxApplications})
+	// The sent actions are kept, so the state matches the planned_actions of
+	// the plan
+	plannedActions, err := marshallDiscountCodeActions(actions)
+	if err != nil {
+		return diagnosticsFromError(err)
 	}
+	d.Set("planned_actions", plannedActions)
 
-	if d.HasChange("max_applications_per_customer") {
-		newMaxApplications := d.Get("max_applications_per_customer").(int)
-		input.Actions = append(
-			input.Actions,
-			&platform.DiscountCodeSetMaxApplicationsPerCustomerAction{MaxApplicationsPerCustomer: &newMaxApplications})
+	if change.HasChange("cart_discounts") {
+		diags = append(diags, checkDiscountCodeCartDiscounts(ctx, client, unmarshallDiscountCodeCartDiscounts(d))...)
+	}
+	return append(diags, resourceDiscountCodeRead(ctx, d, m)...)
+}
+
+// buildDiscountCodeUpdateActions returns the update actions for the changed
+// attributes. Commercetools applies the actions of a request in order, so they
+// are always emitted in the same order:
+//
+//  1. the custom type, since custom fields can only be set once it is attached
+//  2. structural changes: cart discounts, predicate and groups
+//  3. value changes like the name, limits and validity
+//  4. the custom fields
+func buildDiscountCodeUpdateActions(d resourceChange) ([]platform.DiscountCodeUpdateAction, error) {
+	actions := []platform.DiscountCodeUpdateAction{}
+
+	// Removing the custom block detaches the type, which also removes all its
+	// fields. Fields removed from a block which is kept are cleared one by one.
+	customTypeChanged := d.HasChange("custom.0.type_id")
+	if customTypeChanged {
+		action := &platform.DiscountCodeSetCustomTypeAction{}
+		if typeID := d.Get("custom.0.type_id").(string); typeID != "" {
+			action.Type = &platform.TypeResourceIdentifier{ID: &typeID}
+		}
+		actions = append(actions, action)
 	}
 
 	if d.HasChange("cart_discounts") {
+		logStringListChange(d, "cart_discounts")
 		newCartDiscounts := unmarshallDiscountCodeCartDiscounts(d)
-		input.Actions = append(
-			input.Actions,
+		actions = append(
+			actions,
 			&platform.DiscountCodeChangeCartDiscountsAction{CartDiscounts: newCartDiscounts})
 	}
 
+	if d.HasChange("predicate") || d.HasChange("stores") {
+		newPredicate := unmarshallDiscountCodePredicate(d)
+		actions = append(
+			actions,
+			&platform.DiscountCodeSetCartPredicateAction{CartPredicate: &newPredicate})
+	}
+
+	// ChangeGroups replaces the groups of the discount code, so the complete
+	// new list is always sent. An empty list removes the code from all groups.
 	if d.HasChange("groups") {
 		newGroups := unmarshallDiscountCodeGroups(d)
-		if len(newGroups) > 0 {
-			input.Actions = append(
-				input.Actions,
-				&platform.DiscountCodeChangeGroupsAction{Groups: newGroups})
-		} else {
-			input.Actions = append(
-				input.Actions,
-				&platform.DiscountCodeChangeGroupsAction{Groups: []string{}})
+		if newGroups == nil {
+			newGroups = []string{}
 		}
+		actions = append(
+			actions,
+			&platform.DiscountCodeChangeGroupsAction{Groups: newGroups})
+	}
+
+	if d.HasChange("name") && !skipEmptyLocalizedString(d, "name") {
+		logLocalizedStringChange(d, "name")
+		newName := unmarshallLocalizedString(d.Get("name"))
+		actions = append(
+			actions,
+			&platform.DiscountCodeSetNameAction{Name: &newName})
+	}
+
+	if d.HasChange("description") && !skipEmptyLocalizedString(d, "description") {
+		logLocalizedStringChange(d, "description")
+		newDescription := unmarshallLocalizedString(d.Get("description"))
+		actions = append(
+			actions,
+			&platform.DiscountCodeSetDescriptionAction{Description: &newDescription})
+	}
+
+	if d.HasChange("max_applications") {
+		newMaxApplications := d.Get("max_applications").(int)
+		actions = append(
+			actions,
+			&platform.DiscountCodeSetMaxApplicationsAction{MaxApplications: &newMaxApplications})
+	}
+
+	if d.HasChange("max_applications_per_customer") {
+		actions = append(
+			actions,
+			&platform.DiscountCodeSetMaxApplicationsPerCustomerAction{
+				MaxApplicationsPerCustomer: unmarshallMaxApplicationsPerCustomer(d),
+			})
 	}
 
 	if d.HasChange("is_active") {
 		newIsActive := d.Get("is_active").(bool)
-		input.Actions = append(
-			input.Actions,
+		actions = append(
+			actions,
 			&platform.DiscountCodeChangeIsActiveAction{IsActive: newIsActive})
 	}
 
-	if d.HasChange("valid_from") {
-		if val := d.Get("valid_from").(string); len(val) > 0 {
-			newValidFrom, err := unmarshallTime(d.Get("valid_from").(string))
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			input.Actions = append(
-				input.Actions,
-				&platform.DiscountCodeSetValidFromAction{ValidFrom: &newValidFrom})
-		} else {
-			input.Actions = append(
-				input.Actions,
-				&platform.DiscountCodeSetValidFromAction{})
+	if fromChanged, untilChanged := d.HasChange("valid_from"), d.HasChange("valid_until"); fromChanged || untilChanged {
+		validFrom, err := unmarshallOptionalTime(d.Get("valid_from").(string))
+		if err != nil {
+			return nil, err
+		}
+		validUntil, err := unmarshallOptionalTime(d.Get("valid_until").(string))
+		if err != nil {
+			return nil, err
+		}
+		// Only the changed times are sent, setting an unchanged valid_from
+		// again would restart the validity period of the code
+		switch {
+		case fromChanged && untilChanged:
+			actions = append(
+				actions,
+				&platform.DiscountCodeSetValidFromAndUntilAction{ValidFrom: validFrom, ValidUntil: validUntil})
+		case fromChanged:
+			actions = append(
+				actions,
+				&platform.DiscountCodeSetValidFromAction{ValidFrom: validFrom})
+		default:
+			actions = append(
+				actions,
+				&platform.DiscountCodeSetValidUntilAction{ValidUntil: validUntil})
 		}
 	}
 
-	if d.HasChange("valid_until") {
-		if val := d.Get("valid_until").(string); len(val) > 0 {
-			newValidUntil, err := unmarshallTime(d.Get("valid_until").(string))
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			input.Actions = append(
-				input.Actions,
-				&platform.DiscountCodeSetValidUntilAction{ValidUntil: &newValidUntil})
-		} else {
-			input.Actions = append(
-				input.Actions,
-				&platform.DiscountCodeSetValidUntilAction{})
-		}
+	if d.Get("custom.0.type_id").(string) != "" && (customTypeChanged || d.HasChange("custom.0.fields")) {
+		actions = append(actions, discountCodeCustomFieldActions(d, customTypeChanged)...)
 	}
 
-	log.Printf(
-		"[DEBUG] Will perform update operation with the following actions:\n%s",
-		stringFormatActions(input.Actions))
+	return actions, nil
+}
 
-	_, err = client.DiscountCodes().WithId(discountCode.ID).Post(input).Execute(ctx)
+// discountCodeUpdateKeys are the attributes which are used to build the update
+// actions of a discount code
+var discountCodeUpdateKeys = []string{
+	"name", "description", "predicate", "stores", "cart_discounts", "groups", "is_active",
+	"valid_from", "valid_until", "max_applications", "max_applications_per_customer", "custom",
+}
+
+// planDiscountCodeUpdateActions sets planned_actions to the update actions for
+// the planned change. When a value is only known after applying the actions
+// can't be built yet. Without actions the last applied actions are kept, since
+// nothing is sent on apply.
+func planDiscountCodeUpdateActions(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	for _, key := range discountCodeUpdateKeys {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("planned_actions")
+		}
+	}
+
+	actions, err := buildDiscountCodeUpdateActions(d)
+	if err != nil || len(actions) == 0 {
+		return err
+	}
+	result, err := marshallDiscountCodeActions(actions)
 	if err != nil {
-		if ctErr, ok := err.(platform.ErrorResponse); ok {
-			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
+		return err
+	}
+	return d.SetNew("planned_actions", result)
+}
+
+// marshallDiscountCodeActions encodes each update action as JSON
+func marshallDiscountCodeActions(actions []platform.DiscountCodeUpdateAction) ([]string, error) {
+	result := make([]string, len(actions))
+	for i, action := range actions {
+		data, err := json.Marshal(action)
+		if err != nil {
+			return nil, err
 		}
-		return diag.FromErr(err)
+		result[i] = string(data)
 	}
+	return result, nil
+}
 
-	return resourceDiscountCodeRead(ctx, d, m)
+// skipEmptyLocalizedString returns true when the localized string is set to
+// an explicit empty map in the configuration. This often happens by accident,
+// for example when a module passes an empty map, so it doesn't clear the
+// value. Removing the attribute from the configuration clears it.
+func skipEmptyLocalizedString(d resourceChange, key string) bool {
+	return isExplicitEmptyMap(d.GetRawConfig(), key)
+}
+
+// diffSuppressEmptyLocalizedString suppresses the diff of a localized string
+// which is set to an explicit empty map, since the update skips it anyway, see
+// skipEmptyLocalizedString. Otherwise the plan would show the change on every
+// run without ever applying it.
+func diffSuppressEmptyLocalizedString(k, old, new string, d *schema.ResourceData) bool {
+	return skipEmptyLocalizedString(d, strings.SplitN(k, ".", 2)[0])
+}
+
+// discountCodeCustomFieldActions returns a SetCustomField action for every
+// changed field. When the type did not change, fields which were removed
+// from the configuration are cleared. When the type changed all fields were
+// reset by the SetCustomType action, so every configured field is set.
+func discountCodeCustomFieldActions(d resourceChange, typeChanged bool) []platform.DiscountCodeUpdateAction {
+	old, new := d.GetChange("custom.0.fields")
+	oldFields := unmarshallCustomFieldContainer(old)
+	newFields := unmarshallCustomFieldContainer(new)
+
+	names := make([]string, 0, len(newFields))
+	for name, value := range newFields {
+		if oldValue, ok := oldFields[name]; typeChanged || !ok || !reflect.DeepEqual(oldValue, value) {
+			names = append(names, name)
+		}
+	}
+	if !typeChanged {
+		for name := range oldFields {
+			if _, ok := newFields[name]; !ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	actions := make([]platform.DiscountCodeUpdateAction, 0, len(names))
+	for _, name := range names {
+		actions = append(actions, &platform.DiscountCodeSetCustomFieldAction{
+			Name:  name,
+			Value: newFields[name],
+		})
+	}
+	return actions
 }
 
 func resourceDiscountCodeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.Get("disable_on_destroy").(bool) {
+		return resourceDiscountCodeDisable(ctx, d, m)
+	}
+
 	client := getClient(m)
+	if d.Get("refuse_delete_if_redeemed").(bool) {
+		if diags := checkDiscountCodeNotRedeemed(ctx, client, d); diags != nil {
+			return diags
+		}
+	}
+
 	version := d.Get("version").(int)
-	_, err := client.DiscountCodes().WithId(d.Id()).Delete().Version(version).DataErasure(true).Execute(ctx)
+	dataErasure := getProviderMeta(m).dataErasure()
+	err := retryContext(ctx, m, "delete discount code", d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := client.DiscountCodes().WithId(d.Id()).Delete().Version(version).DataErasure(dataErasure).Execute(ctx)
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
 
 	if err != nil {
 		log.Printf("[ERROR] Error during deleting discount code resource %s", err)
 		return nil
 	}
+
+	if warning := discountCodeDeleteWarning(d, time.Now()); warning != nil {
+		return diag.Diagnostics{*warning}
+	}
 	return nil
 }
 
-func unmarshallDiscountCodeGroups(d *schema.ResourceData) []string {
+// checkDiscountCodeNotRedeemed returns an error when an order used the
+// discount code. The discount code doesn't have a redemption count, so the
+// orders referencing it are counted instead.
+func checkDiscountCodeNotRedeemed(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, d *schema.ResourceData) diag.Diagnostics {
+	log.Printf("[DEBUG] Counting the orders which used discount code %s", d.Id())
+	result, err := client.Orders().Get().
+		Where([]string{fmt.Sprintf("discountCodes(discountCode(id = %s))", quotePredicateString(d.Id()))}).
+		Limit(1).
+		WithTotal(true).
+		Execute(ctx)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	total := result.Count
+	if result.Total != nil {
+		total = *result.Total
+	}
+	if total == 0 {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Discount code %s was redeemed and is not deleted", d.Get("code")),
+		Detail: fmt.Sprintf(
+			"%d order(s) used the discount code and refuse_delete_if_redeemed is set. Set disable_on_destroy to "+
+				"deactivate the code instead, which keeps its redemption history, or unset "+
+				"refuse_delete_if_redeemed to delete it anyway.", total),
+		AttributePath: cty.Path{cty.GetAttrStep{Name: "refuse_delete_if_redeemed"}},
+	}}
+}
+
+// resourceDiscountCodeDisable deactivates the discount code instead of
+// deleting it. The resource is removed from the state by the caller.
+func resourceDiscountCodeDisable(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	input := platform.DiscountCodeUpdate{
+		Version: d.Get("version").(int),
+		Actions: []platform.DiscountCodeUpdateAction{
+			&platform.DiscountCodeChangeIsActiveAction{IsActive: false},
+		},
+	}
+
+	log.Printf("[DEBUG] Deactivating discount code %s instead of deleting it", d.Id())
+	err := retryContext(ctx, m, "deactivate discount code", d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := client.DiscountCodes().WithId(d.Id()).Post(input).Execute(ctx)
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil
+		}
+		return diagnosticsFromError(err)
+	}
+	return nil
+}
+
+// discountCodeDeleteWarning returns a warning when a deleted discount code was
+// active and within its validity period, so it might have been deleted in the
+// middle of a campaign. Codes without a validity period are not reported.
+func discountCodeDeleteWarning(d *schema.ResourceData, now time.Time) *diag.Diagnostic {
+	if !d.Get("is_active").(bool) {
+		return nil
+	}
+	validFrom, err := unmarshallOptionalTime(d.Get("valid_from").(string))
+	if err != nil {
+		return nil
+	}
+	validUntil, err := unmarshallOptionalTime(d.Get("valid_until").(string))
+	if err != nil {
+		return nil
+	}
+	if validFrom == nil && validUntil == nil {
+		return nil
+	}
+	if (validFrom != nil && now.Before(*validFrom)) || (validUntil != nil && now.After(*validUntil)) {
+		return nil
+	}
+
+	return &diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Deleted discount code %s was still valid", d.Get("code")),
+		Detail: fmt.Sprintf("The discount code %s was active and within its validity period (%s - %s). "+
+			"Carts using the code no longer receive the discount.",
+			d.Get("code"), d.Get("valid_from"), d.Get("valid_until")),
+	}
+}
+
+// resourceDiscountCodeImportState imports a discount code by its ID or code.
+// When the value is both the ID of a discount code and the code of another
+// one an error is returned, since it is not clear which one is meant. The
+// `id=` and `code=` prefixes select how the value is used.
+func resourceDiscountCodeImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := getClient(m)
+	value := d.Id()
+
+	if strings.HasPrefix(value, "id=") {
+		d.SetId(strings.TrimPrefix(value, "id="))
+		return []*schema.ResourceData{d}, nil
+	}
+
+	var byID *platform.DiscountCode
+	if strings.HasPrefix(value, "code=") {
+		value = strings.TrimPrefix(value, "code=")
+	} else {
+		discountCode, err := client.DiscountCodes().WithId(value).Get().Execute(ctx)
+		if err != nil && !isNotFoundError(err) {
+			return nil, err
+		}
+		if err == nil {
+			byID = discountCode
+		}
+	}
+
+	result, err := client.DiscountCodes().Get().
+		Where([]string{fmt.Sprintf("code = %s", quotePredicateString(value))}).
+		Limit(1).
+		Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var byCode *platform.DiscountCode
+	if len(result.Results) > 0 {
+		byCode = &result.Results[0]
+	}
+
+	id, err := resolveDiscountCodeImport(value, byID, byCode)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(id)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resolveDiscountCodeImport(value string, byID, byCode *platform.DiscountCode) (string, error) {
+	switch {
+	case byID != nil && byCode != nil && byID.ID != byCode.ID:
+		return "", fmt.Errorf(
+			"%q is ambiguous: it is the ID of the discount code with code %q and the code of the discount "+
+				"code with ID %s. Import with id=%s or code=%s instead", value, byID.Code, byCode.ID, value, value)
+	case byID != nil:
+		return byID.ID, nil
+	case byCode != nil:
+		return byCode.ID, nil
+	default:
+		return "", fmt.Errorf("no discount code found with ID or code %q", value)
+	}
+}
+
+// validateMaxApplicationsPerCustomer rejects values below 1. Omitting the
+// attribute allows unlimited applications, while 0 would make the code
+// unusable for every customer.
+func validateMaxApplicationsPerCustomer(val interface{}, path cty.Path) diag.Diagnostics {
+	value, ok := val.(int)
+	if !ok || value >= 1 {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       fmt.Sprintf("max_applications_per_customer must be at least 1, got: %d", value),
+		Detail:        "Omit max_applications_per_customer to allow unlimited applications per customer.",
+		AttributePath: path,
+	}}
+}
+
+// unmarshallMaxApplicationsPerCustomer returns nil when the attribute is not
+// set, so the number of applications per customer is not limited
+func unmarshallMaxApplicationsPerCustomer(d resourceChange) *int {
+	value := d.Get("max_applications_per_customer").(int)
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
+
+// validateDiscountCodeUnique checks that the code is not used by another
+// discount code, either by another resource in the configuration or by a
+// discount code in the project which is not managed by terraform. The plan
+// doesn't fail when the existing discount code is:
+//   - the discount code of the resource, also when the resource is replaced
+//   - deactivated, for example by disable_on_destroy
+//   - created by the API client of the provider, so it is most likely managed
+//     by a resource which is removed in the same apply, like when a code is
+//     moved to another resource
+//
+// A CustomizeDiff only sees a single resource, so duplicates in the
+// configuration are detected with the codes planned by this provider.
+func validateDiscountCodeUnique(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("code") {
+		return nil
+	}
+	code := d.Get("code").(string)
+
+	// Without the configuration, which Terraform always sends when planning,
+	// the resources can't be told apart
+	var planned plannedDiscountCode
+	if config := d.GetRawConfig(); !config.IsNull() {
+		var conflict bool
+		planned, conflict = getProviderMeta(m).planDiscountCode(code, d.Id(), config)
+		if conflict && planned.id != "" {
+			return fmt.Errorf("code %s is also used by discount code %s in the configuration", code, planned.id)
+		}
+		if conflict {
+			return fmt.Errorf("code %s is also used by another discount code in the configuration", code)
+		}
+	}
+
+	if !d.HasChange("code") {
+		return nil
+	}
+	if d.Id() == "" && (d.Get("adopt_existing").(bool) || d.Get("import_if_exists").(bool)) {
+		return nil
+	}
+
+	result, err := getClient(m).DiscountCodes().Get().
+		Where([]string{fmt.Sprintf("code = %s", quotePredicateString(code))}).
+		Limit(1).
+		Execute(ctx)
+	if err != nil {
+		return err
+	}
+	clientID := getProviderMeta(m).clientID
+	for _, existing := range result.Results {
+		switch {
+		case existing.ID == d.Id() || existing.ID == planned.id:
+			continue
+		case !existing.IsActive:
+			log.Printf("[DEBUG] Code %s is used by the deactivated discount code %s", code, existing.ID)
+			continue
+		case clientID != "" && existing.CreatedBy != nil && existing.CreatedBy.ClientId != nil &&
+			*existing.CreatedBy.ClientId == clientID:
+			log.Printf("[DEBUG] Code %s is used by discount code %s, which was created by the provider", code, existing.ID)
+			continue
+		}
+		return fmt.Errorf(
+			"code %s is already used by discount code %s, set adopt_existing or import_if_exists to take "+
+				"it over", code, existing.ID)
+	}
+	return nil
+}
+
+// plannedDiscountCode is the code of a planned discount code resource. The ID
+// is empty for a new discount code, the configuration tells the resources
+// apart.
+type plannedDiscountCode struct {
+	id     string
+	config cty.Value
+}
+
+// planDiscountCode records that the discount code resource with the ID and
+// configuration uses the code. It returns the resource which planned the code
+// before. conflict is set when it is another resource, the resource itself
+// may be planned more than once, for example when it is replaced.
+func (p *providerMeta) planDiscountCode(code, id string, config cty.Value) (planned plannedDiscountCode, conflict bool) {
+	p.plannedCodesMu.Lock()
+	defer p.plannedCodesMu.Unlock()
+
+	if p.plannedCodes == nil {
+		p.plannedCodes = map[string]plannedDiscountCode{}
+	}
+	planned, ok := p.plannedCodes[code]
+	if ok && !planned.config.RawEquals(config) && (id == "" || planned.id == "" || id != planned.id) {
+		return planned, true
+	}
+	if !ok || planned.id == "" {
+		planned = plannedDiscountCode{id: id, config: config}
+	}
+	p.plannedCodes[code] = planned
+	return planned, false
+}
+
+// validateDiscountCodeStores checks if the stores referenced in the stores
+// attribute can be fetched when planning. A store which doesn't exist yet may
+// be created in the same apply, so it is skipped here.
+func validateDiscountCodeStores(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("stores") || !d.NewValueKnown("stores") {
+		return nil
+	}
+
+	client := getClient(m)
+	for _, key := range expandStringArray(d.Get("stores").(*schema.Set).List()) {
+		_, err := client.Stores().WithKey(key).Get().Execute(ctx)
+		if err != nil {
+			if isNotFoundError(err) {
+				log.Printf("[DEBUG] Store %s referenced in stores does not exist yet, skipping its validation", key)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDiscountCodeLocales checks that the name contains the locales of the
+// required_name_locales setting of the provider. The description only has to
+// contain them when it is set.
+func validateDiscountCodeLocales(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta, ok := m.(*providerMeta)
+	if !ok || len(meta.requiredNameLocales) == 0 {
+		return nil
+	}
+
+	for _, field := range []string{"name", "description"} {
+		if !d.NewValueKnown(field) {
+			continue
+		}
+		value, _ := d.Get(field).(map[string]interface{})
+		if field == "description" && len(value) == 0 {
+			continue
+		}
+		if missing := missingLocales(value, meta.requiredNameLocales); len(missing) > 0 {
+			return fmt.Errorf(
+				"%s does not contain the locale(s) %s, which are required by the required_name_locales "+
+					"setting of the provider", field, strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
+
+// discountCodeExpiredWarning returns a warning when the valid_until of a new
+// discount code is before now, so the code can't be used at all
+func discountCodeExpiredWarning(code string, validUntil *time.Time, now time.Time) *diag.Diagnostic {
+	if validUntil == nil || !validUntil.Before(now) {
+		return nil
+	}
+	return &diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Discount code %s is created with a valid_until in the past", code),
+		Detail: fmt.Sprintf("The valid_until (%s) is in the past, so the discount code can't be applied "+
+			"to any cart. Check the date for a typo if the code is not backdated on purpose.",
+			validUntil.Format(time.RFC3339)),
+	}
+}
+
+// missingLocales returns the locales which have no value in the localized
+// string, in the order of locales
+func missingLocales(value map[string]interface{}, locales []string) []string {
+	var missing []string
+	for _, locale := range locales {
+		if text, _ := value[locale].(string); text == "" {
+			missing = append(missing, locale)
+		}
+	}
+	return missing
+}
+
+// checkDiscountCodeCartDiscounts returns a warning for every referenced cart
+// discount which doesn't require a discount code. These cart discounts are
+// applied to every matching cart, so referencing them in a code is redundant.
+func checkDiscountCodeCartDiscounts(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, refs []platform.CartDiscountResourceIdentifier) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, ref := range refs {
+		var cartDiscount *platform.CartDiscount
+		var err error
+		var reference string
+		switch {
+		case ref.ID != nil:
+			reference = *ref.ID
+			cartDiscount, err = client.CartDiscounts().WithId(*ref.ID).Get().Execute(ctx)
+		case ref.Key != nil:
+			reference = "key=" + *ref.Key
+			cartDiscount, err = client.CartDiscounts().WithKey(*ref.Key).Get().Execute(ctx)
+		default:
+			continue
+		}
+		if err != nil {
+			log.Printf("[WARN] Unable to check cart discount %s: %s", reference, err)
+			continue
+		}
+		if warning := cartDiscountRequiresCodeWarning(cartDiscount); warning != nil {
+			diags = append(diags, *warning)
+		}
+	}
+	return diags
+}
+
+func cartDiscountRequiresCodeWarning(cartDiscount *platform.CartDiscount) *diag.Diagnostic {
+	if cartDiscount.RequiresDiscountCode {
+		return nil
+	}
+	return &diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Cart discount %s does not require a discount code", cartDiscount.ID),
+		Detail: "The cart discount is applied to every matching cart, also without this discount code. " +
+			"Set requires_discount_code to true on the cart discount if it should only apply with a code.",
+	}
+}
+
+// unmarshallDiscountCodePredicate returns the cart predicate to send to
+// commercetools. When stores are set a clause limiting the discount code to
+// these stores is added to the predicate.
+func unmarshallDiscountCodePredicate(d resourceChange) string {
+	predicate := d.Get("predicate").(string)
+	stores := expandStringArray(d.Get("stores").(*schema.Set).List())
+	return buildDiscountCodePredicate(predicate, stores)
+}
+
+// marshallDiscountCodePredicate returns the predicate and the stores for the
+// terraform state. commercetools only has the cart predicate, so when it ends
+// with a clause generated by buildDiscountCodePredicate the clause is returned
+// as the stores. This also fills the stores of an imported discount code. A
+// predicate which is configured with such a clause without stores is returned
+// as is.
+func marshallDiscountCodePredicate(value *string, d *schema.ResourceData) (string, []string) {
+	if value == nil {
+		return "", nil
+	}
+
+	stores := expandStringArray(d.Get("stores").(*schema.Set).List())
+	if len(stores) == 0 && *value == d.Get("predicate").(string) {
+		return *value, nil
+	}
+	if predicate, stores, ok := parseDiscountCodePredicate(*value); ok {
+		return predicate, stores
+	}
+	return *value, nil
+}
+
+var (
+	discountCodeStoreClause = regexp.MustCompile(`^(?:\((.*)\) and )?store\.key in \((.*)\)$`)
+	predicateStringLiteral  = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+	predicateStringEscapes  = strings.NewReplacer(`\\`, `\`, `\"`, `"`)
+)
+
+// parseDiscountCodePredicate splits a predicate generated by
+// buildDiscountCodePredicate into the configured predicate and the stores
+func parseDiscountCodePredicate(value string) (string, []string, bool) {
+	match := discountCodeStoreClause.FindStringSubmatch(value)
+	if match == nil {
+		return "", nil, false
+	}
+	var stores []string
+	for _, literal := range predicateStringLiteral.FindAllStringSubmatch(match[2], -1) {
+		stores = append(stores, predicateStringEscapes.Replace(literal[1]))
+	}
+	if len(stores) == 0 || !balancedParentheses(match[1]) || buildDiscountCodePredicate(match[1], stores) != value {
+		return "", nil, false
+	}
+	return match[1], stores, true
+}
+
+// balancedParentheses returns whether the parentheses outside the string
+// literals of the predicate are balanced, so `(a) and (b)` is not mistaken
+// for the predicate `a) and (b` wrapped in parentheses
+func balancedParentheses(predicate string) bool {
+	depth := 0
+	quoted := false
+	for i := 0; i < len(predicate); i++ {
+		switch c := predicate[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == '(':
+			depth++
+		case !quoted && c == ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0 && !quoted
+}
+
+func buildDiscountCodePredicate(predicate string, stores []string) string {
+	if len(stores) == 0 {
+		return predicate
+	}
+
+	keys := make([]string, len(stores))
+	for i, key := range stores {
+		keys[i] = quotePredicateString(key)
+	}
+	sort.Strings(keys)
+
+	clause := fmt.Sprintf("store.key in (%s)", strings.Join(keys, ", "))
+	if predicate == "" {
+		return clause
+	}
+	return fmt.Sprintf("(%s) and %s", predicate, clause)
+}
+
+func unmarshallDiscountCodeGroups(d resourceChange) []string {
 	return expandStringArray(d.Get("groups").([]interface{}))
 }
 
-func unmarshallDiscountCodeCartDiscounts(d *schema.ResourceData) []platform.CartDiscountResourceIdentifier {
+// marshallDiscountCodeGroups returns the groups in the order of the current
+// state when commercetools returns the same groups in another order, so the
+// order of the groups doesn't cause a diff
+func marshallDiscountCodeGroups(groups []string, d *schema.ResourceData) []string {
+	current := unmarshallDiscountCodeGroups(d)
+	if len(current) != len(groups) {
+		return groups
+	}
+
+	counts := make(map[string]int, len(groups))
+	for _, group := range groups {
+		counts[group]++
+	}
+	for _, group := range current {
+		if counts[group] == 0 {
+			return groups
+		}
+		counts[group]--
+	}
+	return current
+}
+
+func unmarshallDiscountCodeCartDiscounts(d resourceChange) []platform.CartDiscountResourceIdentifier {
 	discounts := d.Get("cart_discounts").([]interface{})
 
 	cartDiscounts := make([]platform.CartDiscountResourceIdentifier, len(discounts))
 	for i := range discounts {
-		id := discounts[i].(string)
-		cartDiscounts[i] = platform.CartDiscountResourceIdentifier{ID: &id}
+		id, key := resolveResourceIdentifier(discounts[i].(string))
+		cartDiscounts[i] = platform.CartDiscountResourceIdentifier{ID: id, Key: key}
 	}
 	return cartDiscounts
 }
 
-func marshallDiscountCodeCartDiscounts(values []platform.CartDiscountReference) []string {
+// marshallDiscountCodeCartDiscounts returns the IDs of the cart discounts.
+// When the current value references a cart discount by its key the key is
+// kept, which requires the references to be expanded.
+func marshallDiscountCodeCartDiscounts(values []platform.CartDiscountReference, current []string) []string {
+	configured := make(map[string]bool, len(current))
+	for _, value := range current {
+		configured[value] = true
+	}
+
 	result := make([]string, len(values))
-	for i := range values {
-		result[i] = string(values[i].ID)
+	for i, value := range values {
+		result[i] = value.ID
+		if value.Obj != nil && value.Obj.Key != nil && configured[*value.Obj.Key] && !configured[value.ID] {
+			result[i] = *value.Obj.Key
+		}
 	}
 	return result
 }
@@ -0,0 +1,462 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+// maxDiscountCodeBatchSize is the maximum number of codes of a batch, larger
+// batches take too long to apply and make the state very large
+const maxDiscountCodeBatchSize = 10000
+
+// discountCodeBatchRangeKeys are the attributes which determine the codes of
+// a batch
+var discountCodeBatchRangeKeys = []string{"code_prefix", "code_suffix", "index_digits", "first_index", "last_index"}
+
+func resourceDiscountCodeBatch() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"code_prefix": {
+			Description: "The start of every code, for example `PROMO-`",
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+		},
+		"code_suffix": {
+			Description: "The end of every code",
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+		},
+		"index_digits": {
+			Description: "The minimum number of digits of the index in the codes, shorter indexes are padded " +
+				"with zeros. With the default of 3 the codes are `PROMO-001`, `PROMO-002` and so on",
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      3,
+			ForceNew:     true,
+			ValidateFunc: validation.IntBetween(1, 10),
+		},
+		"first_index": {
+			Description:  "The index of the first code",
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      1,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"last_index": {
+			Description: fmt.Sprintf(
+				"The index of the last code. A batch has at most %d codes", maxDiscountCodeBatchSize),
+			Type:         schema.TypeInt,
+			Required:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"max_concurrency": {
+			Description: "The number of discount codes which are created, updated or deleted at the same time. " +
+				"The `max_parallel_requests` setting of the provider still limits the requests of all resources",
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      5,
+			ValidateFunc: validation.IntBetween(1, 20),
+		},
+		"codes": {
+			Description: "The IDs of the discount codes of the batch by their code. Codes which don't exist, " +
+				"for example because creating them failed, are missing",
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+	}
+
+	// All codes of the batch share the attributes of a discount code which
+	// can be updated
+	codeSchema := resourceDiscountCode().Schema
+	for _, key := range discountCodeUpdateKeys {
+		s[key] = codeSchema[key]
+	}
+
+	return &schema.Resource{
+		Description: "Manages a batch of discount codes with the same settings and sequential codes, like " +
+			"`PROMO-001` up to `PROMO-100`, for example for codes which are handed out one per customer.\n\n" +
+			"Changing `first_index` or `last_index` creates the codes which are added to the range and deletes " +
+			"the codes which are removed from it, the other codes are kept. Changes of the other attributes " +
+			"are applied to every code.\n\n" +
+			"The codes are created in parallel. When some of them can't be created the others are kept and a " +
+			"warning is returned for every failed code. The next plan shows the missing codes, so applying again " +
+			"only creates these. When some codes can't be updated the batch keeps its previous state and no " +
+			"codes are created, so the next plan shows the change again. Only the existence of the codes is " +
+			"read, changes made to the codes outside of terraform are not detected.\n\n" +
+			"See also the [Discount Code Api Documentation](https://docs.commercetools.com/api/projects/discountCodes)",
+		CreateContext: resourceDiscountCodeBatchCreate,
+		ReadContext:   resourceDiscountCodeBatchRead,
+		UpdateContext: resourceDiscountCodeBatchUpdate,
+		DeleteContext: resourceDiscountCodeBatchDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		CustomizeDiff: customdiff.All(
+			validateDiscountCodeLocales,
+			planDiscountCodeBatchCodes,
+		),
+		Schema: s,
+	}
+}
+
+// discountCodeBatchCodes returns the codes of the batch in index order
+func discountCodeBatchCodes(d resourceChange) []string {
+	prefix := d.Get("code_prefix").(string)
+	suffix := d.Get("code_suffix").(string)
+	digits := d.Get("index_digits").(int)
+	first := d.Get("first_index").(int)
+	last := d.Get("last_index").(int)
+
+	codes := make([]string, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		codes = append(codes, fmt.Sprintf("%s%0*d%s", prefix, digits, i, suffix))
+	}
+	return codes
+}
+
+// planDiscountCodeBatchCodes validates the range of the batch and marks the
+// codes as changed when codes have to be created or deleted, which is also the
+// case when codes are missing because creating them failed
+func planDiscountCodeBatchCodes(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for _, key := range discountCodeBatchRangeKeys {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("codes")
+		}
+	}
+
+	first, last := d.Get("first_index").(int), d.Get("last_index").(int)
+	if last < first {
+		return fmt.Errorf("last_index %d is lower than first_index %d", last, first)
+	}
+	if size := last - first + 1; size > maxDiscountCodeBatchSize {
+		return fmt.Errorf("the batch has %d codes, at most %d are supported", size, maxDiscountCodeBatchSize)
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+	current := d.Get("codes").(map[string]interface{})
+	desired := discountCodeBatchCodes(d)
+	if len(current) != len(desired) {
+		return d.SetNewComputed("codes")
+	}
+	for _, code := range desired {
+		if _, ok := current[code]; !ok {
+			return d.SetNewComputed("codes")
+		}
+	}
+	return nil
+}
+
+func resourceDiscountCodeBatchCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(resource.UniqueId())
+
+	codes := discountCodeBatchCodes(d)
+	log.Printf("[DEBUG] Creating %d discount codes from %s to %s", len(codes), codes[0], codes[len(codes)-1])
+	created, diags := createDiscountCodeBatchCodes(ctx, d, m, codes, d.Timeout(schema.TimeoutCreate))
+	if len(created) == 0 && diags.HasError() {
+		d.SetId("")
+		return diags
+	}
+
+	// An error would taint the batch, which deletes all codes on the next
+	// apply. The failed codes are missing instead, so they are created by the
+	// next apply.
+	for i := range diags {
+		diags[i].Severity = diag.Warning
+	}
+	d.Set("codes", created)
+	return append(diags, resourceDiscountCodeBatchRead(ctx, d, m)...)
+}
+
+func resourceDiscountCodeBatchRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	current := d.Get("codes").(map[string]interface{})
+	ids := make([]string, 0, len(current))
+	for _, id := range current {
+		ids = append(ids, id.(string))
+	}
+	sort.Strings(ids)
+
+	log.Printf("[DEBUG] Reading %d discount codes of batch %s from commercetools", len(ids), d.Id())
+	discountCodes, err := queryDiscountCodesByID(ctx, getClient(m), ids)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	codes := make(map[string]string, len(discountCodes))
+	for _, discountCode := range discountCodes {
+		codes[discountCode.Code] = discountCode.ID
+	}
+	d.Set("codes", codes)
+	return nil
+}
+
+func resourceDiscountCodeBatchUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
+	// The planned codes are unknown when codes are created or deleted, the
+	// state has the codes which exist
+	old, _ := d.GetChange("codes")
+	codes := map[string]string{}
+	for code, id := range old.(map[string]interface{}) {
+		codes[code] = id.(string)
+	}
+
+	desired := map[string]bool{}
+	var missing []string
+	for _, code := range discountCodeBatchCodes(d) {
+		desired[code] = true
+		if _, ok := codes[code]; !ok {
+			missing = append(missing, code)
+		}
+	}
+	removed := map[string]bool{}
+	kept := map[string]bool{}
+	for code, id := range codes {
+		if desired[code] {
+			kept[id] = true
+		} else {
+			removed[id] = true
+		}
+	}
+
+	var diags diag.Diagnostics
+	if len(removed) > 0 {
+		log.Printf("[DEBUG] Deleting %d discount codes of batch %s", len(removed), d.Id())
+		dataErasure := getProviderMeta(m).dataErasure()
+		failures := forEachConcurrently(ctx, sortedKeys(removed), d.Get("max_concurrency").(int), func(id string) error {
+			return deleteDiscountCodeByID(ctx, m, id, dataErasure, timeout)
+		})
+		for code, id := range codes {
+			if removed[id] && failures[id] == nil {
+				delete(codes, code)
+			}
+		}
+		diags = append(diags, discountCodeBatchDiagnostics("delete", failures)...)
+	}
+
+	if d.HasChanges(discountCodeUpdateKeys...) && len(kept) > 0 {
+		actions, err := buildDiscountCodeUpdateActions(d)
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+		if len(actions) > 0 {
+			log.Printf(
+				"[DEBUG] Updating %d discount codes of batch %s with the following actions:\n%s",
+				len(kept), d.Id(), stringFormatActions(actions))
+			failures := forEachConcurrently(ctx, sortedKeys(kept), d.Get("max_concurrency").(int), func(id string) error {
+				return updateDiscountCodeByID(ctx, m, id, actions, timeout)
+			})
+			if len(failures) > 0 {
+				// The codes share the attributes of the batch, so the planned
+				// values would mark the failed codes as updated. The batch
+				// keeps its previous state instead, which would also drop the
+				// IDs of created codes, so no codes are created.
+				d.Partial(true)
+				return append(diags, discountCodeBatchDiagnostics("update", failures)...)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		log.Printf("[DEBUG] Creating %d discount codes of batch %s", len(missing), d.Id())
+		created, createDiags := createDiscountCodeBatchCodes(ctx, d, m, missing, timeout)
+		for code, id := range created {
+			codes[code] = id
+		}
+		diags = append(diags, createDiags...)
+	}
+
+	d.Set("codes", codes)
+	return append(diags, resourceDiscountCodeBatchRead(ctx, d, m)...)
+}
+
+func resourceDiscountCodeBatchDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	codes := d.Get("codes").(map[string]interface{})
+	ids := make([]string, 0, len(codes))
+	for _, id := range codes {
+		ids = append(ids, id.(string))
+	}
+	sort.Strings(ids)
+
+	log.Printf("[DEBUG] Deleting %d discount codes of batch %s", len(ids), d.Id())
+	dataErasure := getProviderMeta(m).dataErasure()
+	timeout := d.Timeout(schema.TimeoutDelete)
+	failures := forEachConcurrently(ctx, ids, d.Get("max_concurrency").(int), func(id string) error {
+		return deleteDiscountCodeByID(ctx, m, id, dataErasure, timeout)
+	})
+	if len(failures) > 0 {
+		remaining := map[string]string{}
+		for code, id := range codes {
+			if failures[id.(string)] != nil {
+				remaining[code] = id.(string)
+			}
+		}
+		d.Set("codes", remaining)
+	}
+	return discountCodeBatchDiagnostics("delete", failures)
+}
+
+// createDiscountCodeBatchCodes creates a discount code for every code with the
+// attributes of the batch. It returns the IDs of the created codes by code,
+// and an error for every code which could not be created.
+func createDiscountCodeBatchCodes(ctx context.Context, d *schema.ResourceData, m interface{}, codes []string, timeout time.Duration) (map[string]string, diag.Diagnostics) {
+	// The draft is built once, the attributes of d are not read concurrently
+	draft, err := unmarshallDiscountCodeDraft(d, "")
+	if err != nil {
+		return nil, diagnosticsFromError(err)
+	}
+
+	client := getClient(m)
+	var mu sync.Mutex
+	created := map[string]string{}
+	failures := forEachConcurrently(ctx, codes, d.Get("max_concurrency").(int), func(code string) error {
+		codeDraft := draft
+		codeDraft.Code = code
+		start := time.Now()
+		attempts := 0
+		err := retryContext(ctx, m, fmt.Sprintf("create discount code %s", code), timeout, func() *resource.RetryError {
+			attempts++
+			discountCode, err := client.DiscountCodes().Post(codeDraft).Execute(ctx)
+			if err != nil {
+				return handleCommercetoolsError(err)
+			}
+			mu.Lock()
+			created[code] = discountCode.ID
+			mu.Unlock()
+			return nil
+		})
+		if err == nil {
+			return nil
+		}
+
+		// A request without a response may have created the discount code,
+		// like in resourceDiscountCodeCreate
+		_, duplicate := duplicateFieldError(err, "code")
+		if isRetryTimeout(err) || (duplicate && attempts > 1) {
+			recoverCtx, cancel := context.WithTimeout(context.Background(), discountCodeRecoverTimeout)
+			defer cancel()
+			if existing := findCreatedDiscountCode(recoverCtx, client, code, start); existing != nil {
+				log.Printf("[INFO] Discount code %s was created with id %s although the create failed", code, existing.ID)
+				mu.Lock()
+				created[code] = existing.ID
+				mu.Unlock()
+				return nil
+			}
+		}
+		return err
+	})
+	return created, discountCodeBatchDiagnostics("create", failures)
+}
+
+// updateDiscountCodeByID applies the actions to the current version of the
+// discount code
+func updateDiscountCodeByID(ctx context.Context, m interface{}, id string, actions []platform.DiscountCodeUpdateAction, timeout time.Duration) error {
+	client := getClient(m)
+	return retryContext(ctx, m, fmt.Sprintf("update discount code %s", id), timeout, func() *resource.RetryError {
+		discountCode, err := client.DiscountCodes().WithId(id).Get().Execute(ctx)
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+
+		input := platform.DiscountCodeUpdate{
+			Version: discountCode.Version,
+			Actions: actions,
+		}
+		_, err = client.DiscountCodes().WithId(id).Post(input).Execute(ctx)
+		if err != nil {
+			if isConflictError(err) && retryOnConflict(m) {
+				return resource.RetryableError(err)
+			}
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+}
+
+// deleteDiscountCodeByID deletes the current version of the discount code,
+// discount codes which no longer exist are ignored
+func deleteDiscountCodeByID(ctx context.Context, m interface{}, id string, dataErasure bool, timeout time.Duration) error {
+	client := getClient(m)
+	return retryContext(ctx, m, fmt.Sprintf("delete discount code %s", id), timeout, func() *resource.RetryError {
+		discountCode, err := client.DiscountCodes().WithId(id).Get().Execute(ctx)
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil
+			}
+			return handleCommercetoolsError(err)
+		}
+
+		_, err = client.DiscountCodes().WithId(id).Delete().Version(discountCode.Version).DataErasure(dataErasure).Execute(ctx)
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil
+			}
+			if isConflictError(err) {
+				return resource.RetryableError(err)
+			}
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+}
+
+// queryDiscountCodesByID returns the discount codes with the IDs which exist.
+// The IDs are queried in chunks to keep the predicates short.
+func queryDiscountCodesByID(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, ids []string) ([]platform.DiscountCode, error) {
+	const chunkSize = 100
+
+	var result []platform.DiscountCode
+	for start := 0; start < len(ids); start += chunkSize {
+		end := start + chunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		discountCodes, err := queryDiscountCodes(ctx, client, []string{
+			fmt.Sprintf("id in (%s)", quotePredicateStrings(ids[start:end])),
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, discountCodes...)
+	}
+	return result, nil
+}
+
+// discountCodeBatchDiagnostics returns an error for every discount code of
+// the batch for which the operation failed, sorted by code or ID
+func discountCodeBatchDiagnostics(operation string, failures map[string]error) diag.Diagnostics {
+	keys := make([]string, 0, len(failures))
+	for key := range failures {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var diags diag.Diagnostics
+	for _, key := range keys {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Could not %s discount code %s", operation, key),
+			Detail: fmt.Sprintf(
+				"%s\n\nThe other discount codes are %sd. Apply again to retry the %s of this code.",
+				strings.TrimSpace(failures[key].Error()), operation, operation),
+		})
+	}
+	return diags
+}
@@ -0,0 +1,344 @@
+package commercetools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDiscountCodes is an in memory discount code API. Creating the codes in
+// failCodes fails, the codes in lostCodes are created but the response of the
+// first create is lost and updating the IDs in failUpdates fails. The
+// discount codes which are created return the is_active and valid_until of
+// their draft.
+type fakeDiscountCodes struct {
+	mu          sync.Mutex
+	codes       map[string]string
+	drafts      map[string]platform.DiscountCodeDraft
+	failCodes   map[string]bool
+	lostCodes   map[string]bool
+	failUpdates map[string]bool
+	posts       int
+	updated     []string
+	deleted     []string
+}
+
+func (f *fakeDiscountCodes) body(id string, version int) string {
+	result := map[string]interface{}{
+		"id": id, "version": version, "code": f.codes[id], "createdAt": time.Now().UTC().Format(time.RFC3339),
+	}
+	if draft, ok := f.drafts[id]; ok {
+		if draft.IsActive != nil {
+			result["isActive"] = *draft.IsActive
+		}
+		if draft.ValidUntil != nil {
+			result["validUntil"] = draft.ValidUntil.Format(time.RFC3339)
+		}
+	}
+	data, _ := json.Marshal(result)
+	return string(data)
+}
+
+var fakeDiscountCodeIDs = regexp.MustCompile(`"([^"]+)"`)
+
+func (f *fakeDiscountCodes) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	id := strings.TrimPrefix(r.URL.Path, "/my-project/discount-codes/")
+	switch {
+	case r.URL.Path == "/my-project/discount-codes" && r.Method == http.MethodPost:
+		var draft platform.DiscountCodeDraft
+		json.NewDecoder(r.Body).Decode(&draft)
+		f.posts++
+		if f.failCodes[draft.Code] {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode": 400, "message": "Invalid code"}`))
+			return
+		}
+		if f.codes["id-"+draft.Code] != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"statusCode": 400, "message": "duplicate", "errors": [{"code": "DuplicateField",
+				"message": "duplicate", "field": "code", "duplicateValue": %q}]}`, draft.Code)
+			return
+		}
+		if f.drafts == nil {
+			f.drafts = map[string]platform.DiscountCodeDraft{}
+		}
+		f.codes["id-"+draft.Code] = draft.Code
+		f.drafts["id-"+draft.Code] = draft
+		if f.lostCodes[draft.Code] {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"statusCode": 502, "message": "Bad gateway"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(f.body("id-"+draft.Code, 1)))
+	case r.URL.Path == "/my-project/discount-codes":
+		var results []string
+		where := r.URL.Query().Get("where")
+		for _, match := range fakeDiscountCodeIDs.FindAllStringSubmatch(where, -1) {
+			if strings.HasPrefix(where, "code = ") {
+				match[1] = "id-" + match[1]
+			}
+			if _, ok := f.codes[match[1]]; ok {
+				results = append(results, f.body(match[1], 1))
+			}
+		}
+		fmt.Fprintf(w, `{"count": %d, "results": [%s]}`, len(results), strings.Join(results, ","))
+	case f.codes[id] == "":
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"statusCode": 404, "message": "Not found"}`))
+	case r.Method == http.MethodPost:
+		if f.failUpdates[id] {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode": 400, "message": "Invalid update"}`))
+			return
+		}
+		f.updated = append(f.updated, id)
+		w.Write([]byte(f.body(id, 2)))
+	case r.Method == http.MethodDelete:
+		f.deleted = append(f.deleted, id)
+		w.Write([]byte(f.body(id, 1)))
+		delete(f.codes, id)
+	default:
+		w.Write([]byte(f.body(id, 1)))
+	}
+}
+
+func newFakeDiscountCodesMeta(t *testing.T, fake *fakeDiscountCodes) *providerMeta {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	return &providerMeta{client: client.WithProjectKey("my-project"), environment: "test"}
+}
+
+func TestDiscountCodeBatchCodes(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDiscountCodeBatch().Schema, map[string]interface{}{
+		"code_prefix": "PROMO-",
+		"first_index": 99,
+		"last_index":  101,
+	})
+	assert.Equal(t, []string{"PROMO-099", "PROMO-100", "PROMO-101"}, discountCodeBatchCodes(d))
+
+	d = schema.TestResourceDataRaw(t, resourceDiscountCodeBatch().Schema, map[string]interface{}{
+		"code_prefix":  "X",
+		"code_suffix":  "-2021",
+		"index_digits": 1,
+		"first_index":  9,
+		"last_index":   10,
+	})
+	assert.Equal(t, []string{"X9-2021", "X10-2021"}, discountCodeBatchCodes(d))
+}
+
+func TestPlanDiscountCodeBatchCodes(t *testing.T) {
+	config := map[string]interface{}{
+		"code_prefix":    "PROMO-",
+		"first_index":    1,
+		"last_index":     2,
+		"cart_discounts": []interface{}{"cart-discount-1"},
+	}
+	state := &terraform.InstanceState{
+		ID: "batch-1",
+		Attributes: map[string]string{
+			"id":                            "batch-1",
+			"code_prefix":                   "PROMO-",
+			"index_digits":                  "3",
+			"first_index":                   "1",
+			"last_index":                    "2",
+			"max_concurrency":               "5",
+			"is_active":                     "true",
+			"cart_discounts.#":              "1",
+			"cart_discounts.0":              "cart-discount-1",
+			"codes.%":                       "2",
+			"codes.PROMO-001":               "id-PROMO-001",
+			"codes.PROMO-002":               "id-PROMO-002",
+			"stores.#":                      "0",
+			"groups.#":                      "0",
+			"custom.#":                      "0",
+			"max_applications":              "0",
+			"valid_from":                    "",
+			"valid_until":                   "",
+			"predicate":                     "",
+			"code_suffix":                   "",
+			"name.%":                        "0",
+			"description.%":                 "0",
+			"max_applications_per_customer": "0",
+		},
+	}
+
+	diff, err := resourceDiscountCodeBatch().SimpleDiff(
+		context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+	assert.NoError(t, err)
+	if diff != nil {
+		assert.Empty(t, diff.Attributes)
+	}
+
+	// A code which is missing in the state is created
+	delete(state.Attributes, "codes.PROMO-002")
+	state.Attributes["codes.%"] = "1"
+	diff, err = resourceDiscountCodeBatch().SimpleDiff(
+		context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+	assert.NoError(t, err)
+	if assert.NotNil(t, diff) {
+		assert.True(t, diff.Attributes["codes.%"].NewComputed)
+	}
+
+	config["last_index"] = 0
+	_, err = resourceDiscountCodeBatch().SimpleDiff(
+		context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "last_index 0 is lower than first_index 1")
+	}
+}
+
+func TestResourceDiscountCodeBatchCreatePartialFailure(t *testing.T) {
+	fake := &fakeDiscountCodes{codes: map[string]string{}, failCodes: map[string]bool{"PROMO-002": true}}
+	meta := newFakeDiscountCodesMeta(t, fake)
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCodeBatch().Schema, map[string]interface{}{
+		"code_prefix":    "PROMO-",
+		"last_index":     3,
+		"cart_discounts": []interface{}{"cart-discount-1"},
+	})
+	diags := resourceDiscountCodeBatchCreate(context.Background(), d, meta)
+	assert.False(t, diags.HasError())
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "Could not create discount code PROMO-002", diags[0].Summary)
+		assert.Contains(t, diags[0].Detail, "Apply again to retry the create of this code")
+	}
+	assert.NotEmpty(t, d.Id())
+	assert.Equal(t, map[string]interface{}{
+		"PROMO-001": "id-PROMO-001",
+		"PROMO-003": "id-PROMO-003",
+	}, d.Get("codes"))
+}
+
+func TestResourceDiscountCodeBatchUpdateRange(t *testing.T) {
+	fake := &fakeDiscountCodes{codes: map[string]string{
+		"id-PROMO-001": "PROMO-001",
+		"id-PROMO-003": "PROMO-003",
+	}}
+	meta := newFakeDiscountCodesMeta(t, fake)
+
+	old := map[string]interface{}{
+		"code_prefix":    "PROMO-",
+		"first_index":    1,
+		"last_index":     3,
+		"cart_discounts": []interface{}{"cart-discount-1"},
+		"codes": map[string]interface{}{
+			"PROMO-001": "id-PROMO-001",
+			"PROMO-003": "id-PROMO-003",
+		},
+	}
+	new := map[string]interface{}{
+		"code_prefix":    "PROMO-",
+		"first_index":    2,
+		"last_index":     4,
+		"cart_discounts": []interface{}{"cart-discount-1"},
+		"is_active":      false,
+	}
+	d := testResourceDataChange(t, resourceDiscountCodeBatch().Schema, old, new)
+
+	diags := resourceDiscountCodeBatchUpdate(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, []string{"id-PROMO-001"}, fake.deleted)
+	assert.Equal(t, []string{"id-PROMO-003"}, fake.updated)
+	assert.Equal(t, map[string]interface{}{
+		"PROMO-002": "id-PROMO-002",
+		"PROMO-003": "id-PROMO-003",
+		"PROMO-004": "id-PROMO-004",
+	}, d.Get("codes"))
+}
+
+func TestResourceDiscountCodeBatchCreateLostResponse(t *testing.T) {
+	fake := &fakeDiscountCodes{codes: map[string]string{}, lostCodes: map[string]bool{"PROMO-002": true}}
+	meta := newFakeDiscountCodesMeta(t, fake)
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCodeBatch().Schema, map[string]interface{}{
+		"code_prefix":    "PROMO-",
+		"last_index":     2,
+		"cart_discounts": []interface{}{"cart-discount-1"},
+	})
+	diags := resourceDiscountCodeBatchCreate(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, 3, fake.posts)
+	assert.Equal(t, map[string]interface{}{
+		"PROMO-001": "id-PROMO-001",
+		"PROMO-002": "id-PROMO-002",
+	}, d.Get("codes"))
+}
+
+func TestResourceDiscountCodeBatchUpdateFailure(t *testing.T) {
+	fake := &fakeDiscountCodes{
+		codes:       map[string]string{"id-PROMO-001": "PROMO-001", "id-PROMO-002": "PROMO-002"},
+		failUpdates: map[string]bool{"id-PROMO-002": true},
+	}
+	meta := newFakeDiscountCodesMeta(t, fake)
+
+	old := map[string]interface{}{
+		"code_prefix":    "PROMO-",
+		"last_index":     2,
+		"cart_discounts": []interface{}{"cart-discount-1"},
+		"codes": map[string]interface{}{
+			"PROMO-001": "id-PROMO-001",
+			"PROMO-002": "id-PROMO-002",
+		},
+	}
+	new := map[string]interface{}{
+		"code_prefix":    "PROMO-",
+		"last_index":     3,
+		"cart_discounts": []interface{}{"cart-discount-1"},
+		"is_active":      false,
+	}
+	d := testResourceDataChange(t, resourceDiscountCodeBatch().Schema, old, new)
+
+	diags := resourceDiscountCodeBatchUpdate(context.Background(), d, meta)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "Could not update discount code id-PROMO-002", diags[0].Summary)
+	}
+	assert.Equal(t, []string{"id-PROMO-001"}, fake.updated)
+	assert.Equal(t, 0, fake.posts)
+
+	// The previous state is kept, so the next plan updates the codes again
+	state := d.State()
+	assert.Equal(t, "true", state.Attributes["is_active"])
+	assert.Equal(t, "2", state.Attributes["last_index"])
+	assert.Equal(t, "2", state.Attributes["codes.%"])
+}
+
+func TestResourceDiscountCodeBatchDelete(t *testing.T) {
+	fake := &fakeDiscountCodes{codes: map[string]string{"id-PROMO-001": "PROMO-001"}}
+	meta := newFakeDiscountCodesMeta(t, fake)
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCodeBatch().Schema, map[string]interface{}{
+		"code_prefix":    "PROMO-",
+		"last_index":     2,
+		"cart_discounts": []interface{}{"cart-discount-1"},
+	})
+	d.SetId("batch-1")
+	d.Set("codes", map[string]string{"PROMO-001": "id-PROMO-001", "PROMO-002": "id-PROMO-002"})
+
+	// Codes which were already deleted are ignored
+	diags := resourceDiscountCodeBatchDelete(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, []string{"id-PROMO-001"}, fake.deleted)
+}
@@ -0,0 +1,184 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func resourceDiscountCodeGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		Description: "Adds a discount code to a single group, so several modules can add the same discount code " +
+			"to their own groups without managing the `groups` of the discount code. Only the group of the " +
+			"membership is added and removed, the other groups are kept. Concurrent changes of the groups are " +
+			"retried with the current groups, regardless of the `conflict_strategy` of the provider.\n\n" +
+			"Don't set `groups` on a `commercetools_discount_code` which has memberships, and ignore its " +
+			"changes with `lifecycle { ignore_changes = [groups] }`, otherwise the discount code removes the " +
+			"groups of the memberships.\n\n" +
+			"See also the [Discount Code Api Documentation](https://docs.commercetools.com/api/projects/discountCodes)",
+		CreateContext: resourceDiscountCodeGroupMembershipCreate,
+		ReadContext:   resourceDiscountCodeGroupMembershipRead,
+		DeleteContext: resourceDiscountCodeGroupMembershipDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDiscountCodeGroupMembershipImportState,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"discount_code_id": {
+				Description: "The ID of the discount code",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"group": {
+				Description: "The group the discount code is added to",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+func resourceDiscountCodeGroupMembershipCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	discountCodeID := d.Get("discount_code_id").(string)
+	group := d.Get("group").(string)
+
+	err := changeDiscountCodeGroups(ctx, m, discountCodeID, d.Timeout(schema.TimeoutCreate), func(groups []string) []string {
+		if stringInSlice(group, groups) {
+			return nil
+		}
+		return append(groups, group)
+	})
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	d.SetId(discountCodeGroupMembershipID(discountCodeID, group))
+	return resourceDiscountCodeGroupMembershipRead(ctx, d, m)
+}
+
+func resourceDiscountCodeGroupMembershipRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	discountCodeID, group, err := parseDiscountCodeGroupMembershipID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Reading groups of discount code %s from commercetools", discountCodeID)
+	discountCode, err := getClient(m).DiscountCodes().WithId(discountCodeID).Get().Execute(ctx)
+	if err != nil {
+		if isNotFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return diagnosticsFromError(err)
+	}
+	if !stringInSlice(group, discountCode.Groups) {
+		log.Printf("[DEBUG] Discount code %s is no longer in group %s", discountCodeID, group)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("discount_code_id", discountCodeID)
+	d.Set("group", group)
+	return nil
+}
+
+func resourceDiscountCodeGroupMembershipDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	discountCodeID := d.Get("discount_code_id").(string)
+	group := d.Get("group").(string)
+
+	err := changeDiscountCodeGroups(ctx, m, discountCodeID, d.Timeout(schema.TimeoutDelete), func(groups []string) []string {
+		if !stringInSlice(group, groups) {
+			return nil
+		}
+		result := []string{}
+		for _, value := range groups {
+			if value != group {
+				result = append(result, value)
+			}
+		}
+		return result
+	})
+	if err != nil && !isNotFoundError(err) {
+		return diagnosticsFromError(err)
+	}
+	return nil
+}
+
+func resourceDiscountCodeGroupMembershipImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	discountCodeID, group, err := parseDiscountCodeGroupMembershipID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("discount_code_id", discountCodeID)
+	d.Set("group", group)
+	return []*schema.ResourceData{d}, nil
+}
+
+// changeDiscountCodeGroups updates the groups of the discount code to the
+// result of change, which returns nil when the groups are already as desired.
+// The groups are read again when the discount code was changed concurrently,
+// so memberships of the same discount code don't overwrite each other.
+func changeDiscountCodeGroups(ctx context.Context, m interface{}, id string, timeout time.Duration, change func(groups []string) []string) error {
+	client := getClient(m)
+
+	// Lock to prevent concurrent updates due to Version number conflicts
+	ctMutexKV.Lock(id)
+	defer ctMutexKV.Unlock(id)
+
+	return retryContext(ctx, m, "change discount code groups", timeout, func() *resource.RetryError {
+		discountCode, err := client.DiscountCodes().WithId(id).Get().Execute(ctx)
+		if err != nil {
+			if isNotFoundError(err) {
+				return resource.NonRetryableError(err)
+			}
+			return handleCommercetoolsError(err)
+		}
+
+		groups := change(discountCode.Groups)
+		if groups == nil {
+			return nil
+		}
+		log.Printf("[DEBUG] Changing groups of discount code %s to %s", id, strings.Join(groups, ", "))
+
+		_, err = client.DiscountCodes().WithId(id).Post(platform.DiscountCodeUpdate{
+			Version: discountCode.Version,
+			Actions: []platform.DiscountCodeUpdateAction{
+				&platform.DiscountCodeChangeGroupsAction{Groups: groups},
+			},
+		}).Execute(ctx)
+		if err != nil {
+			if isConflictError(err) {
+				log.Printf("[DEBUG] Groups of discount code %s were changed, retrying", id)
+				return resource.RetryableError(err)
+			}
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+}
+
+// discountCodeGroupMembershipID returns the ID of the membership. Discount
+// code IDs don't contain a slash, so the group can be anything.
+func discountCodeGroupMembershipID(discountCodeID, group string) string {
+	return fmt.Sprintf("%s/%s", discountCodeID, group)
+}
+
+func parseDiscountCodeGroupMembershipID(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid discount code group membership ID %q, expected <discount_code_id>/<group>", id)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,112 @@
+package commercetools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDiscountCodeGroups is discount code code-1 with groups. The first
+// update is rejected with a conflict after concurrentGroup was added, like an
+// update of another membership.
+type fakeDiscountCodeGroups struct {
+	mu              sync.Mutex
+	version         int
+	groups          []string
+	concurrentGroup string
+	conflicts       int
+}
+
+func (f *fakeDiscountCodeGroups) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodPost {
+		var update struct {
+			Version int                      `json:"version"`
+			Actions []map[string]interface{} `json:"actions"`
+		}
+		json.NewDecoder(r.Body).Decode(&update)
+		if f.concurrentGroup != "" {
+			f.groups = append(f.groups, f.concurrentGroup)
+			f.concurrentGroup = ""
+			f.version++
+		}
+		if update.Version != f.version {
+			f.conflicts++
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"statusCode": 409, "message": "Version mismatch"}`))
+			return
+		}
+		f.groups = expandStringArray(update.Actions[0]["groups"].([]interface{}))
+		f.version++
+	}
+	groups, _ := json.Marshal(f.groups)
+	fmt.Fprintf(w, `{"id": "code-1", "version": %d, "code": "SUMMER", "groups": %s}`, f.version, groups)
+}
+
+func newFakeDiscountCodeGroupsMeta(t *testing.T, fake *fakeDiscountCodeGroups) *providerMeta {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	return &providerMeta{client: client.WithProjectKey("my-project")}
+}
+
+func TestResourceDiscountCodeGroupMembershipCreateConcurrent(t *testing.T) {
+	fake := &fakeDiscountCodeGroups{version: 1, groups: []string{"summer"}, concurrentGroup: "newsletter"}
+	meta := newFakeDiscountCodeGroupsMeta(t, fake)
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCodeGroupMembership().Schema, map[string]interface{}{
+		"discount_code_id": "code-1",
+		"group":            "vip",
+	})
+	diags := resourceDiscountCodeGroupMembershipCreate(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, "code-1/vip", d.Id())
+	assert.Equal(t, 1, fake.conflicts)
+	assert.Equal(t, []string{"summer", "newsletter", "vip"}, fake.groups)
+}
+
+func TestResourceDiscountCodeGroupMembershipDelete(t *testing.T) {
+	fake := &fakeDiscountCodeGroups{version: 1, groups: []string{"summer", "vip"}}
+	meta := newFakeDiscountCodeGroupsMeta(t, fake)
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCodeGroupMembership().Schema, map[string]interface{}{
+		"discount_code_id": "code-1",
+		"group":            "vip",
+	})
+	d.SetId("code-1/vip")
+
+	diags := resourceDiscountCodeGroupMembershipDelete(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, []string{"summer"}, fake.groups)
+
+	// The membership is gone once the group was removed
+	diags = resourceDiscountCodeGroupMembershipRead(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Empty(t, d.Id())
+}
+
+func TestParseDiscountCodeGroupMembershipID(t *testing.T) {
+	id, group, err := parseDiscountCodeGroupMembershipID(discountCodeGroupMembershipID("code-1", "summer/2021"))
+	assert.NoError(t, err)
+	assert.Equal(t, "code-1", id)
+	assert.Equal(t, "summer/2021", group)
+
+	_, _, err = parseDiscountCodeGroupMembershipID("code-1")
+	assert.EqualError(t, err, `invalid discount code group membership ID "code-1", expected <discount_code_id>/<group>`)
+}
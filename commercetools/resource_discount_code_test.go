@@ -2,14 +2,1096 @@ package commercetools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
+func TestResourceDiscountCodeCreateNoResult(t *testing.T) {
+	var correlationID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationID = r.Header.Get("X-Correlation-ID")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("null"))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"code": "SUMMER",
+	})
+	diags := resourceDiscountCodeCreate(context.Background(), d, meta)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, `No discount code created for code "SUMMER"`, diags[0].Summary)
+	assert.NotEmpty(t, correlationID)
+	assert.Contains(t, diags[0].Detail, correlationID)
+}
+
+func TestResourceDiscountCodeCreateImportIfExists(t *testing.T) {
+	var created bool
+	var actions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		existing := `{"id": "code-1", "version": 3, "code": "SUMMER", "isActive": true,
+			"name": {"en": "Summer"}, "description": {"en": "Old"}, "groups": ["summer"],
+			"cartPredicate": "1=1", "cartDiscounts": [{"typeId": "cart-discount", "id": "cart-discount-1"}]}`
+		switch {
+		case r.URL.Path == "/my-project/discount-codes" && r.Method == http.MethodPost:
+			created = true
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(existing))
+		case r.URL.Path == "/my-project/discount-codes":
+			assert.Equal(t, `code = "SUMMER"`, r.URL.Query().Get("where"))
+			fmt.Fprintf(w, `{"count": 1, "results": [%s]}`, existing)
+		case r.URL.Path == "/my-project/discount-codes/code-1" && r.Method == http.MethodPost:
+			var update struct {
+				Actions []map[string]interface{} `json:"actions"`
+			}
+			json.NewDecoder(r.Body).Decode(&update)
+			for _, action := range update.Actions {
+				actions = append(actions, action["action"].(string))
+			}
+			w.Write([]byte(existing))
+		case r.URL.Path == "/my-project/discount-codes/code-1":
+			w.Write([]byte(existing))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"statusCode": 404, "message": "Not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"code":             "SUMMER",
+		"name":             map[string]interface{}{"en": "Summer"},
+		"predicate":        "1=1",
+		"cart_discounts":   []interface{}{"cart-discount-1"},
+		"groups":           []interface{}{"summer", "newsletter"},
+		"import_if_exists": true,
+	})
+	diags := resourceDiscountCodeCreate(context.Background(), d, meta)
+	assert.False(t, diags.HasError())
+	if assert.NotEmpty(t, diags) {
+		assert.Equal(t, "Imported existing discount code SUMMER", diags[0].Summary)
+	}
+	assert.False(t, created)
+	assert.Equal(t, "code-1", d.Id())
+
+	// Only the attributes which differ are updated, the description is not
+	// configured so it is cleared
+	assert.Equal(t, []string{"changeGroups", "setDescription"}, actions)
+}
+
+func TestResourceDiscountCodeCreateTimeoutRecovery(t *testing.T) {
+	var posts int
+	var createdAt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/my-project/discount-codes" && r.Method == http.MethodPost:
+			// The discount code is created, but the response is lost
+			posts++
+			if createdAt == "" {
+				createdAt = time.Now().UTC().Format(time.RFC3339)
+			}
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"statusCode": 502, "message": "Bad gateway"}`))
+		case r.URL.Path == "/my-project/discount-codes":
+			assert.Equal(t, `code = "SUMMER"`, r.URL.Query().Get("where"))
+			if createdAt == "" {
+				w.Write([]byte(`{"count": 0, "results": []}`))
+				return
+			}
+			fmt.Fprintf(w, `{"count": 1, "results": [{"id": "code-1", "version": 1, "code": "SUMMER", "createdAt": %q}]}`, createdAt)
+		default:
+			fmt.Fprintf(w, `{"id": "code-1", "version": 1, "code": "SUMMER", "createdAt": %q}`, createdAt)
+		}
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	r := resourceDiscountCode()
+	r.Timeouts.Create = schema.DefaultTimeout(time.Second)
+	d := r.Data(nil)
+	d.Set("code", "SUMMER")
+	d.Set("is_active", true)
+
+	// Terraform bounds the context of the create by its timeout as well
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	diags := resourceDiscountCodeCreate(ctx, d, meta)
+	assert.False(t, diags.HasError())
+	if assert.NotEmpty(t, diags) {
+		assert.Equal(t, "Discount code SUMMER was created although the create failed", diags[0].Summary)
+	}
+	assert.Equal(t, "code-1", d.Id())
+	assert.Equal(t, "SUMMER", d.Get("code"))
+	assert.GreaterOrEqual(t, posts, 1)
+}
+
+func TestResourceDiscountCodeCreateRetryDuplicate(t *testing.T) {
+	var posts int
+	var createdAt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/my-project/discount-codes" && r.Method == http.MethodPost:
+			posts++
+			if posts == 1 {
+				// The discount code is created, but the response is lost
+				createdAt = time.Now().UTC().Format(time.RFC3339)
+				w.WriteHeader(http.StatusBadGateway)
+				w.Write([]byte(`{"statusCode": 502, "message": "Bad gateway"}`))
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode": 400, "message": "A duplicate value 'SUMMER' exists for field 'code'.",
+				"errors": [{"code": "DuplicateField", "message": "A duplicate value 'SUMMER' exists for field 'code'.",
+				"field": "code", "duplicateValue": "SUMMER"}]}`))
+		case r.URL.Path == "/my-project/discount-codes":
+			fmt.Fprintf(w, `{"count": 1, "results": [{"id": "code-1", "version": 1, "code": "SUMMER", "createdAt": %q}]}`, createdAt)
+		default:
+			fmt.Fprintf(w, `{"id": "code-1", "version": 1, "code": "SUMMER", "createdAt": %q}`, createdAt)
+		}
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := resourceDiscountCode().Data(nil)
+	d.Set("code", "SUMMER")
+	d.Set("is_active", true)
+
+	diags := resourceDiscountCodeCreate(context.Background(), d, meta)
+	assert.False(t, diags.HasError())
+	if assert.NotEmpty(t, diags) {
+		assert.Equal(t, "Discount code SUMMER was created although the create failed", diags[0].Summary)
+	}
+	assert.Equal(t, "code-1", d.Id())
+	assert.Equal(t, 2, posts)
+
+	// A duplicate code of the first attempt is not created by the provider
+	posts = 1
+	d = resourceDiscountCode().Data(nil)
+	d.Set("code", "SUMMER")
+	diags = resourceDiscountCodeCreate(context.Background(), d, meta)
+	if assert.True(t, diags.HasError()) {
+		assert.Contains(t, diags[0].Summary, "A duplicate value")
+	}
+	assert.Equal(t, "", d.Id())
+}
+
+func TestResourceDiscountCodeCreateNoConsistencyWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "code-1", "version": 1, "code": "SUMMER"}`))
+			return
+		}
+		// The created discount code is not readable yet
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"statusCode": 404, "message": "Not found"}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"code":                        "SUMMER",
+		"wait_for_create_consistency": false,
+	})
+	diags := resourceDiscountCodeCreate(context.Background(), d, meta)
+	if assert.True(t, diags.HasError()) {
+		assert.Equal(t, "Discount code SUMMER was created but can't be read yet", diags[0].Summary)
+	}
+	assert.Equal(t, "code-1", d.Id())
+}
+
+func TestResourceDiscountCodeReadModifiedBy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "code-1",
+			"version": 3,
+			"code": "SUMMER",
+			"createdAt": "2021-08-01T10:00:00.000Z",
+			"createdBy": {"clientId": "terraform"},
+			"lastModifiedAt": "2021-08-02T12:30:00.000Z",
+			"lastModifiedBy": {"clientId": "merchant-center", "customer": {"typeId": "customer", "id": "customer-1"}}
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{})
+	d.SetId("code-1")
+	diags := resourceDiscountCodeRead(context.Background(), d, meta)
+	assert.Empty(t, diags)
+
+	assert.Equal(t, "2021-08-01T10:00:00Z", d.Get("created_at"))
+	assert.Equal(t, "terraform", d.Get("created_by.0.client_id"))
+	assert.Equal(t, "2021-08-02T12:30:00Z", d.Get("last_modified_at"))
+	assert.Equal(t, "merchant-center", d.Get("last_modified_by.0.client_id"))
+	assert.Equal(t, "customer-1", d.Get("last_modified_by.0.customer_id"))
+	assert.Equal(t, "", d.Get("last_modified_by.0.external_user_id"))
+}
+
+func TestResourceDiscountCodeReadGroupsOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "code-1",
+			"version": 3,
+			"code": "SUMMER",
+			"isActive": true,
+			"cartDiscounts": [{"typeId": "cart-discount", "id": "cart-discount-1"}],
+			"groups": ["group-c", "group-b", "group-a"]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	config := map[string]interface{}{
+		"code":           "SUMMER",
+		"cart_discounts": []interface{}{"cart-discount-1"},
+		"groups":         []interface{}{"group-a", "group-b", "group-c"},
+	}
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, config)
+	d.SetId("code-1")
+	diags := resourceDiscountCodeRead(context.Background(), d, meta)
+	assert.Empty(t, diags)
+	assert.Equal(t, []interface{}{"group-a", "group-b", "group-c"}, d.Get("groups"))
+
+	diff, err := resourceDiscountCode().SimpleDiff(
+		context.Background(), d.State(), terraform.NewResourceConfigRaw(config), meta)
+	assert.NoError(t, err)
+	if diff != nil {
+		for key := range diff.Attributes {
+			assert.NotContains(t, key, "groups")
+		}
+	}
+}
+
+func TestMarshallDiscountCodeGroups(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"groups": []interface{}{"group-a", "group-b"},
+	})
+	assert.Equal(t, []string{"group-a", "group-b"}, marshallDiscountCodeGroups([]string{"group-b", "group-a"}, d))
+	assert.Equal(t, []string{"group-c", "group-a"}, marshallDiscountCodeGroups([]string{"group-c", "group-a"}, d))
+	assert.Equal(t, []string{"group-b"}, marshallDiscountCodeGroups([]string{"group-b"}, d))
+	assert.Equal(t, []string{"group-a", "group-a"}, marshallDiscountCodeGroups([]string{"group-a", "group-a"}, d))
+}
+
+func TestDiscountCodeEffectiveStatus(t *testing.T) {
+	now := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+	cases := []struct {
+		discountCode platform.DiscountCode
+		status       string
+	}{
+		{platform.DiscountCode{IsActive: true}, "active"},
+		{platform.DiscountCode{IsActive: true, ValidFrom: &before, ValidUntil: &after}, "active"},
+		{platform.DiscountCode{IsActive: false, ValidFrom: &before, ValidUntil: &after}, "inactive"},
+		{platform.DiscountCode{IsActive: false, ValidUntil: &before}, "inactive"},
+		{platform.DiscountCode{IsActive: true, ValidFrom: &after}, "scheduled"},
+		{platform.DiscountCode{IsActive: true, ValidUntil: &before}, "expired"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.status, discountCodeEffectiveStatus(&c.discountCode, now), "%+v", c.discountCode)
+	}
+}
+
+func TestResourceDiscountCodeReadExpired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "code-1",
+			"version": 3,
+			"code": "SUMMER",
+			"isActive": true,
+			"validUntil": "2021-06-01T00:00:00.000Z"
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{})
+	d.SetId("code-1")
+	diags := resourceDiscountCodeRead(context.Background(), d, meta)
+	assert.Equal(t, "expired", d.Get("effective_status"))
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+		assert.Equal(t, "Discount code SUMMER is active but expired", diags[0].Summary)
+	}
+}
+
+func TestDiscountCodeCartDiscountReferences(t *testing.T) {
+	id := "5d3e1a0c-7b2f-4e8b-9c51-2f0d3a6b8e11"
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"cart_discounts": []interface{}{id, "summer-sale"},
+	})
+	assert.Equal(t, []platform.CartDiscountResourceIdentifier{
+		{ID: stringRef(id)},
+		{Key: stringRef("summer-sale")},
+	}, unmarshallDiscountCodeCartDiscounts(d))
+
+	summerKey, winterKey := "summer-sale", "winter-sale"
+	references := []platform.CartDiscountReference{
+		{ID: id, Obj: &platform.CartDiscount{ID: id, Key: &winterKey}},
+		{ID: "0b9f6c1e-3d2a-4c7b-8e5f-1a2b3c4d5e6f", Obj: &platform.CartDiscount{Key: &summerKey}},
+	}
+	assert.Equal(t,
+		[]string{id, "summer-sale"},
+		marshallDiscountCodeCartDiscounts(references, []string{id, "summer-sale"}))
+	assert.Equal(t,
+		[]string{id, "0b9f6c1e-3d2a-4c7b-8e5f-1a2b3c4d5e6f"},
+		marshallDiscountCodeCartDiscounts(references, nil))
+}
+
+func TestPlanDiscountCodeUpdateActions(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "code-1",
+		Attributes: map[string]string{
+			"id":                          "code-1",
+			"code":                        "SUMMER",
+			"is_active":                   "true",
+			"wait_for_create_consistency": "true",
+			"max_applications":            "10",
+			"planned_actions.#":           "1",
+			"planned_actions.0":           `{"action":"setMaxApplications","maxApplications":10}`,
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"code":             "SUMMER",
+		"is_active":        false,
+		"max_applications": 10,
+	})
+
+	diff, err := resourceDiscountCode().SimpleDiff(context.Background(), state, config, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"action":"setMaxApplications","maxApplications":10}`, diff.Attributes["planned_actions.0"].Old)
+	assert.Equal(t, `{"action":"changeIsActive","isActive":false}`, diff.Attributes["planned_actions.0"].New)
+
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"code":             "SUMMER",
+		"is_active":        true,
+		"max_applications": 10,
+	})
+	diff, err = resourceDiscountCode().SimpleDiff(context.Background(), state, config, nil)
+	assert.NoError(t, err)
+	if diff != nil {
+		// The last applied actions are kept when nothing is sent
+		_, ok := diff.Attributes["planned_actions.#"]
+		assert.False(t, ok)
+	}
+}
+
+func TestBuildDiscountCodePredicate(t *testing.T) {
+	assert.Equal(t, "1=1", buildDiscountCodePredicate("1=1", nil))
+	assert.Equal(t, `store.key in ("store-a")`, buildDiscountCodePredicate("", []string{"store-a"}))
+	assert.Equal(t,
+		`(totalPrice > "10.00 EUR") and store.key in ("store-a", "store-b")`,
+		buildDiscountCodePredicate(`totalPrice > "10.00 EUR"`, []string{"store-b", "store-a"}))
+	assert.Equal(t, `store.key in ("with\"quote")`, buildDiscountCodePredicate("", []string{`with"quote`}))
+}
+
+func TestMarshallDiscountCodePredicate(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"predicate": "1=1",
+		"stores":    []interface{}{"store-a"},
+	})
+
+	check := func(d *schema.ResourceData, value *string, predicate string, stores []string) {
+		t.Helper()
+		resultPredicate, resultStores := marshallDiscountCodePredicate(value, d)
+		assert.Equal(t, predicate, resultPredicate)
+		assert.Equal(t, stores, resultStores)
+	}
+	check(d, stringRef(`(1=1) and store.key in ("store-a")`), "1=1", []string{"store-a"})
+	check(d, stringRef(`(1=1) and store.key in ("store-a", "store-b")`), "1=1", []string{"store-a", "store-b"})
+	check(d, stringRef("1=2"), "1=2", nil)
+	check(d, nil, "", nil)
+
+	// Imported discount codes get the stores of the predicate
+	imported := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{})
+	check(imported, stringRef(`store.key in ("with\"quote", "with\\backslash")`), "",
+		[]string{`with"quote`, `with\backslash`})
+	check(imported, stringRef(`(a = "b") and (c = "d") and store.key in ("store-a")`),
+		`(a = "b") and (c = "d") and store.key in ("store-a")`, nil)
+	check(imported, stringRef(`((a = "b") and (c = "(")) and store.key in ("store-a")`), `(a = "b") and (c = "(")`, []string{"store-a"})
+	check(imported, stringRef(`store.key in ("store-b", "store-a")`), `store.key in ("store-b", "store-a")`, nil)
+
+	// A predicate which is configured with the store clause stays as is
+	configured := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"predicate": `store.key in ("store-a")`,
+	})
+	check(configured, stringRef(`store.key in ("store-a")`), `store.key in ("store-a")`, nil)
+}
+
+func TestResourceDiscountCodeReadStores(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "code-1",
+			"version": 3,
+			"code": "SUMMER",
+			"cartPredicate": "(1 = 1) and store.key in (\"store-a\", \"store-b\")"
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	// An imported discount code has no stores in the state yet
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{})
+	d.SetId("code-1")
+	diags := resourceDiscountCodeRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, "1 = 1", d.Get("predicate"))
+	assert.ElementsMatch(t, []interface{}{"store-a", "store-b"}, d.Get("stores").(*schema.Set).List())
+}
+
+func TestValidateDiscountCodeStores(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/my-project/stores/key=existing":
+			w.Write([]byte(`{"id": "store-1", "key": "existing"}`))
+		case strings.HasPrefix(r.URL.Path, "/my-project/stores/"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"statusCode": 404, "message": "Not found"}`))
+		default:
+			w.Write([]byte(`{"count": 0, "results": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	// A store which doesn't exist yet may be created in the same apply
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"code":           "SUMMER",
+		"cart_discounts": []interface{}{"cart-discount-1"},
+		"stores":         []interface{}{"existing", "created-in-this-apply"},
+	})
+	_, err = resourceDiscountCode().SimpleDiff(context.Background(), &terraform.InstanceState{}, config, meta)
+	assert.NoError(t, err)
+}
+
+func TestCartDiscountRequiresCodeWarning(t *testing.T) {
+	warning := cartDiscountRequiresCodeWarning(&platform.CartDiscount{ID: "cart-discount-1"})
+	assert.NotNil(t, warning)
+	assert.Equal(t, diag.Warning, warning.Severity)
+	assert.Contains(t, warning.Summary, "cart-discount-1")
+
+	warning = cartDiscountRequiresCodeWarning(&platform.CartDiscount{ID: "cart-discount-2", RequiresDiscountCode: true})
+	assert.Nil(t, warning)
+}
+
+func TestBuildDiscountCodeUpdateActionsCustomTypeOrder(t *testing.T) {
+	old := map[string]interface{}{
+		"code": "SUMMER",
+		"name": map[string]interface{}{"en": "Summer"},
+		"custom": []interface{}{
+			map[string]interface{}{
+				"type_id": "type-1",
+				"fields":  map[string]interface{}{"campaign": "spring"},
+			},
+		},
+	}
+	new := map[string]interface{}{
+		"code": "SUMMER",
+		"name": map[string]interface{}{"en": "Summer sale"},
+		"custom": []interface{}{
+			map[string]interface{}{
+				"type_id": "type-2",
+				"fields":  map[string]interface{}{"campaign": "summer", "budget": "100"},
+			},
+		},
+	}
+
+	d := testResourceDataChange(t, resourceDiscountCode().Schema, old, new)
+	actions, err := buildDiscountCodeUpdateActions(d)
+	assert.NoError(t, err)
+
+	typeID := "type-2"
+	assert.Equal(t, []platform.DiscountCodeUpdateAction{
+		&platform.DiscountCodeSetCustomTypeAction{
+			Type: &platform.TypeResourceIdentifier{ID: &typeID},
+		},
+		&platform.DiscountCodeSetNameAction{
+			Name: &platform.LocalizedString{"en": "Summer sale"},
+		},
+		&platform.DiscountCodeSetCustomFieldAction{Name: "budget", Value: float64(100)},
+		&platform.DiscountCodeSetCustomFieldAction{Name: "campaign", Value: "summer"},
+	}, actions)
+}
+
+func TestBuildDiscountCodeUpdateActionsCustomField(t *testing.T) {
+	old := map[string]interface{}{
+		"code": "SUMMER",
+		"custom": []interface{}{
+			map[string]interface{}{
+				"type_id": "type-1",
+				"fields": map[string]interface{}{
+					"campaign": "summer",
+					"budget":   "100",
+					"price":    `{"currencyCode": "EUR", "centAmount": 500}`,
+				},
+			},
+		},
+	}
+	new := map[string]interface{}{
+		"code": "SUMMER",
+		"custom": []interface{}{
+			map[string]interface{}{
+				"type_id": "type-1",
+				"fields": map[string]interface{}{
+					"campaign": "summer",
+					"budget":   "200",
+					"price":    `{"currencyCode": "EUR", "centAmount": 500}`,
+				},
+			},
+		},
+	}
+
+	d := testResourceDataChange(t, resourceDiscountCode().Schema, old, new)
+	actions, err := buildDiscountCodeUpdateActions(d)
+	assert.NoError(t, err)
+	assert.Equal(t, []platform.DiscountCodeUpdateAction{
+		&platform.DiscountCodeSetCustomFieldAction{Name: "budget", Value: float64(200)},
+	}, actions)
+}
+
+func TestBuildDiscountCodeUpdateActionsCustomRemoval(t *testing.T) {
+	old := map[string]interface{}{
+		"code": "SUMMER",
+		"custom": []interface{}{
+			map[string]interface{}{
+				"type_id": "type-1",
+				"fields":  map[string]interface{}{"campaign": "summer", "budget": "100"},
+			},
+		},
+	}
+
+	// Removing a field clears only that field and keeps the type
+	d := testResourceDataChange(t, resourceDiscountCode().Schema, old, map[string]interface{}{
+		"code": "SUMMER",
+		"custom": []interface{}{
+			map[string]interface{}{
+				"type_id": "type-1",
+				"fields":  map[string]interface{}{"campaign": "summer"},
+			},
+		},
+	})
+	actions, err := buildDiscountCodeUpdateActions(d)
+	assert.NoError(t, err)
+	assert.Equal(t, []platform.DiscountCodeUpdateAction{
+		&platform.DiscountCodeSetCustomFieldAction{Name: "budget"},
+	}, actions)
+	data, err := json.Marshal(actions[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"action": "setCustomField", "name": "budget"}`, string(data))
+
+	// Removing the custom block detaches the type, which removes all fields
+	d = testResourceDataChange(t, resourceDiscountCode().Schema, old, map[string]interface{}{"code": "SUMMER"})
+	actions, err = buildDiscountCodeUpdateActions(d)
+	assert.NoError(t, err)
+	assert.Equal(t, []platform.DiscountCodeUpdateAction{
+		&platform.DiscountCodeSetCustomTypeAction{},
+	}, actions)
+	data, err = json.Marshal(actions[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"action": "setCustomType"}`, string(data))
+}
+
+func TestBuildDiscountCodeUpdateActionsGroups(t *testing.T) {
+	old := map[string]interface{}{
+		"code":   "SUMMER",
+		"groups": []interface{}{"group-a"},
+	}
+	new := map[string]interface{}{
+		"code":   "SUMMER",
+		"groups": []interface{}{"group-b"},
+	}
+
+	d := testResourceDataChange(t, resourceDiscountCode().Schema, old, new)
+	actions, err := buildDiscountCodeUpdateActions(d)
+	assert.NoError(t, err)
+	assert.Equal(t, []platform.DiscountCodeUpdateAction{
+		&platform.DiscountCodeChangeGroupsAction{Groups: []string{"group-b"}},
+	}, actions)
+
+	d = testResourceDataChange(t, resourceDiscountCode().Schema, old, map[string]interface{}{"code": "SUMMER"})
+	actions, err = buildDiscountCodeUpdateActions(d)
+	assert.NoError(t, err)
+	assert.Equal(t, []platform.DiscountCodeUpdateAction{
+		&platform.DiscountCodeChangeGroupsAction{Groups: []string{}},
+	}, actions)
+}
+
+func TestBuildDiscountCodeUpdateActionsValidUntil(t *testing.T) {
+	old := map[string]interface{}{
+		"code":        "SUMMER",
+		"valid_from":  "2021-06-01T00:00:00Z",
+		"valid_until": "2021-08-31T23:59:59Z",
+	}
+	new := map[string]interface{}{
+		"code":        "SUMMER",
+		"valid_from":  "2021-06-01T02:00:00+02:00",
+		"valid_until": "2021-09-30T23:59:59Z",
+	}
+
+	d := testResourceDataChange(t, resourceDiscountCode().Schema, old, new)
+	actions, err := buildDiscountCodeUpdateActions(d)
+	assert.NoError(t, err)
+	validUntil := time.Date(2021, 9, 30, 23, 59, 59, 0, time.UTC)
+	assert.Equal(t, []platform.DiscountCodeUpdateAction{
+		&platform.DiscountCodeSetValidUntilAction{ValidUntil: &validUntil},
+	}, actions)
+}
+
+// Changing the groups and the validity together must be a single request, so
+// the discount code is never in between the old and the new campaign.
+func TestResourceDiscountCodeUpdateGroupsAndValidUntil(t *testing.T) {
+	var posts []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			posts = append(posts, body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "00000000-0000-0000-0000-000000000000", "version": 2, "code": "SUMMER"}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := testResourceDataChange(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"code":        "SUMMER",
+		"groups":      []interface{}{"summer-2021"},
+		"valid_until": "2021-08-31T23:59:59Z",
+	}, map[string]interface{}{
+		"code":        "SUMMER",
+		"groups":      []interface{}{"summer-2021", "extended"},
+		"valid_until": "2021-09-30T23:59:59Z",
+	})
+
+	diags := resourceDiscountCodeUpdate(context.Background(), d, meta)
+	assert.False(t, diags.HasError())
+	if assert.Len(t, posts, 1) {
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"action": "changeGroups", "groups": []interface{}{"summer-2021", "extended"}},
+			map[string]interface{}{"action": "setValidUntil", "validUntil": "2021-09-30T23:59:59Z"},
+		}, posts[0]["actions"])
+	}
+	assert.Equal(t, []interface{}{
+		`{"action":"changeGroups","groups":["summer-2021","extended"]}`,
+		`{"action":"setValidUntil","validUntil":"2021-09-30T23:59:59Z"}`,
+	}, d.Get("planned_actions"))
+}
+
+func TestResourceDiscountCodeDiffEmptyLocalizedString(t *testing.T) {
+	current := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"code":           "SUMMER",
+		"cart_discounts": []interface{}{"cart-discount-1"},
+		"name":           map[string]interface{}{"en": "Summer"},
+	})
+	current.SetId("code-1")
+
+	diff := func(config map[string]interface{}) *terraform.InstanceDiff {
+		state := current.State()
+		raw, err := json.Marshal(config)
+		assert.NoError(t, err)
+		// Terraform passes the configuration along with the state when
+		// planning, which tells an empty map apart from a removed attribute
+		state.RawConfig, err = ctyjson.Unmarshal(raw, resourceDiscountCode().CoreConfigSchema().ImpliedType())
+		assert.NoError(t, err)
+
+		result, err := resourceDiscountCode().SimpleDiff(
+			context.Background(), state, terraform.NewResourceConfigRaw(config), &providerMeta{})
+		assert.NoError(t, err)
+		return result
+	}
+
+	empty := diff(map[string]interface{}{
+		"code":           "SUMMER",
+		"cart_discounts": []interface{}{"cart-discount-1"},
+		"name":           map[string]interface{}{},
+	})
+	assert.Nil(t, empty.Attributes["name.en"])
+
+	removed := diff(map[string]interface{}{
+		"code":           "SUMMER",
+		"cart_discounts": []interface{}{"cart-discount-1"},
+	})
+	if assert.NotNil(t, removed.Attributes["name.en"]) {
+		assert.True(t, removed.Attributes["name.en"].NewRemoved)
+	}
+}
+
+func TestValidateDiscountCodeUnique(t *testing.T) {
+	holders := map[string]string{
+		"FOREIGN":  `{"id": "code-foreign", "code": "FOREIGN", "isActive": true, "createdBy": {"clientId": "merchant-center"}}`,
+		"MINE":     `{"id": "code-mine", "code": "MINE", "isActive": true, "createdBy": {"clientId": "terraform"}}`,
+		"DISABLED": `{"id": "code-disabled", "code": "DISABLED", "isActive": false, "createdBy": {"clientId": "merchant-center"}}`,
+		"REPLACED": `{"id": "code-1", "code": "REPLACED", "isActive": true, "createdBy": {"clientId": "merchant-center"}}`,
+		// Predicate strings only escape quotes and backslashes, unlike Go
+		"SALE\u00a0\\1": `{"id": "code-sale", "code": "SALE\u00a0\\1", "isActive": true, "createdBy": {"clientId": "merchant-center"}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		for code, holder := range holders {
+			if r.URL.Query().Get("where") == "code = "+quotePredicateString(code) {
+				fmt.Fprintf(w, `{"count": 1, "results": [%s]}`, holder)
+				return
+			}
+		}
+		w.Write([]byte(`{"count": 0, "results": []}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project"), clientID: "terraform"}
+
+	config := func(code, name string) map[string]interface{} {
+		return map[string]interface{}{
+			"code":           code,
+			"name":           map[string]interface{}{"en": name},
+			"cart_discounts": []interface{}{"cart-discount-1"},
+		}
+	}
+	// diff plans the configuration with Terraform passing the raw
+	// configuration, which tells the resources apart
+	diff := func(state *terraform.InstanceState, config map[string]interface{}) error {
+		raw, err := json.Marshal(config)
+		assert.NoError(t, err)
+		state.RawConfig, err = ctyjson.Unmarshal(raw, resourceDiscountCode().CoreConfigSchema().ImpliedType())
+		assert.NoError(t, err)
+		_, err = resourceDiscountCode().SimpleDiff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+		return err
+	}
+	existing := func(id string, config map[string]interface{}) *terraform.InstanceState {
+		d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, config)
+		d.SetId(id)
+		return d.State()
+	}
+
+	err = diff(&terraform.InstanceState{}, config("FOREIGN", "Foreign"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "code FOREIGN is already used by discount code code-foreign")
+	}
+	err = diff(&terraform.InstanceState{}, config("SALE\u00a0\\1", "Sale"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "is already used by discount code code-sale")
+	}
+	found, err := findDiscountCodeByCode(context.Background(), meta.client, "SALE\u00a0\\1")
+	if assert.NoError(t, err) && assert.NotNil(t, found) {
+		assert.Equal(t, "code-sale", found.ID)
+	}
+	// Most likely managed by a resource which is removed in the same apply
+	assert.NoError(t, diff(&terraform.InstanceState{}, config("MINE", "Mine")))
+	// Left by disable_on_destroy
+	assert.NoError(t, diff(&terraform.InstanceState{}, config("DISABLED", "Disabled")))
+
+	// When a discount code is replaced Terraform plans the update and then the
+	// create of the replacement while the discount code still exists
+	replaced := config("REPLACED", "Replaced")
+	assert.NoError(t, diff(existing("code-1", replaced), replaced))
+	assert.NoError(t, diff(&terraform.InstanceState{}, replaced))
+
+	// Duplicates in the configuration
+	assert.NoError(t, diff(&terraform.InstanceState{}, config("NEW", "First")))
+	err = diff(&terraform.InstanceState{}, config("NEW", "Second"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "code NEW is also used by another discount code in the configuration")
+	}
+	assert.NoError(t, diff(existing("code-2", config("KEPT", "Kept")), config("KEPT", "Kept")))
+	err = diff(&terraform.InstanceState{}, config("KEPT", "Copy"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "code KEPT is also used by discount code code-2 in the configuration")
+	}
+}
+
+func TestValidateMaxApplicationsPerCustomer(t *testing.T) {
+	path := cty.GetAttrPath("max_applications_per_customer")
+
+	assert.Empty(t, validateMaxApplicationsPerCustomer(5, path))
+	assert.Empty(t, validateMaxApplicationsPerCustomer(1, path))
+
+	diags := validateMaxApplicationsPerCustomer(0, path)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "max_applications_per_customer must be at least 1, got: 0", diags[0].Summary)
+		assert.Equal(t, path, diags[0].AttributePath)
+	}
+	assert.Len(t, validateMaxApplicationsPerCustomer(-3, path), 1)
+}
+
+func TestBuildDiscountCodeUpdateActionsMaxApplicationsPerCustomer(t *testing.T) {
+	old := map[string]interface{}{
+		"code":                          "SUMMER",
+		"max_applications_per_customer": 2,
+	}
+
+	d := testResourceDataChange(t, resourceDiscountCode().Schema, old, map[string]interface{}{
+		"code":                          "SUMMER",
+		"max_applications_per_customer": 3,
+	})
+	actions, err := buildDiscountCodeUpdateActions(d)
+	assert.NoError(t, err)
+	assert.Equal(t, []platform.DiscountCodeUpdateAction{
+		&platform.DiscountCodeSetMaxApplicationsPerCustomerAction{MaxApplicationsPerCustomer: intRef(3)},
+	}, actions)
+
+	d = testResourceDataChange(t, resourceDiscountCode().Schema, old, map[string]interface{}{"code": "SUMMER"})
+	actions, err = buildDiscountCodeUpdateActions(d)
+	assert.NoError(t, err)
+	assert.Equal(t, []platform.DiscountCodeUpdateAction{
+		&platform.DiscountCodeSetMaxApplicationsPerCustomerAction{},
+	}, actions)
+}
+
+func TestDiscountCodeTimeouts(t *testing.T) {
+	r := resourceDiscountCode()
+
+	d := r.Data(nil)
+	for _, key := range []string{schema.TimeoutCreate, schema.TimeoutRead, schema.TimeoutUpdate, schema.TimeoutDelete} {
+		assert.Equal(t, 1*time.Minute, d.Timeout(key), key)
+	}
+
+	timeouts := &schema.ResourceTimeout{}
+	err := timeouts.ConfigDecode(r, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"timeouts": []interface{}{
+			map[string]interface{}{"create": "5m"},
+		},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, *timeouts.Create)
+	assert.Equal(t, 1*time.Minute, *timeouts.Update)
+}
+
+func TestDiscountCodeDeleteWarning(t *testing.T) {
+	now := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		config map[string]interface{}
+		warn   bool
+	}{
+		{map[string]interface{}{"code": "SUMMER", "valid_from": "2021-06-01T00:00:00Z", "valid_until": "2021-09-01T00:00:00Z"}, true},
+		{map[string]interface{}{"code": "SUMMER", "valid_until": "2021-09-01T00:00:00Z"}, true},
+		{map[string]interface{}{"code": "SUMMER", "valid_from": "2021-08-01T00:00:00Z"}, false},
+		{map[string]interface{}{"code": "SUMMER", "valid_until": "2021-06-01T00:00:00Z"}, false},
+		{map[string]interface{}{"code": "SUMMER", "valid_from": "2021-06-01T00:00:00Z", "is_active": false}, false},
+		{map[string]interface{}{"code": "SUMMER"}, false},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, c.config)
+		warning := discountCodeDeleteWarning(d, now)
+		if c.warn {
+			assert.NotNil(t, warning, "%v", c.config)
+			assert.Equal(t, diag.Warning, warning.Severity)
+		} else {
+			assert.Nil(t, warning, "%v", c.config)
+		}
+	}
+}
+
+func TestDiscountCodeExpiredWarning(t *testing.T) {
+	now := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	past := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	future := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	warning := discountCodeExpiredWarning("SUMMER", &past, now)
+	if assert.NotNil(t, warning) {
+		assert.Equal(t, diag.Warning, warning.Severity)
+		assert.Equal(t, "Discount code SUMMER is created with a valid_until in the past", warning.Summary)
+		assert.Contains(t, warning.Detail, "2021-06-01T00:00:00Z")
+	}
+	assert.Nil(t, discountCodeExpiredWarning("SUMMER", &future, now))
+	assert.Nil(t, discountCodeExpiredWarning("SUMMER", nil, now))
+}
+
+func TestResourceDiscountCodeCreateExpired(t *testing.T) {
+	fake := &fakeDiscountCodes{codes: map[string]string{}}
+	meta := newFakeDiscountCodesMeta(t, fake)
+
+	// A backdated code is created, with a warning instead of an error
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"code":        "SUMMER",
+		"valid_until": "2021-06-01T00:00:00Z",
+	})
+	diags := resourceDiscountCodeCreate(context.Background(), d, meta)
+	assert.False(t, diags.HasError())
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "Discount code SUMMER is created with a valid_until in the past", diags[0].Summary)
+	}
+	assert.Equal(t, "id-SUMMER", d.Id())
+}
+
+func TestResourceDiscountCodeDeleteDisableOnDestroy(t *testing.T) {
+	var methods []string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "code-1", "version": 4, "code": "SUMMER", "isActive": false}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"code":               "SUMMER",
+		"disable_on_destroy": true,
+	})
+	d.SetId("code-1")
+	d.Set("version", 3)
+
+	diags := resourceDiscountCodeDelete(context.Background(), d, meta)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, []string{http.MethodPost}, methods)
+	assert.Equal(t, map[string]interface{}{
+		"version": 3.0,
+		"actions": []interface{}{
+			map[string]interface{}{"action": "changeIsActive", "isActive": false},
+		},
+	}, body)
+}
+
+func TestResourceDiscountCodeDeleteRefuseIfRedeemed(t *testing.T) {
+	var requests []string
+	orders := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/my-project/orders" {
+			assert.Equal(t, `discountCodes(discountCode(id = "code-1"))`, r.URL.Query().Get("where"))
+			fmt.Fprintf(w, `{"limit": 1, "count": %d, "total": %d, "results": []}`, orders, orders)
+			return
+		}
+		w.Write([]byte(`{"id": "code-1", "version": 3, "code": "SUMMER"}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	d := schema.TestResourceDataRaw(t, resourceDiscountCode().Schema, map[string]interface{}{
+		"code":                      "SUMMER",
+		"refuse_delete_if_redeemed": true,
+	})
+	d.SetId("code-1")
+	d.Set("version", 3)
+
+	diags := resourceDiscountCodeDelete(context.Background(), d, meta)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "Discount code SUMMER was redeemed and is not deleted", diags[0].Summary)
+		assert.Contains(t, diags[0].Detail, "2 order(s) used the discount code")
+	}
+	assert.Equal(t, []string{"GET /my-project/orders"}, requests)
+
+	requests, orders = nil, 0
+	diags = resourceDiscountCodeDelete(context.Background(), d, meta)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, []string{"GET /my-project/orders", "DELETE /my-project/discount-codes/code-1"}, requests)
+}
+
 func TestAccDiscountCodeCreate_basic(t *testing.T) {
 
 	resource.Test(t, resource.TestCase{
@@ -330,6 +1412,130 @@ func testAccDiscountCodeRemoveProperties() string {
 	  }`
 }
 
+func TestAccDiscountCode_duplicateCode(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDiscountCodeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDiscountCodeDuplicateConfig(false),
+			},
+			{
+				Config:      testAccDiscountCodeDuplicateConfig(true),
+				ExpectError: regexp.MustCompile("code DUPLICATE is already used by discount code"),
+			},
+		},
+	})
+}
+
+func testAccDiscountCodeDuplicateConfig(duplicate bool) string {
+	config := `
+	resource "commercetools_cart_discount" "duplicate" {
+		name = {
+			en = "duplicate"
+		}
+		sort_order             = "0.7654"
+		predicate              = "1=1"
+		requires_discount_code = true
+
+		target {
+			type      = "lineItems"
+			predicate = "1=1"
+		}
+
+		value {
+			type      = "relative"
+			permyriad = 1000
+		}
+	}
+
+	resource "commercetools_discount_code" "original" {
+		code           = "DUPLICATE"
+		cart_discounts = [commercetools_cart_discount.duplicate.id]
+	}
+	`
+	if duplicate {
+		config += `
+	resource "commercetools_discount_code" "copy" {
+		code           = "DUPLICATE"
+		cart_discounts = [commercetools_cart_discount.duplicate.id]
+	}
+	`
+	}
+	return config
+}
+
+func TestAccDiscountCode_disableOnDestroy(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDiscountCodeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDiscountCodeDisableOnDestroyConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("commercetools_discount_code.disabled", "is_active", "true"),
+					func(s *terraform.State) error {
+						id = s.RootModule().Resources["commercetools_discount_code.disabled"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				// Removing the discount code from the configuration destroys it
+				Config: testAccDiscountCodeDisableOnDestroyConfig(false),
+				Check: func(s *terraform.State) error {
+					client := getClient(testAccProvider.Meta())
+					code, err := client.DiscountCodes().WithId(id).Get().Execute(context.Background())
+					if err != nil {
+						return fmt.Errorf("discount code (%s) was deleted: %w", id, err)
+					}
+					if code.IsActive {
+						return fmt.Errorf("discount code (%s) is still active", id)
+					}
+					_, err = client.DiscountCodes().WithId(id).Delete().Version(code.Version).Execute(context.Background())
+					return err
+				},
+			},
+		},
+	})
+}
+
+func testAccDiscountCodeDisableOnDestroyConfig(withCode bool) string {
+	config := `
+	resource "commercetools_cart_discount" "disabled" {
+		name = {
+			en = "disabled"
+		}
+		sort_order             = "0.7653"
+		predicate              = "1=1"
+		requires_discount_code = true
+
+		target {
+			type      = "lineItems"
+			predicate = "1=1"
+		}
+
+		value {
+			type      = "relative"
+			permyriad = 1000
+		}
+	}
+	`
+	if withCode {
+		config += `
+	resource "commercetools_discount_code" "disabled" {
+		code               = "DISABLE-ON-DESTROY"
+		cart_discounts     = [commercetools_cart_discount.disabled.id]
+		disable_on_destroy = true
+	}
+	`
+	}
+	return config
+}
+
 func testAccCheckDiscountCodeDestroy(s *terraform.State) error {
 	client := getClient(testAccProvider.Meta())
 
@@ -367,3 +1573,78 @@ func testAccCheckDiscountCodeDestroy(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestResolveDiscountCodeImport(t *testing.T) {
+	a := &platform.DiscountCode{ID: "id-a", Code: "SUMMER"}
+	b := &platform.DiscountCode{ID: "id-b", Code: "id-a"}
+
+	id, err := resolveDiscountCodeImport("id-a", a, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "id-a", id)
+
+	id, err = resolveDiscountCodeImport("SUMMER", nil, a)
+	assert.NoError(t, err)
+	assert.Equal(t, "id-a", id)
+
+	id, err = resolveDiscountCodeImport("id-a", a, a)
+	assert.NoError(t, err)
+	assert.Equal(t, "id-a", id)
+
+	_, err = resolveDiscountCodeImport("id-a", a, b)
+	assert.EqualError(t, err, `"id-a" is ambiguous: it is the ID of the discount code with code "SUMMER" and `+
+		`the code of the discount code with ID id-b. Import with id=id-a or code=id-a instead`)
+
+	_, err = resolveDiscountCodeImport("WINTER", nil, nil)
+	assert.EqualError(t, err, `no discount code found with ID or code "WINTER"`)
+}
+
+func TestValidateDiscountCodeLocales(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "code-1",
+		Attributes: map[string]string{
+			"id":                          "code-1",
+			"code":                        "SUMMER",
+			"is_active":                   "true",
+			"wait_for_create_consistency": "true",
+			"planned_actions.#":           "0",
+		},
+	}
+	meta := &providerMeta{requiredNameLocales: []string{"en", "de"}}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"code":      "SUMMER",
+		"is_active": true,
+		"name":      map[string]interface{}{"en": "Summer sale", "de": "Sommerschlussverkauf"},
+	})
+	_, err := resourceDiscountCode().SimpleDiff(context.Background(), state, config, meta)
+	assert.NoError(t, err)
+
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"code":        "SUMMER",
+		"is_active":   true,
+		"name":        map[string]interface{}{"en": "Summer sale", "nl": "Zomeruitverkoop"},
+		"description": map[string]interface{}{"en": "Summer sale"},
+	})
+	_, err = resourceDiscountCode().SimpleDiff(context.Background(), state, config, meta)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "name does not contain the locale(s) de")
+	}
+
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"code":        "SUMMER",
+		"is_active":   true,
+		"name":        map[string]interface{}{"en": "Summer sale", "de": "Sommerschlussverkauf"},
+		"description": map[string]interface{}{"nl": "Zomeruitverkoop"},
+	})
+	_, err = resourceDiscountCode().SimpleDiff(context.Background(), state, config, meta)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "description does not contain the locale(s) en, de")
+	}
+}
+
+func TestMissingLocales(t *testing.T) {
+	value := map[string]interface{}{"en": "Summer sale", "de": ""}
+	assert.Equal(t, []string{"de", "nl"}, missingLocales(value, []string{"en", "de", "nl"}))
+	assert.Nil(t, missingLocales(value, []string{"en"}))
+	assert.Equal(t, []string{"en"}, missingLocales(nil, []string{"en"}))
+}
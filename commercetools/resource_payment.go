@@ -0,0 +1,612 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func resourcePayment() *schema.Resource {
+	return &schema.Resource{
+		Description: "Payments hold information about the current state of receiving and/or refunding money. " +
+			"Transactions can be added but never removed, since the payment records what happened at the PSP.\n\n" +
+			"See also the [Payment API Documentation](https://docs.commercetools.com/api/projects/payments)",
+		CreateContext: resourcePaymentCreate,
+		ReadContext:   resourcePaymentRead,
+		UpdateContext: resourcePaymentUpdate,
+		DeleteContext: resourcePaymentDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePaymentImportState,
+		},
+		CustomizeDiff: validatePaymentTransactions,
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Description: "User-specific unique identifier for the payment",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"interface_id": {
+				Description: "The identifier used by the payment service that processes the payment (PSP)",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"amount_planned": {
+				Description: "How much money this payment intends to receive from the customer",
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Required:    true,
+				Elem:        paymentMoneyResource(),
+			},
+			"payment_method_info": {
+				Description: "[PaymentMethodInfo](https://docs.commercetools.com/api/projects/payments#paymentmethodinfo)",
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"payment_interface": {
+							Description: "The PSP, for example `Adyen` or `Stripe`",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"method": {
+							Description: "The payment method, for example `CreditCard`",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"name": {
+							Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+							Type:             TypeLocalizedString,
+							ValidateDiagFunc: validateLocalizedString,
+							Optional:         true,
+						},
+					},
+				},
+			},
+			"payment_status": {
+				Description: "[PaymentStatus](https://docs.commercetools.com/api/projects/payments#paymentstatus)",
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"interface_code": {
+							Description: "The status code of the PSP",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"interface_text": {
+							Description: "The status text of the PSP",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"transactions": {
+				Description: "Array of [Transaction](https://docs.commercetools.com/api/projects/payments#transaction). " +
+					"Transactions can only be appended, the type and amount of an existing transaction cannot change",
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Description: "Authorization, CancelAuthorization, Charge, Refund or Chargeback",
+							Type:        schema.TypeString,
+							Required:    true,
+							ValidateFunc: validation.StringInSlice([]string{
+								string(platform.TransactionTypeAuthorization),
+								string(platform.TransactionTypeCancelAuthorization),
+								string(platform.TransactionTypeCharge),
+								string(platform.TransactionTypeRefund),
+								string(platform.TransactionTypeChargeback),
+							}, false),
+						},
+						"amount": {
+							Description: "The amount of money of the transaction",
+							Type:        schema.TypeList,
+							MaxItems:    1,
+							Required:    true,
+							Elem:        paymentMoneyResource(),
+						},
+						"timestamp": {
+							Description:      "The time at which the transaction took place",
+							Type:             schema.TypeString,
+							Optional:         true,
+							Computed:         true,
+							ValidateFunc:     validation.IsRFC3339Time,
+							DiffSuppressFunc: diffSuppressTime,
+						},
+						"interaction_id": {
+							Description: "The identifier used by the PSP for the transaction",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"state": {
+							Description: "Initial, Pending, Success or Failure",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							ValidateFunc: validation.StringInSlice([]string{
+								string(platform.TransactionStateInitial),
+								string(platform.TransactionStatePending),
+								string(platform.TransactionStateSuccess),
+								string(platform.TransactionStateFailure),
+							}, false),
+						},
+					},
+				},
+			},
+			"custom": customFieldSchema(),
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func paymentMoneyResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"currency_code": {
+				Description:  "The currency code compliant to [ISO 4217](https://en.wikipedia.org/wiki/ISO_4217)",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: ValidateCurrencyCode,
+			},
+			"cent_amount": {
+				Description: "The amount in cents (the smallest indivisible unit of the currency)",
+				Type:        schema.TypeInt,
+				Required:    true,
+			},
+		},
+	}
+}
+
+// validatePaymentTransactions checks that the planned transactions only add
+// new transactions or change the fields which can be updated, since
+// transactions cannot be removed from a payment.
+func validatePaymentTransactions(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("transactions") {
+		return nil
+	}
+	old, new := d.GetChange("transactions")
+	return checkPaymentTransactionChanges(old.([]interface{}), new.([]interface{}))
+}
+
+func checkPaymentTransactionChanges(old, new []interface{}) error {
+	if len(new) < len(old) {
+		return fmt.Errorf("transactions cannot be removed from a payment")
+	}
+	for i := range old {
+		o := old[i].(map[string]interface{})
+		n := new[i].(map[string]interface{})
+		if o["type"] != n["type"] {
+			return fmt.Errorf("the type of transaction %d cannot be changed", i)
+		}
+		if fmt.Sprint(o["amount"]) != fmt.Sprint(n["amount"]) {
+			return fmt.Errorf("the amount of transaction %d cannot be changed", i)
+		}
+	}
+	return nil
+}
+
+func resourcePaymentCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	var payment *platform.Payment
+
+	custom, err := unmarshallCustomFieldsDraft(d)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	transactions := []platform.TransactionDraft{}
+	for _, raw := range d.Get("transactions").([]interface{}) {
+		transaction, err := unmarshallPaymentTransaction(raw.(map[string]interface{}))
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	draft := platform.PaymentDraft{
+		Key:                   stringRef(d.Get("key")),
+		AmountPlanned:         unmarshallPaymentMoney(d.Get("amount_planned")),
+		PaymentMethodInfo:     unmarshallPaymentMethodInfo(d),
+		PaymentStatus:         unmarshallPaymentStatus(d),
+		Transactions:          transactions,
+		InterfaceInteractions: []platform.CustomFieldsDraft{},
+		Custom:                custom,
+	}
+
+	if val := d.Get("interface_id").(string); len(val) > 0 {
+		draft.InterfaceId = &val
+	}
+
+	errorResponse := retryContext(ctx, m, "create payment", 1*time.Minute, func() *resource.RetryError {
+		var err error
+
+		payment, err = client.Payments().Post(draft).Execute(ctx)
+
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+
+	if errorResponse != nil {
+		return diagnosticsFromError(errorResponse)
+	}
+
+	if payment == nil {
+		return diag.Errorf("No payment created")
+	}
+
+	d.SetId(payment.ID)
+	d.Set("version", payment.Version)
+
+	return resourcePaymentRead(ctx, d, m)
+}
+
+func resourcePaymentRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading payment from commercetools, with payment id: %s", d.Id())
+
+	client := getClient(m)
+
+	payment, err := client.Payments().WithId(d.Id()).Get().Execute(ctx)
+
+	if err != nil {
+		if isNotFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return diagnosticsFromError(err)
+	}
+
+	if payment == nil {
+		log.Print("[DEBUG] No payment found")
+		d.SetId("")
+	} else {
+		log.Print("[DEBUG] Found following payment:")
+		log.Print(stringFormatObject(payment))
+
+		d.Set("version", payment.Version)
+		d.Set("key", payment.Key)
+		d.Set("interface_id", payment.InterfaceId)
+		d.Set("amount_planned", []map[string]interface{}{marshallTypedMoney(payment.AmountPlanned)})
+		d.Set("payment_method_info", marshallPaymentMethodInfo(payment.PaymentMethodInfo))
+		d.Set("payment_status", marshallPaymentStatus(payment.PaymentStatus))
+		d.Set("transactions", marshallPaymentTransactions(payment.Transactions))
+		d.Set("custom", marshallCustomFields(payment.Custom))
+	}
+
+	return nil
+}
+
+func resourcePaymentUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	payment, err := client.Payments().WithId(d.Id()).Get().Execute(ctx)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	input := platform.PaymentUpdate{
+		Version: payment.Version,
+		Actions: []platform.PaymentUpdateAction{},
+	}
+
+	if d.HasChange("key") {
+		newKey := d.Get("key").(string)
+		input.Actions = append(
+			input.Actions,
+			&platform.PaymentSetKeyAction{Key: &newKey})
+	}
+
+	if d.HasChange("interface_id") {
+		newInterfaceID := d.Get("interface_id").(string)
+		input.Actions = append(
+			input.Actions,
+			&platform.PaymentSetInterfaceIdAction{InterfaceId: newInterfaceID})
+	}
+
+	if d.HasChange("amount_planned") {
+		input.Actions = append(
+			input.Actions,
+			&platform.PaymentChangeAmountPlannedAction{Amount: unmarshallPaymentMoney(d.Get("amount_planned"))})
+	}
+
+	if d.HasChange("payment_method_info.0.payment_interface") {
+		newInterface := d.Get("payment_method_info.0.payment_interface").(string)
+		input.Actions = append(
+			input.Actions,
+			&platform.PaymentSetMethodInfoInterfaceAction{Interface: newInterface})
+	}
+
+	if d.HasChange("payment_method_info.0.method") {
+		action := &platform.PaymentSetMethodInfoMethodAction{}
+		if val := d.Get("payment_method_info.0.method").(string); len(val) > 0 {
+			action.Method = &val
+		}
+		input.Actions = append(input.Actions, action)
+	}
+
+	if d.HasChange("payment_method_info.0.name") {
+		newName := unmarshallLocalizedString(d.Get("payment_method_info.0.name"))
+		input.Actions = append(
+			input.Actions,
+			&platform.PaymentSetMethodInfoNameAction{Name: &newName})
+	}
+
+	if d.HasChange("payment_status.0.interface_code") {
+		action := &platform.PaymentSetStatusInterfaceCodeAction{}
+		if val := d.Get("payment_status.0.interface_code").(string); len(val) > 0 {
+			action.InterfaceCode = &val
+		}
+		input.Actions = append(input.Actions, action)
+	}
+
+	if d.HasChange("payment_status.0.interface_text") {
+		newText := d.Get("payment_status.0.interface_text").(string)
+		input.Actions = append(
+			input.Actions,
+			&platform.PaymentSetStatusInterfaceTextAction{InterfaceText: newText})
+	}
+
+	if d.HasChange("transactions") {
+		old, new := d.GetChange("transactions")
+		actions, err := resourcePaymentTransactionActions(old.([]interface{}), new.([]interface{}))
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+		input.Actions = append(input.Actions, actions...)
+	}
+
+	if d.HasChange("custom") {
+		action := &platform.PaymentSetCustomTypeAction{}
+		custom, err := unmarshallCustomFieldsDraft(d)
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+		if custom != nil {
+			action.Type = &custom.Type
+			action.Fields = custom.Fields
+		}
+		input.Actions = append(input.Actions, action)
+	}
+
+	log.Printf(
+		"[DEBUG] Will perform update operation with the following actions:\n%s",
+		stringFormatActions(input.Actions))
+
+	_, err = client.Payments().WithId(payment.ID).Post(input).Execute(ctx)
+	if err != nil {
+		if ctErr, ok := err.(platform.ErrorResponse); ok {
+			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
+		}
+		return diagnosticsFromError(err)
+	}
+
+	return resourcePaymentRead(ctx, d, m)
+}
+
+func resourcePaymentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	version := d.Get("version").(int)
+	_, err := client.Payments().WithId(d.Id()).Delete().Version(version).Execute(ctx)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+	return nil
+}
+
+// resourcePaymentImportState allows importing a payment by either its ID or
+// its key. The ID is tried first, when no payment is found we fall back to the
+// key.
+func resourcePaymentImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := getClient(m)
+	value := d.Id()
+
+	payment, err := client.Payments().WithId(value).Get().Execute(ctx)
+	if err != nil && isNotFoundError(err) {
+		payment, err = client.Payments().WithKey(value).Get().Execute(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(payment.ID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourcePaymentTransactionActions returns the actions to go from the old to
+// the new list of transactions. Existing transactions are matched by position,
+// transactions added at the end of the list are added to the payment.
+func resourcePaymentTransactionActions(old, new []interface{}) ([]platform.PaymentUpdateAction, error) {
+	if err := checkPaymentTransactionChanges(old, new); err != nil {
+		return nil, err
+	}
+
+	actions := []platform.PaymentUpdateAction{}
+	for i, raw := range new {
+		n := raw.(map[string]interface{})
+		if i >= len(old) {
+			transaction, err := unmarshallPaymentTransaction(n)
+			if err != nil {
+				return nil, err
+			}
+			actions = append(actions, &platform.PaymentAddTransactionAction{Transaction: transaction})
+			continue
+		}
+
+		o := old[i].(map[string]interface{})
+		id := o["id"].(string)
+
+		if val := n["interaction_id"].(string); val != o["interaction_id"] {
+			actions = append(actions, &platform.PaymentChangeTransactionInteractionIdAction{
+				TransactionId: id,
+				InteractionId: val,
+			})
+		}
+		// The timestamp is returned in UTC, so the instants are compared
+		if val := n["timestamp"].(string); val != "" && !diffSuppressTime("timestamp", o["timestamp"].(string), val, nil) {
+			timestamp, err := unmarshallTime(val)
+			if err != nil {
+				return nil, err
+			}
+			actions = append(actions, &platform.PaymentChangeTransactionTimestampAction{
+				TransactionId: id,
+				Timestamp:     timestamp,
+			})
+		}
+		if val := n["state"].(string); val != "" && val != o["state"] {
+			actions = append(actions, &platform.PaymentChangeTransactionStateAction{
+				TransactionId: id,
+				State:         platform.TransactionState(val),
+			})
+		}
+	}
+	return actions, nil
+}
+
+func unmarshallPaymentMoney(val interface{}) platform.Money {
+	items, ok := val.([]interface{})
+	if !ok || len(items) == 0 || items[0] == nil {
+		return platform.Money{}
+	}
+	input := items[0].(map[string]interface{})
+	return platform.Money{
+		CurrencyCode: input["currency_code"].(string),
+		CentAmount:   input["cent_amount"].(int),
+	}
+}
+
+func unmarshallPaymentMethodInfo(d *schema.ResourceData) *platform.PaymentMethodInfo {
+	input, err := elementFromList(d, "payment_method_info")
+	if err != nil || input == nil {
+		return nil
+	}
+
+	result := &platform.PaymentMethodInfo{}
+	if val := input["payment_interface"].(string); len(val) > 0 {
+		result.PaymentInterface = &val
+	}
+	if val := input["method"].(string); len(val) > 0 {
+		result.Method = &val
+	}
+	if name := unmarshallLocalizedString(input["name"]); len(name) > 0 {
+		result.Name = &name
+	}
+	return result
+}
+
+func unmarshallPaymentStatus(d *schema.ResourceData) *platform.PaymentStatusDraft {
+	input, err := elementFromList(d, "payment_status")
+	if err != nil || input == nil {
+		return nil
+	}
+
+	result := &platform.PaymentStatusDraft{}
+	if val := input["interface_code"].(string); len(val) > 0 {
+		result.InterfaceCode = &val
+	}
+	if val := input["interface_text"].(string); len(val) > 0 {
+		result.InterfaceText = &val
+	}
+	return result
+}
+
+func unmarshallPaymentTransaction(input map[string]interface{}) (platform.TransactionDraft, error) {
+	draft := platform.TransactionDraft{
+		Type:   platform.TransactionType(input["type"].(string)),
+		Amount: unmarshallPaymentMoney(input["amount"]),
+	}
+	if val := input["timestamp"].(string); len(val) > 0 {
+		timestamp, err := unmarshallTime(val)
+		if err != nil {
+			return draft, err
+		}
+		draft.Timestamp = &timestamp
+	}
+	if val := input["interaction_id"].(string); len(val) > 0 {
+		draft.InteractionId = &val
+	}
+	if val := input["state"].(string); len(val) > 0 {
+		state := platform.TransactionState(val)
+		draft.State = &state
+	}
+	return draft, nil
+}
+
+func marshallPaymentMethodInfo(val platform.PaymentMethodInfo) []map[string]interface{} {
+	if val.PaymentInterface == nil && val.Method == nil && val.Name == nil {
+		return []map[string]interface{}{}
+	}
+
+	result := map[string]interface{}{
+		"payment_interface": "",
+		"method":            "",
+		"name":              val.Name,
+	}
+	if val.PaymentInterface != nil {
+		result["payment_interface"] = *val.PaymentInterface
+	}
+	if val.Method != nil {
+		result["method"] = *val.Method
+	}
+	if val.Name != nil {
+		result["name"] = *val.Name
+	}
+	return []map[string]interface{}{result}
+}
+
+func marshallPaymentStatus(val platform.PaymentStatus) []map[string]interface{} {
+	if val.InterfaceCode == nil && val.InterfaceText == nil {
+		return []map[string]interface{}{}
+	}
+
+	result := map[string]interface{}{
+		"interface_code": "",
+		"interface_text": "",
+	}
+	if val.InterfaceCode != nil {
+		result["interface_code"] = *val.InterfaceCode
+	}
+	if val.InterfaceText != nil {
+		result["interface_text"] = *val.InterfaceText
+	}
+	return []map[string]interface{}{result}
+}
+
+func marshallPaymentTransactions(values []platform.Transaction) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(values))
+	for i, value := range values {
+		item := map[string]interface{}{
+			"id":             value.ID,
+			"type":           string(value.Type),
+			"amount":         []map[string]interface{}{marshallTypedMoney(value.Amount)},
+			"timestamp":      marshallTime(value.Timestamp),
+			"interaction_id": "",
+			"state":          "",
+		}
+		if value.InteractionId != nil {
+			item["interaction_id"] = *value.InteractionId
+		}
+		if value.State != nil {
+			item["state"] = string(*value.State)
+		}
+		result[i] = item
+	}
+	return result
+}
@@ -0,0 +1,236 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPaymentTransaction(id, state, interactionID string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":   id,
+		"type": "Charge",
+		"amount": []interface{}{
+			map[string]interface{}{"currency_code": "EUR", "cent_amount": 1000},
+		},
+		"timestamp":      "2021-08-01T10:00:00Z",
+		"interaction_id": interactionID,
+		"state":          state,
+	}
+}
+
+func TestResourcePaymentTransactionActions(t *testing.T) {
+	old := []interface{}{
+		testPaymentTransaction("transaction-1", "Pending", "interaction-1"),
+	}
+	new := []interface{}{
+		testPaymentTransaction("transaction-1", "Success", "interaction-2"),
+		testPaymentTransaction("", "", ""),
+	}
+
+	actions, err := resourcePaymentTransactionActions(old, new)
+	assert.NoError(t, err)
+
+	timestamp := time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, []platform.PaymentUpdateAction{
+		&platform.PaymentChangeTransactionInteractionIdAction{
+			TransactionId: "transaction-1",
+			InteractionId: "interaction-2",
+		},
+		&platform.PaymentChangeTransactionStateAction{
+			TransactionId: "transaction-1",
+			State:         platform.TransactionStateSuccess,
+		},
+		&platform.PaymentAddTransactionAction{
+			Transaction: platform.TransactionDraft{
+				Timestamp: &timestamp,
+				Type:      platform.TransactionTypeCharge,
+				Amount:    platform.Money{CurrencyCode: "EUR", CentAmount: 1000},
+			},
+		},
+	}, actions)
+}
+
+func TestResourcePaymentTransactionTimestamp(t *testing.T) {
+	old := []interface{}{testPaymentTransaction("transaction-1", "Pending", "interaction-1")}
+	configured := testPaymentTransaction("transaction-1", "Pending", "interaction-1")
+
+	// The same instant with an offset and fractional seconds is not changed
+	configured["timestamp"] = "2021-08-01T12:00:00.000+02:00"
+	actions, err := resourcePaymentTransactionActions(old, []interface{}{configured})
+	assert.NoError(t, err)
+	assert.Empty(t, actions)
+	s := resourcePayment().Schema["transactions"].Elem.(*schema.Resource).Schema["timestamp"]
+	assert.True(t, s.DiffSuppressFunc("transactions.0.timestamp", "2021-08-01T10:00:00Z", configured["timestamp"].(string), nil))
+
+	configured["timestamp"] = "2021-08-01T12:00:00+01:00"
+	actions, err = resourcePaymentTransactionActions(old, []interface{}{configured})
+	assert.NoError(t, err)
+	if assert.Len(t, actions, 1) {
+		action := actions[0].(*platform.PaymentChangeTransactionTimestampAction)
+		assert.Equal(t, "transaction-1", action.TransactionId)
+		assert.True(t, action.Timestamp.Equal(time.Date(2021, 8, 1, 11, 0, 0, 0, time.UTC)))
+	}
+}
+
+func TestCheckPaymentTransactionChanges(t *testing.T) {
+	old := []interface{}{
+		testPaymentTransaction("transaction-1", "Pending", ""),
+	}
+
+	assert.NoError(t, checkPaymentTransactionChanges(old, old))
+	assert.EqualError(t, checkPaymentTransactionChanges(old, []interface{}{}),
+		"transactions cannot be removed from a payment")
+
+	changedType := testPaymentTransaction("transaction-1", "Pending", "")
+	changedType["type"] = "Refund"
+	assert.EqualError(t, checkPaymentTransactionChanges(old, []interface{}{changedType}),
+		"the type of transaction 0 cannot be changed")
+
+	changedAmount := testPaymentTransaction("transaction-1", "Pending", "")
+	changedAmount["amount"] = []interface{}{
+		map[string]interface{}{"currency_code": "EUR", "cent_amount": 500},
+	}
+	assert.EqualError(t, checkPaymentTransactionChanges(old, []interface{}{changedAmount}),
+		"the amount of transaction 0 cannot be changed")
+}
+
+func TestAccPayment_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPaymentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPaymentConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"commercetools_payment.standard", "key", "standard",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_payment.standard", "amount_planned.0.cent_amount", "1500",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_payment.standard", "transactions.#", "1",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_payment.standard", "transactions.0.state", "Pending",
+					),
+				),
+			},
+			{
+				Config: testAccPaymentUpdate(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"commercetools_payment.standard", "key", "standard-new",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_payment.standard", "amount_planned.0.cent_amount", "2000",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_payment.standard", "payment_status.0.interface_code", "paid",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_payment.standard", "transactions.#", "2",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_payment.standard", "transactions.0.state", "Success",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_payment.standard", "transactions.1.type", "Charge",
+					),
+				),
+			},
+			{
+				ResourceName:      "commercetools_payment.standard",
+				ImportState:       true,
+				ImportStateId:     "standard-new",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccPaymentConfig() string {
+	return `
+resource "commercetools_payment" "standard" {
+	key = "standard"
+
+	amount_planned {
+		currency_code = "EUR"
+		cent_amount   = 1500
+	}
+
+	transactions {
+		type = "Authorization"
+		amount {
+			currency_code = "EUR"
+			cent_amount   = 1500
+		}
+		state = "Pending"
+	}
+}
+`
+}
+
+func testAccPaymentUpdate() string {
+	return `
+resource "commercetools_payment" "standard" {
+	key = "standard-new"
+
+	amount_planned {
+		currency_code = "EUR"
+		cent_amount   = 2000
+	}
+
+	payment_status {
+		interface_code = "paid"
+	}
+
+	transactions {
+		type = "Authorization"
+		amount {
+			currency_code = "EUR"
+			cent_amount   = 1500
+		}
+		state = "Success"
+	}
+
+	transactions {
+		type = "Charge"
+		amount {
+			currency_code = "EUR"
+			cent_amount   = 1500
+		}
+	}
+}
+`
+}
+
+func testAccCheckPaymentDestroy(s *terraform.State) error {
+	client := getClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "commercetools_payment" {
+			continue
+		}
+		response, err := client.Payments().WithId(rs.Primary.ID).Get().Execute(context.Background())
+		if err == nil {
+			if response != nil && response.ID == rs.Primary.ID {
+				return fmt.Errorf("payment (%s) still exists", rs.Primary.ID)
+			}
+			return nil
+		}
+		if newErr := checkApiResult(err); newErr != nil {
+			return newErr
+		}
+	}
+	return nil
+}
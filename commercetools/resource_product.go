@@ -0,0 +1,608 @@
+package commercetools
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func resourceProduct() *schema.Resource {
+	return &schema.Resource{
+		Description: "Products are the items which are sold and which discounts apply to. The resource manages " +
+			"the staged data of the product, which is only visible to customers once the product is published. " +
+			"Variants are identified by their SKU, so changing the SKU of a variant replaces the variant.\n\n" +
+			"See also the [Products API Documentation](https://docs.commercetools.com/api/projects/products)",
+		CreateContext: resourceProductCreate,
+		ReadContext:   resourceProductRead,
+		UpdateContext: resourceProductUpdate,
+		DeleteContext: resourceProductDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Description: "User-specific unique identifier for the product",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"product_type_id": {
+				Description: "The ID of the product type of the product",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Type:             TypeLocalizedString,
+				ValidateDiagFunc: validateLocalizedString,
+				Required:         true,
+			},
+			"slug": {
+				Description:      "Human readable identifiers, needs to be unique per locale",
+				Type:             TypeLocalizedString,
+				ValidateDiagFunc: validateLocalizedString,
+				Required:         true,
+			},
+			"description": {
+				Type:             TypeLocalizedString,
+				ValidateDiagFunc: validateLocalizedString,
+				Optional:         true,
+			},
+			"categories": {
+				Description: "The IDs of the categories of the product",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"master_variant": {
+				Description: "The master variant of the product",
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Required:    true,
+				Elem:        productVariantResource(),
+			},
+			"variants": {
+				Description: "The other variants of the product",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        productVariantResource(),
+			},
+			"publish": {
+				Description: "Whether the product is published. When true all changes are published right away, " +
+					"otherwise they are only staged",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"has_staged_changes": {
+				Description: "Whether the staged data differs from the published data",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func productVariantResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The ID of the variant within the product",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"sku": {
+				Description: "The SKU of the variant, unique across the project",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"key": {
+				Description: "User-specific unique identifier for the variant",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"attributes": {
+				Description: "Map of the attribute values. Values are decoded as JSON when possible, so use " +
+					"`jsonencode()` for strings which would otherwise be valid JSON (for example numbers)",
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"price": {
+				Description: "The prices of the variant",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"currency_code": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: ValidateCurrencyCode,
+						},
+						"cent_amount": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"country": {
+							Description: "A two-digit country code as per ISO 3166-1 alpha-2",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"customer_group_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"channel_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceProductCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	var product *platform.Product
+
+	productTypeID := d.Get("product_type_id").(string)
+	masterVariant := unmarshallProductVariantDraft(d.Get("master_variant.0").(map[string]interface{}))
+	variants := []platform.ProductVariantDraft{}
+	for _, raw := range d.Get("variants").([]interface{}) {
+		variants = append(variants, unmarshallProductVariantDraft(raw.(map[string]interface{})))
+	}
+
+	draft := platform.ProductDraft{
+		ProductType:   platform.ProductTypeResourceIdentifier{ID: &productTypeID},
+		Name:          unmarshallLocalizedString(d.Get("name")),
+		Slug:          unmarshallLocalizedString(d.Get("slug")),
+		Categories:    unmarshallProductCategories(d.Get("categories")),
+		MasterVariant: &masterVariant,
+		Variants:      variants,
+		Publish:       boolRef(d.Get("publish")),
+	}
+
+	if val := d.Get("key").(string); val != "" {
+		draft.Key = &val
+	}
+
+	if description := unmarshallLocalizedString(d.Get("description")); len(description) > 0 {
+		draft.Description = &description
+	}
+
+	err := retryContext(ctx, m, "create product", 1*time.Minute, func() *resource.RetryError {
+		var err error
+
+		product, err = client.Products().Post(draft).Execute(ctx)
+
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	if product == nil {
+		return diag.Errorf("No product created")
+	}
+
+	d.SetId(product.ID)
+	d.Set("version", product.Version)
+
+	return resourceProductRead(ctx, d, m)
+}
+
+func resourceProductRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading product from commercetools, with product id: %s", d.Id())
+
+	client := getClient(m)
+
+	product, err := client.Products().WithId(d.Id()).Get().Execute(ctx)
+
+	if err != nil {
+		if isNotFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return diagnosticsFromError(err)
+	}
+
+	if product == nil {
+		log.Print("[DEBUG] No product found")
+		d.SetId("")
+	} else {
+		log.Print("[DEBUG] Found following product:")
+		log.Print(stringFormatObject(product))
+
+		staged := product.MasterData.Staged
+		d.Set("version", product.Version)
+		d.Set("key", product.Key)
+		d.Set("product_type_id", product.ProductType.ID)
+		d.Set("name", staged.Name)
+		d.Set("slug", staged.Slug)
+		d.Set("description", staged.Description)
+		d.Set("categories", marshallProductCategories(staged.Categories))
+		d.Set("master_variant", []map[string]interface{}{marshallProductVariant(staged.MasterVariant)})
+		d.Set("variants", marshallProductVariants(staged.Variants))
+		d.Set("publish", product.MasterData.Published)
+		d.Set("has_staged_changes", product.MasterData.HasStagedChanges)
+	}
+
+	return nil
+}
+
+func resourceProductUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	product, err := client.Products().WithId(d.Id()).Get().Execute(ctx)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	input := platform.ProductUpdate{
+		Version: product.Version,
+		Actions: buildProductUpdateActions(d),
+	}
+
+	log.Printf(
+		"[DEBUG] Will perform update operation with the following actions:\n%s",
+		stringFormatActions(input.Actions))
+
+	_, err = client.Products().WithId(product.ID).Post(input).Execute(ctx)
+	if err != nil {
+		if ctErr, ok := err.(platform.ErrorResponse); ok {
+			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
+		}
+		return diagnosticsFromError(err)
+	}
+
+	return resourceProductRead(ctx, d, m)
+}
+
+// resourceProductDelete unpublishes the product first when it is published,
+// since published products cannot be deleted
+func resourceProductDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	product, err := client.Products().WithId(d.Id()).Get().Execute(ctx)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil
+		}
+		return diagnosticsFromError(err)
+	}
+
+	version := product.Version
+	if product.MasterData.Published {
+		product, err = client.Products().WithId(d.Id()).Post(platform.ProductUpdate{
+			Version: version,
+			Actions: []platform.ProductUpdateAction{&platform.ProductUnpublishAction{}},
+		}).Execute(ctx)
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+		version = product.Version
+	}
+
+	_, err = client.Products().WithId(d.Id()).Delete().Version(version).Execute(ctx)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+	return nil
+}
+
+// buildProductUpdateActions returns the actions to update the staged data of
+// the product, followed by a publish action when the product is published
+func buildProductUpdateActions(d resourceChange) []platform.ProductUpdateAction {
+	staged := boolRef(true)
+	actions := []platform.ProductUpdateAction{}
+
+	if d.HasChange("key") {
+		action := &platform.ProductSetKeyAction{}
+		if val := d.Get("key").(string); val != "" {
+			action.Key = &val
+		}
+		actions = append(actions, action)
+	}
+
+	if d.HasChange("name") {
+		logLocalizedStringChange(d, "name")
+		actions = append(
+			actions,
+			&platform.ProductChangeNameAction{Name: unmarshallLocalizedString(d.Get("name")), Staged: staged})
+	}
+
+	if d.HasChange("slug") {
+		logLocalizedStringChange(d, "slug")
+		actions = append(
+			actions,
+			&platform.ProductChangeSlugAction{Slug: unmarshallLocalizedString(d.Get("slug")), Staged: staged})
+	}
+
+	if d.HasChange("description") {
+		logLocalizedStringChange(d, "description")
+		action := &platform.ProductSetDescriptionAction{Staged: staged}
+		if description := unmarshallLocalizedString(d.Get("description")); len(description) > 0 {
+			action.Description = &description
+		}
+		actions = append(actions, action)
+	}
+
+	if d.HasChange("categories") {
+		old, new := d.GetChange("categories")
+		oldSet, newSet := old.(*schema.Set), new.(*schema.Set)
+		for _, id := range expandStringArray(oldSet.Difference(newSet).List()) {
+			id := id
+			actions = append(actions, &platform.ProductRemoveFromCategoryAction{
+				Category: platform.CategoryResourceIdentifier{ID: &id},
+				Staged:   staged,
+			})
+		}
+		for _, id := range expandStringArray(newSet.Difference(oldSet).List()) {
+			id := id
+			actions = append(actions, &platform.ProductAddToCategoryAction{
+				Category: platform.CategoryResourceIdentifier{ID: &id},
+				Staged:   staged,
+			})
+		}
+	}
+
+	if d.HasChange("master_variant") || d.HasChange("variants") {
+		oldMaster, newMaster := d.GetChange("master_variant")
+		oldVariants, newVariants := d.GetChange("variants")
+		actions = append(actions, productVariantActions(
+			append(oldMaster.([]interface{}), oldVariants.([]interface{})...),
+			append(newMaster.([]interface{}), newVariants.([]interface{})...),
+		)...)
+	}
+
+	publish := d.Get("publish").(bool)
+	switch {
+	case publish && (len(actions) > 0 || d.HasChange("publish")):
+		actions = append(actions, &platform.ProductPublishAction{})
+	case !publish && d.HasChange("publish"):
+		actions = append(actions, &platform.ProductUnpublishAction{})
+	}
+	return actions
+}
+
+// productVariantActions returns the actions to change the variants of the
+// product from the old to the new variants, where the first variant of each
+// list is the master variant. Variants are matched by SKU: new variants are
+// added before the master variant is changed, so the new master variant
+// exists, and removed variants are removed afterwards, so the old master
+// variant is no longer the master variant.
+func productVariantActions(old, new []interface{}) []platform.ProductUpdateAction {
+	staged := boolRef(true)
+	actions := []platform.ProductUpdateAction{}
+
+	oldBySku := map[string]map[string]interface{}{}
+	for _, raw := range old {
+		variant := raw.(map[string]interface{})
+		oldBySku[variant["sku"].(string)] = variant
+	}
+	newBySku := map[string]bool{}
+	for _, raw := range new {
+		newBySku[raw.(map[string]interface{})["sku"].(string)] = true
+	}
+
+	for _, raw := range new {
+		variant := raw.(map[string]interface{})
+		sku := variant["sku"].(string)
+		if _, ok := oldBySku[sku]; ok {
+			continue
+		}
+		draft := unmarshallProductVariantDraft(variant)
+		actions = append(actions, &platform.ProductAddVariantAction{
+			Sku:        draft.Sku,
+			Key:        draft.Key,
+			Prices:     draft.Prices,
+			Attributes: draft.Attributes,
+			Staged:     staged,
+		})
+	}
+
+	if len(old) > 0 && len(new) > 0 {
+		oldMasterSku := old[0].(map[string]interface{})["sku"].(string)
+		newMasterSku := new[0].(map[string]interface{})["sku"].(string)
+		if oldMasterSku != newMasterSku {
+			actions = append(actions, &platform.ProductChangeMasterVariantAction{
+				Sku:    &newMasterSku,
+				Staged: staged,
+			})
+		}
+	}
+
+	for _, raw := range old {
+		sku := raw.(map[string]interface{})["sku"].(string)
+		if !newBySku[sku] {
+			sku := sku
+			actions = append(actions, &platform.ProductRemoveVariantAction{Sku: &sku, Staged: staged})
+		}
+	}
+
+	for _, raw := range new {
+		variant := raw.(map[string]interface{})
+		sku := variant["sku"].(string)
+		if previous, ok := oldBySku[sku]; ok {
+			actions = append(actions, productVariantChangeActions(sku, previous, variant)...)
+		}
+	}
+	return actions
+}
+
+// productVariantChangeActions returns the actions to change the key,
+// attributes and prices of an existing variant
+func productVariantChangeActions(sku string, old, new map[string]interface{}) []platform.ProductUpdateAction {
+	staged := boolRef(true)
+	actions := []platform.ProductUpdateAction{}
+
+	if old["key"] != new["key"] {
+		action := &platform.ProductSetProductVariantKeyAction{Sku: &sku, Staged: staged}
+		if val := new["key"].(string); val != "" {
+			action.Key = &val
+		}
+		actions = append(actions, action)
+	}
+
+	oldAttributes, _ := old["attributes"].(map[string]interface{})
+	newAttributes, _ := new["attributes"].(map[string]interface{})
+	for _, name := range sortedKeys(mapKeys(oldAttributes, newAttributes)) {
+		oldValue, newValue := oldAttributes[name], newAttributes[name]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		action := &platform.ProductSetAttributeAction{Sku: &sku, Name: name, Staged: staged}
+		if value, ok := newValue.(string); ok {
+			action.Value = unmarshallCustomFieldValue(value)
+		}
+		actions = append(actions, action)
+	}
+
+	if !reflect.DeepEqual(old["price"], new["price"]) {
+		actions = append(actions, &platform.ProductSetPricesAction{
+			Sku:    &sku,
+			Prices: unmarshallProductPrices(new["price"]),
+			Staged: staged,
+		})
+	}
+	return actions
+}
+
+// mapKeys returns the keys of both maps
+func mapKeys(a, b map[string]interface{}) map[string]bool {
+	result := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		result[k] = true
+	}
+	for k := range b {
+		result[k] = true
+	}
+	return result
+}
+
+func unmarshallProductVariantDraft(input map[string]interface{}) platform.ProductVariantDraft {
+	sku := input["sku"].(string)
+	draft := platform.ProductVariantDraft{
+		Sku:        &sku,
+		Prices:     unmarshallProductPrices(input["price"]),
+		Attributes: []platform.Attribute{},
+	}
+	if key, ok := input["key"].(string); ok && key != "" {
+		draft.Key = &key
+	}
+	attributes, _ := input["attributes"].(map[string]interface{})
+	for _, name := range sortedKeys(mapKeys(attributes, nil)) {
+		draft.Attributes = append(draft.Attributes, platform.Attribute{
+			Name:  name,
+			Value: unmarshallCustomFieldValue(attributes[name].(string)),
+		})
+	}
+	return draft
+}
+
+func unmarshallProductPrices(val interface{}) []platform.PriceDraft {
+	result := []platform.PriceDraft{}
+	items, _ := val.([]interface{})
+	for _, raw := range items {
+		input := raw.(map[string]interface{})
+		price := platform.PriceDraft{
+			Value: platform.Money{
+				CurrencyCode: input["currency_code"].(string),
+				CentAmount:   input["cent_amount"].(int),
+			},
+		}
+		if val := input["country"].(string); val != "" {
+			price.Country = &val
+		}
+		if val := input["customer_group_id"].(string); val != "" {
+			price.CustomerGroup = &platform.CustomerGroupResourceIdentifier{ID: &val}
+		}
+		if val := input["channel_id"].(string); val != "" {
+			price.Channel = &platform.ChannelResourceIdentifier{ID: &val}
+		}
+		result = append(result, price)
+	}
+	return result
+}
+
+func unmarshallProductCategories(val interface{}) []platform.CategoryResourceIdentifier {
+	result := []platform.CategoryResourceIdentifier{}
+	for _, id := range expandStringArray(val.(*schema.Set).List()) {
+		id := id
+		result = append(result, platform.CategoryResourceIdentifier{ID: &id})
+	}
+	return result
+}
+
+func marshallProductCategories(categories []platform.CategoryReference) []string {
+	result := make([]string, len(categories))
+	for i, category := range categories {
+		result[i] = category.ID
+	}
+	return result
+}
+
+func marshallProductVariants(variants []platform.ProductVariant) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(variants))
+	for i, variant := range variants {
+		result[i] = marshallProductVariant(variant)
+	}
+	return result
+}
+
+func marshallProductVariant(variant platform.ProductVariant) map[string]interface{} {
+	attributes := make(map[string]interface{}, len(variant.Attributes))
+	for _, attribute := range variant.Attributes {
+		attributes[attribute.Name] = marshallCustomFieldValue(attribute.Value)
+	}
+
+	prices := make([]map[string]interface{}, len(variant.Prices))
+	for i, price := range variant.Prices {
+		prices[i] = marshallProductPrice(price)
+	}
+
+	result := map[string]interface{}{
+		"id":         variant.ID,
+		"sku":        "",
+		"key":        "",
+		"attributes": attributes,
+		"price":      prices,
+	}
+	if variant.Sku != nil {
+		result["sku"] = *variant.Sku
+	}
+	if variant.Key != nil {
+		result["key"] = *variant.Key
+	}
+	return result
+}
+
+func marshallProductPrice(price platform.Price) map[string]interface{} {
+	result := marshallTypedMoney(price.Value)
+	result["country"] = ""
+	result["customer_group_id"] = ""
+	result["channel_id"] = ""
+	if price.Country != nil {
+		result["country"] = *price.Country
+	}
+	if price.CustomerGroup != nil {
+		result["customer_group_id"] = price.CustomerGroup.ID
+	}
+	if price.Channel != nil {
+		result["channel_id"] = price.Channel.ID
+	}
+	return result
+}
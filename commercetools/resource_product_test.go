@@ -0,0 +1,227 @@
+package commercetools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildProductUpdateActions(t *testing.T) {
+	old := map[string]interface{}{
+		"product_type_id": "product-type-1",
+		"name":            map[string]interface{}{"en": "Shoe"},
+		"slug":            map[string]interface{}{"en": "shoe"},
+		"categories":      []interface{}{"category-1"},
+		"master_variant": []interface{}{map[string]interface{}{
+			"sku":        "shoe-1",
+			"attributes": map[string]interface{}{"color": "red", "size": "42"},
+			"price": []interface{}{map[string]interface{}{
+				"currency_code": "EUR",
+				"cent_amount":   5000,
+			}},
+		}},
+		"variants": []interface{}{map[string]interface{}{
+			"sku": "shoe-2",
+		}},
+	}
+	new := map[string]interface{}{
+		"product_type_id": "product-type-1",
+		"name":            map[string]interface{}{"en": "Running shoe"},
+		"slug":            map[string]interface{}{"en": "shoe"},
+		"categories":      []interface{}{"category-2"},
+		"master_variant": []interface{}{map[string]interface{}{
+			"sku":        "shoe-1",
+			"attributes": map[string]interface{}{"color": "blue"},
+			"price": []interface{}{map[string]interface{}{
+				"currency_code": "EUR",
+				"cent_amount":   4500,
+			}},
+		}},
+		"variants": []interface{}{map[string]interface{}{
+			"sku": "shoe-3",
+			"key": "shoe-3",
+		}},
+		"publish": true,
+	}
+
+	d := testResourceDataChange(t, resourceProduct().Schema, old, new)
+	sku1, sku2, sku3, key3 := "shoe-1", "shoe-2", "shoe-3", "shoe-3"
+	category1, category2 := "category-1", "category-2"
+	staged := boolRef(true)
+	assert.Equal(t, []platform.ProductUpdateAction{
+		&platform.ProductChangeNameAction{Name: platform.LocalizedString{"en": "Running shoe"}, Staged: staged},
+		&platform.ProductRemoveFromCategoryAction{
+			Category: platform.CategoryResourceIdentifier{ID: &category1}, Staged: staged},
+		&platform.ProductAddToCategoryAction{
+			Category: platform.CategoryResourceIdentifier{ID: &category2}, Staged: staged},
+		&platform.ProductAddVariantAction{
+			Sku: &sku3, Key: &key3, Prices: []platform.PriceDraft{}, Attributes: []platform.Attribute{}, Staged: staged},
+		&platform.ProductRemoveVariantAction{Sku: &sku2, Staged: staged},
+		&platform.ProductSetAttributeAction{Sku: &sku1, Name: "color", Value: "blue", Staged: staged},
+		&platform.ProductSetAttributeAction{Sku: &sku1, Name: "size", Staged: staged},
+		&platform.ProductSetPricesAction{
+			Sku: &sku1,
+			Prices: []platform.PriceDraft{
+				{Value: platform.Money{CurrencyCode: "EUR", CentAmount: 4500}},
+			},
+			Staged: staged,
+		},
+		&platform.ProductPublishAction{},
+	}, buildProductUpdateActions(d))
+}
+
+func TestBuildProductUpdateActionsMasterVariant(t *testing.T) {
+	old := map[string]interface{}{
+		"product_type_id": "product-type-1",
+		"name":            map[string]interface{}{"en": "Shoe"},
+		"slug":            map[string]interface{}{"en": "shoe"},
+		"master_variant":  []interface{}{map[string]interface{}{"sku": "shoe-1"}},
+		"variants":        []interface{}{map[string]interface{}{"sku": "shoe-2"}},
+		"publish":         true,
+	}
+	new := map[string]interface{}{
+		"product_type_id": "product-type-1",
+		"name":            map[string]interface{}{"en": "Shoe"},
+		"slug":            map[string]interface{}{"en": "shoe"},
+		"master_variant":  []interface{}{map[string]interface{}{"sku": "shoe-2"}},
+		"publish":         false,
+	}
+
+	d := testResourceDataChange(t, resourceProduct().Schema, old, new)
+	sku1, sku2 := "shoe-1", "shoe-2"
+	staged := boolRef(true)
+	assert.Equal(t, []platform.ProductUpdateAction{
+		&platform.ProductChangeMasterVariantAction{Sku: &sku2, Staged: staged},
+		&platform.ProductRemoveVariantAction{Sku: &sku1, Staged: staged},
+		&platform.ProductUnpublishAction{},
+	}, buildProductUpdateActions(d))
+}
+
+func TestMarshallProductVariant(t *testing.T) {
+	sku, country := "shoe-1", "NL"
+	result := marshallProductVariant(platform.ProductVariant{
+		ID:  1,
+		Sku: &sku,
+		Attributes: []platform.Attribute{
+			{Name: "color", Value: "red"},
+			{Name: "size", Value: 42.0},
+		},
+		Prices: []platform.Price{{
+			Value:   platform.CentPrecisionMoney{CurrencyCode: "EUR", CentAmount: 5000},
+			Country: &country,
+			Channel: &platform.ChannelReference{ID: "channel-1"},
+		}},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"id":         1,
+		"sku":        "shoe-1",
+		"key":        "",
+		"attributes": map[string]interface{}{"color": "red", "size": "42"},
+		"price": []map[string]interface{}{{
+			"currency_code":     "EUR",
+			"cent_amount":       5000,
+			"country":           "NL",
+			"customer_group_id": "",
+			"channel_id":        "channel-1",
+		}},
+	}, result)
+}
+
+func TestAccProduct_basic(t *testing.T) {
+	key := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckProductDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProductConfig(key, "Shoe", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("commercetools_product.shoe", "name.en", "Shoe"),
+					resource.TestCheckResourceAttr("commercetools_product.shoe", "master_variant.0.sku", key+"-1"),
+					resource.TestCheckResourceAttr("commercetools_product.shoe", "master_variant.0.attributes.color", "red"),
+					resource.TestCheckResourceAttr("commercetools_product.shoe", "publish", "false"),
+				),
+			},
+			{
+				Config: testAccProductConfig(key, "Running shoe", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("commercetools_product.shoe", "name.en", "Running shoe"),
+					resource.TestCheckResourceAttr("commercetools_product.shoe", "publish", "true"),
+					resource.TestCheckResourceAttr("commercetools_product.shoe", "has_staged_changes", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccProductConfig(key, name string, publish bool) string {
+	return fmt.Sprintf(`
+resource "commercetools_product_type" "shoe" {
+	key  = "%[1]s"
+	name = "Shoe"
+
+	attribute {
+		name = "color"
+		label = {
+			en = "Color"
+		}
+		type {
+			name = "text"
+		}
+	}
+}
+
+resource "commercetools_product" "shoe" {
+	key             = "%[1]s"
+	product_type_id = commercetools_product_type.shoe.id
+	name = {
+		en = "%[2]s"
+	}
+	slug = {
+		en = "%[1]s"
+	}
+	publish = %[3]t
+
+	master_variant {
+		sku = "%[1]s-1"
+		attributes = {
+			color = "red"
+		}
+		price {
+			currency_code = "EUR"
+			cent_amount   = 5000
+		}
+	}
+}
+`, key, name, publish)
+}
+
+func testAccCheckProductDestroy(s *terraform.State) error {
+	client := getClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "commercetools_product" {
+			continue
+		}
+		response, err := client.Products().WithId(rs.Primary.ID).Get().Execute(context.Background())
+		if err == nil {
+			if response != nil && response.ID == rs.Primary.ID {
+				return fmt.Errorf("product (%s) still exists", rs.Primary.ID)
+			}
+			return nil
+		}
+		if newErr := checkApiResult(err); newErr != nil {
+			return newErr
+		}
+	}
+	return nil
+}
@@ -75,7 +75,7 @@ func resourceProductType() *schema.Resource {
 						"label": {
 							Description:      "A human-readable label for the attribute",
 							Type:             TypeLocalizedString,
-							ValidateDiagFunc: validateLocalizedStringKey,
+							ValidateDiagFunc: validateLocalizedString,
 							Required:         true,
 						},
 						"required": {
@@ -109,7 +109,7 @@ func resourceProductType() *schema.Resource {
 							Description: "Additional information about the attribute that aids content managers " +
 								"when setting product details",
 							Type:             TypeLocalizedString,
-							ValidateDiagFunc: validateLocalizedStringKey,
+							ValidateDiagFunc: validateLocalizedString,
 							Optional:         true,
 						},
 						"input_hint": {
@@ -225,7 +225,7 @@ func resourceProductTypeCreate(ctx context.Context, d *schema.ResourceData, m in
 	attributes, err := resourceProductTypeGetAttributeDefinitions(d)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	draft := platform.ProductTypeDraft{
@@ -235,7 +235,7 @@ func resourceProductTypeCreate(ctx context.Context, d *schema.ResourceData, m in
 		Attributes:  attributes,
 	}
 
-	err = resource.RetryContext(ctx, 1*time.Minute, func() *resource.RetryError {
+	err = retryContext(ctx, m, "create product type", 1*time.Minute, func() *resource.RetryError {
 		var err error
 
 		ctType, err = client.ProductTypes().Post(draft).Execute(ctx)
@@ -246,7 +246,7 @@ func resourceProductTypeCreate(ctx context.Context, d *schema.ResourceData, m in
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if ctType == nil {
@@ -272,7 +272,7 @@ func resourceProductTypeRead(ctx context.Context, d *schema.ResourceData, m inte
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if ctType == nil {
@@ -288,7 +288,7 @@ func resourceProductTypeRead(ctx context.Context, d *schema.ResourceData, m inte
 			log.Printf("[DEBUG] reading field: %s: %#v", fieldDef.Name, fieldDef)
 			fieldType, err := resourceProductTypeReadAttributeType(fieldDef.Type, true)
 			if err != nil {
-				return diag.FromErr(err)
+				return diagnosticsFromError(err)
 			}
 
 			fieldData["type"] = fieldType
@@ -312,7 +312,7 @@ func resourceProductTypeRead(ctx context.Context, d *schema.ResourceData, m inte
 		d.Set("description", ctType.Description)
 		err = d.Set("attribute", attributes)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 	}
 	return nil
@@ -403,7 +403,7 @@ func resourceProductTypeUpdate(ctx context.Context, d *schema.ResourceData, m in
 		attributeChangeActions, err := resourceProductTypeAttributeChangeActions(
 			old.([]interface{}), new.([]interface{}))
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 
 		input.Actions = append(input.Actions, attributeChangeActions...)
@@ -418,7 +418,7 @@ func resourceProductTypeUpdate(ctx context.Context, d *schema.ResourceData, m in
 		if ctErr, ok := err.(platform.ErrorResponse); ok {
 			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceProductTypeRead(ctx, d, m)
@@ -429,7 +429,7 @@ func resourceProductTypeDelete(ctx context.Context, d *schema.ResourceData, m in
 	version := d.Get("version").(int)
 	_, err := client.ProductTypes().WithId(d.Id()).Delete().Version(version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
@@ -159,7 +159,7 @@ func resourceProjectSettings() *schema.Resource {
 						},
 						"label": {
 							Type:             TypeLocalizedString,
-							ValidateDiagFunc: validateLocalizedStringKey,
+							ValidateDiagFunc: validateLocalizedString,
 							Optional:         true,
 						},
 					},
@@ -198,7 +198,7 @@ func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interf
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	diags := projectUpdate(ctx, d, client, project.Version)
@@ -220,7 +220,7 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	log.Print("[DEBUG] Found the following project:")
@@ -291,7 +291,7 @@ func projectUpdate(ctx context.Context, d *schema.ResourceData, client *platform
 	if d.HasChange("messages") {
 		messages, err := elementFromList(d, "messages")
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		if messages["enabled"] != nil {
 			input.Actions = append(
@@ -309,7 +309,7 @@ func projectUpdate(ctx context.Context, d *schema.ResourceData, client *platform
 	if d.HasChange("shipping_rate_input_type") || d.HasChange("shipping_rate_cart_classification_value") {
 		newShippingRateInputType, err := getShippingRateInputType(d)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		input.Actions = append(
 			input.Actions,
@@ -319,7 +319,7 @@ func projectUpdate(ctx context.Context, d *schema.ResourceData, client *platform
 	if d.HasChange("external_oauth") {
 		externalOAuth, err := elementFromList(d, "external_oauth")
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		if externalOAuth["url"] != nil && externalOAuth["authorization_header"] != nil {
 			newExternalOAuth := platform.ExternalOAuth{
@@ -358,7 +358,7 @@ func projectUpdate(ctx context.Context, d *schema.ResourceData, client *platform
 	if d.HasChange("carts") {
 		carts, err := elementFromList(d, "carts")
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		fallbackEnabled := false
 		if carts["country_tax_rate_fallback_enabled"] != nil {
@@ -382,7 +382,7 @@ func projectUpdate(ctx context.Context, d *schema.ResourceData, client *platform
 	}
 
 	_, err := client.Post(input).Execute(ctx)
-	return diag.FromErr(err)
+	return diagnosticsFromError(err)
 }
 
 func getStringSlice(d *schema.ResourceData, field string) []string {
@@ -587,7 +587,7 @@ func resourceProjectSettingsResourceV0() *schema.Resource {
 						},
 						"label": {
 							Type:             TypeLocalizedString,
-							ValidateDiagFunc: validateLocalizedStringKey,
+							ValidateDiagFunc: validateLocalizedString,
 							Optional:         true,
 						},
 					},
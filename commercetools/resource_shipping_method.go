@@ -40,7 +40,7 @@ func resourceShippingMethod() *schema.Resource {
 			"localized_description": {
 				Description:      "[LocalizedString](https://docs.commercetoolstools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"is_default": {
@@ -58,9 +58,10 @@ func resourceShippingMethod() *schema.Resource {
 				Optional:    true,
 			},
 			"predicate": {
-				Description: "A Cart predicate which can be used to more precisely select a shipping method for a cart",
-				Type:        schema.TypeString,
-				Optional:    true,
+				Description:      "A Cart predicate which can be used to more precisely select a shipping method for a cart",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressPredicate,
 			},
 		},
 	}
@@ -86,7 +87,7 @@ func resourceShippingMethodCreate(ctx context.Context, d *schema.ResourceData, m
 		Predicate:            stringRef(d.Get("predicate")),
 	}
 
-	err := resource.RetryContext(ctx, 1*time.Minute, func() *resource.RetryError {
+	err := retryContext(ctx, m, "create shipping method", 1*time.Minute, func() *resource.RetryError {
 		var err error
 
 		shippingMethod, err = client.ShippingMethods().Post(draft).Execute(ctx)
@@ -97,7 +98,7 @@ func resourceShippingMethodCreate(ctx context.Context, d *schema.ResourceData, m
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if shippingMethod == nil {
@@ -124,7 +125,7 @@ func resourceShippingMethodRead(ctx context.Context, d *schema.ResourceData, m i
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if shippingMethod == nil {
@@ -154,7 +155,7 @@ func resourceShippingMethodUpdate(ctx context.Context, d *schema.ResourceData, m
 	client := getClient(m)
 	shippingMethod, err := client.ShippingMethods().WithId(d.Id()).Get().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input := platform.ShippingMethodUpdate{
@@ -220,7 +221,7 @@ func resourceShippingMethodUpdate(ctx context.Context, d *schema.ResourceData, m
 		if ctErr, ok := err.(platform.ErrorResponse); ok {
 			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceShippingMethodRead(ctx, d, m)
@@ -234,12 +235,12 @@ func resourceShippingMethodDelete(ctx context.Context, d *schema.ResourceData, m
 
 	shippingMethod, err := client.ShippingMethods().WithId(d.Id()).Get().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	_, err = client.ShippingMethods().WithId(d.Id()).Delete().Version(shippingMethod.Version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
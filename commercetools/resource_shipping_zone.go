@@ -77,7 +77,7 @@ func resourceShippingZoneCreate(ctx context.Context, d *schema.ResourceData, m i
 		Locations:   locations,
 	}
 
-	err := resource.RetryContext(ctx, 1*time.Minute, func() *resource.RetryError {
+	err := retryContext(ctx, m, "create shipping zone", 1*time.Minute, func() *resource.RetryError {
 		var err error
 
 		shippingZone, err = client.Zones().Post(draft).Execute(ctx)
@@ -88,7 +88,7 @@ func resourceShippingZoneCreate(ctx context.Context, d *schema.ResourceData, m i
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if shippingZone == nil {
@@ -114,7 +114,7 @@ func resourceShippingZoneRead(ctx context.Context, d *schema.ResourceData, m int
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if shippingZone == nil {
@@ -188,7 +188,7 @@ func resourceShippingZoneUpdate(ctx context.Context, d *schema.ResourceData, m i
 
 	_, err := client.Zones().WithId(d.Id()).Post(input).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceShippingZoneRead(ctx, d, m)
@@ -203,7 +203,7 @@ func resourceShippingZoneDelete(ctx context.Context, d *schema.ResourceData, m i
 
 	version := d.Get("version").(int)
 	_, err := client.Zones().WithId(d.Id()).Delete().Version(version).Execute(ctx)
-	return diag.FromErr(err)
+	return diagnosticsFromError(err)
 }
 
 func unmarshallShippingZoneLocations(input interface{}) []platform.Location {
@@ -178,7 +178,7 @@ func resourceShippingZoneRateCreate(ctx context.Context, d *schema.ResourceData,
 	shippingMethod, err := client.ShippingMethods().WithId(shippingMethodID).Get().Execute(ctx)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input := platform.ShippingMethodUpdate{
@@ -200,7 +200,7 @@ func resourceShippingZoneRateCreate(ctx context.Context, d *schema.ResourceData,
 
 	shippingRatePriceTiers, err := unmarshallShippingRatePriceTiers(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 	log.Printf("[DEBUG] Setting shippingRatePriceTiers: %s", stringFormatObject(shippingRatePriceTiers))
 
@@ -232,7 +232,7 @@ func resourceShippingZoneRateCreate(ctx context.Context, d *schema.ResourceData,
 		},
 	})
 
-	err = resource.RetryContext(ctx, 1*time.Minute, func() *resource.RetryError {
+	err = retryContext(ctx, m, "create shipping zone rate", 1*time.Minute, func() *resource.RetryError {
 		var err error
 
 		shippingMethod, err = client.ShippingMethods().WithId(shippingMethod.ID).Post(input).Execute(ctx)
@@ -243,7 +243,7 @@ func resourceShippingZoneRateCreate(ctx context.Context, d *schema.ResourceData,
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if shippingMethod == nil {
@@ -316,7 +316,7 @@ func resourceShippingZoneRateRead(ctx context.Context, d *schema.ResourceData, m
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if shippingMethod == nil {
@@ -328,7 +328,7 @@ func resourceShippingZoneRateRead(ctx context.Context, d *schema.ResourceData, m
 
 		err = setShippingZoneRateState(d, shippingMethod)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 	}
 
@@ -343,13 +343,13 @@ func resourceShippingZoneRateUpdate(ctx context.Context, d *schema.ResourceData,
 	client := getClient(m)
 	shippingMethod, err := client.ShippingMethods().WithId(shippingMethodID).Get().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	shippingRate, err := findShippingZoneRate(shippingZoneID, currencyCode, shippingMethod)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input := platform.ShippingMethodUpdate{
@@ -397,7 +397,7 @@ func resourceShippingZoneRateUpdate(ctx context.Context, d *schema.ResourceData,
 
 		newShippingRatePriceTiers, err := unmarshallShippingRatePriceTiers(d)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 
 		newShippingRateDraft := platform.ShippingRateDraft{
@@ -434,7 +434,7 @@ func resourceShippingZoneRateUpdate(ctx context.Context, d *schema.ResourceData,
 		if ctErr, ok := err.(platform.ErrorResponse); ok {
 			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceShippingZoneRateRead(ctx, d, m)
@@ -448,7 +448,7 @@ func resourceShippingZoneRateDelete(ctx context.Context, d *schema.ResourceData,
 	client := getClient(m)
 	shippingMethod, err := client.ShippingMethods().WithId(shippingMethodID).Get().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input := platform.ShippingMethodUpdate{
@@ -468,7 +468,7 @@ func resourceShippingZoneRateDelete(ctx context.Context, d *schema.ResourceData,
 
 	newShippingRatePriceTiers, err := unmarshallShippingRatePriceTiers(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	shippingZoneID := d.Get("shipping_zone_id").(string)
@@ -497,7 +497,7 @@ func resourceShippingZoneRateDelete(ctx context.Context, d *schema.ResourceData,
 
 	_, err = client.ShippingMethods().WithId(shippingMethodID).Post(input).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
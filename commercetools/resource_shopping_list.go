@@ -0,0 +1,508 @@
+package commercetools
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/labd/commercetools-go-sdk/platform"
+)
+
+func resourceShoppingList() *schema.Resource {
+	return &schema.Resource{
+		Description: "Shopping lists hold line items of products in the platform in general. They can be used for " +
+			"wishlists or to save items for later use.\n\n" +
+			"See also the [Shopping List API Documentation](https://docs.commercetools.com/api/projects/shoppingLists)",
+		CreateContext: resourceShoppingListCreate,
+		ReadContext:   resourceShoppingListRead,
+		UpdateContext: resourceShoppingListUpdate,
+		DeleteContext: resourceShoppingListDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceShoppingListImportState,
+		},
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Description: "User-specific unique identifier for the shopping list",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"name": {
+				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+				Type:             TypeLocalizedString,
+				ValidateDiagFunc: validateLocalizedString,
+				Required:         true,
+			},
+			"description": {
+				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+				Type:             TypeLocalizedString,
+				ValidateDiagFunc: validateLocalizedString,
+				Optional:         true,
+			},
+			"customer": {
+				Description: "The ID of the customer the shopping list belongs to",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"line_items": {
+				Description: "Array of [ShoppingListLineItem](https://docs.commercetools.com/api/projects/shoppingLists#shoppinglistlineitem)",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"product_id": {
+							Description: "The ID of the product, required when no sku is given",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"variant_id": {
+							Description: "The ID of the product variant, defaults to the master variant",
+							Type:        schema.TypeInt,
+							Optional:    true,
+						},
+						"sku": {
+							Description: "The SKU of the product variant, can be used instead of product_id and variant_id",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"quantity": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  1,
+						},
+					},
+				},
+			},
+			"text_line_items": {
+				Description: "Array of [TextLineItem](https://docs.commercetools.com/api/projects/shoppingLists#textlineitem)",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+							Type:             TypeLocalizedString,
+							ValidateDiagFunc: validateLocalizedString,
+							Required:         true,
+						},
+						"description": {
+							Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
+							Type:             TypeLocalizedString,
+							ValidateDiagFunc: validateLocalizedString,
+							Optional:         true,
+						},
+						"quantity": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  1,
+						},
+					},
+				},
+			},
+			"custom": customFieldSchema(),
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceShoppingListCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	var shoppingList *platform.ShoppingList
+
+	name := unmarshallLocalizedString(d.Get("name"))
+	description := unmarshallLocalizedString(d.Get("description"))
+
+	custom, err := unmarshallCustomFieldsDraft(d)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	draft := platform.ShoppingListDraft{
+		Key:           stringRef(d.Get("key")),
+		Name:          name,
+		Description:   &description,
+		LineItems:     unmarshallShoppingListLineItems(d.Get("line_items").([]interface{})),
+		TextLineItems: unmarshallShoppingListTextLineItems(d.Get("text_line_items").([]interface{})),
+		Custom:        custom,
+	}
+
+	if val := d.Get("customer").(string); len(val) > 0 {
+		draft.Customer = &platform.CustomerResourceIdentifier{ID: &val}
+	}
+
+	errorResponse := retryContext(ctx, m, "create shopping list", 1*time.Minute, func() *resource.RetryError {
+		var err error
+
+		shoppingList, err = client.ShoppingLists().Post(draft).Execute(ctx)
+
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+
+	if errorResponse != nil {
+		return diagnosticsFromError(errorResponse)
+	}
+
+	if shoppingList == nil {
+		return diag.Errorf("No shopping list created")
+	}
+
+	d.SetId(shoppingList.ID)
+	d.Set("version", shoppingList.Version)
+
+	return resourceShoppingListRead(ctx, d, m)
+}
+
+func resourceShoppingListRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading shopping list from commercetools, with shopping list id: %s", d.Id())
+
+	client := getClient(m)
+
+	shoppingList, err := client.ShoppingLists().WithId(d.Id()).Get().Execute(ctx)
+
+	if err != nil {
+		if isNotFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return diagnosticsFromError(err)
+	}
+
+	if shoppingList == nil {
+		log.Print("[DEBUG] No shopping list found")
+		d.SetId("")
+	} else {
+		log.Print("[DEBUG] Found following shopping list:")
+		log.Print(stringFormatObject(shoppingList))
+
+		d.Set("version", shoppingList.Version)
+		d.Set("key", shoppingList.Key)
+		d.Set("name", shoppingList.Name)
+		d.Set("description", shoppingList.Description)
+		if shoppingList.Customer != nil {
+			d.Set("customer", shoppingList.Customer.ID)
+		} else {
+			d.Set("customer", "")
+		}
+		d.Set("line_items", marshallShoppingListLineItems(shoppingList.LineItems, d.Get("line_items").([]interface{})))
+		d.Set("text_line_items", marshallShoppingListTextLineItems(shoppingList.TextLineItems))
+		d.Set("custom", marshallCustomFields(shoppingList.Custom))
+	}
+
+	return nil
+}
+
+func resourceShoppingListUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	shoppingList, err := client.ShoppingLists().WithId(d.Id()).Get().Execute(ctx)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+
+	input := platform.ShoppingListUpdate{
+		Version: shoppingList.Version,
+		Actions: []platform.ShoppingListUpdateAction{},
+	}
+
+	if d.HasChange("key") {
+		newKey := d.Get("key").(string)
+		input.Actions = append(
+			input.Actions,
+			&platform.ShoppingListSetKeyAction{Key: &newKey})
+	}
+
+	if d.HasChange("name") {
+		newName := unmarshallLocalizedString(d.Get("name"))
+		input.Actions = append(
+			input.Actions,
+			&platform.ShoppingListChangeNameAction{Name: newName})
+	}
+
+	if d.HasChange("description") {
+		newDescription := unmarshallLocalizedString(d.Get("description"))
+		input.Actions = append(
+			input.Actions,
+			&platform.ShoppingListSetDescriptionAction{Description: &newDescription})
+	}
+
+	if d.HasChange("customer") {
+		action := &platform.ShoppingListSetCustomerAction{}
+		if val := d.Get("customer").(string); len(val) > 0 {
+			action.Customer = &platform.CustomerResourceIdentifier{ID: &val}
+		}
+		input.Actions = append(input.Actions, action)
+	}
+
+	if d.HasChange("line_items") {
+		old, new := d.GetChange("line_items")
+		input.Actions = append(
+			input.Actions,
+			resourceShoppingListLineItemActions(old.([]interface{}), new.([]interface{}))...)
+	}
+
+	if d.HasChange("text_line_items") {
+		old, new := d.GetChange("text_line_items")
+		input.Actions = append(
+			input.Actions,
+			resourceShoppingListTextLineItemActions(old.([]interface{}), new.([]interface{}))...)
+	}
+
+	if d.HasChange("custom") {
+		action := &platform.ShoppingListSetCustomTypeAction{}
+		custom, err := unmarshallCustomFieldsDraft(d)
+		if err != nil {
+			return diagnosticsFromError(err)
+		}
+		if custom != nil {
+			action.Type = &custom.Type
+			action.Fields = custom.Fields
+		}
+		input.Actions = append(input.Actions, action)
+	}
+
+	log.Printf(
+		"[DEBUG] Will perform update operation with the following actions:\n%s",
+		stringFormatActions(input.Actions))
+
+	_, err = client.ShoppingLists().WithId(shoppingList.ID).Post(input).Execute(ctx)
+	if err != nil {
+		if ctErr, ok := err.(platform.ErrorResponse); ok {
+			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
+		}
+		return diagnosticsFromError(err)
+	}
+
+	return resourceShoppingListRead(ctx, d, m)
+}
+
+func resourceShoppingListDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getClient(m)
+	version := d.Get("version").(int)
+	_, err := client.ShoppingLists().WithId(d.Id()).Delete().Version(version).Execute(ctx)
+	if err != nil {
+		return diagnosticsFromError(err)
+	}
+	return nil
+}
+
+// resourceShoppingListImportState allows importing a shopping list by either
+// its ID or its key. The ID is tried first, when no shopping list is found we
+// fall back to the key.
+func resourceShoppingListImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := getClient(m)
+	value := d.Id()
+
+	shoppingList, err := client.ShoppingLists().WithId(value).Get().Execute(ctx)
+	if err != nil && isNotFoundError(err) {
+		shoppingList, err = client.ShoppingLists().WithKey(value).Get().Execute(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(shoppingList.ID)
+	return []*schema.ResourceData{d}, nil
+}
+
+func unmarshallShoppingListLineItem(input map[string]interface{}) platform.ShoppingListLineItemDraft {
+	draft := platform.ShoppingListLineItemDraft{
+		Quantity: intRef(input["quantity"]),
+	}
+	if val := input["product_id"].(string); len(val) > 0 {
+		draft.ProductId = &val
+	}
+	if val := input["variant_id"].(int); val > 0 {
+		draft.VariantId = &val
+	}
+	if val := input["sku"].(string); len(val) > 0 {
+		draft.Sku = &val
+	}
+	return draft
+}
+
+func unmarshallShoppingListLineItems(input []interface{}) []platform.ShoppingListLineItemDraft {
+	result := make([]platform.ShoppingListLineItemDraft, len(input))
+	for i := range input {
+		result[i] = unmarshallShoppingListLineItem(input[i].(map[string]interface{}))
+	}
+	return result
+}
+
+func unmarshallShoppingListTextLineItem(input map[string]interface{}) platform.TextLineItemDraft {
+	description := unmarshallLocalizedString(input["description"])
+	return platform.TextLineItemDraft{
+		Name:        unmarshallLocalizedString(input["name"]),
+		Description: &description,
+		Quantity:    intRef(input["quantity"]),
+	}
+}
+
+func unmarshallShoppingListTextLineItems(input []interface{}) []platform.TextLineItemDraft {
+	result := make([]platform.TextLineItemDraft, len(input))
+	for i := range input {
+		result[i] = unmarshallShoppingListTextLineItem(input[i].(map[string]interface{}))
+	}
+	return result
+}
+
+// marshallShoppingListLineItems converts the line items to the terraform
+// state. The sku is not returned by commercetools when the variant is not
+// expanded, so when a line item was added by sku the values are copied from
+// the current state. A line item added without a variant_id gets the master
+// variant, but the variant_id is kept empty so it matches the configuration.
+func marshallShoppingListLineItems(values []platform.ShoppingListLineItem, current []interface{}) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(values))
+	for i, item := range values {
+		result[i] = map[string]interface{}{
+			"id":         item.ID,
+			"product_id": item.ProductId,
+			"quantity":   item.Quantity,
+		}
+		if item.VariantId != nil {
+			result[i]["variant_id"] = *item.VariantId
+		}
+		if i < len(current) {
+			c, ok := current[i].(map[string]interface{})
+			if ok && c["variant_id"] == 0 && c["product_id"] == item.ProductId {
+				result[i]["variant_id"] = 0
+			}
+			if ok && c["sku"] != "" {
+				result[i]["sku"] = c["sku"]
+				result[i]["product_id"] = c["product_id"]
+				result[i]["variant_id"] = c["variant_id"]
+			}
+		}
+	}
+	return result
+}
+
+func marshallShoppingListTextLineItems(values []platform.TextLineItem) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(values))
+	for i, item := range values {
+		result[i] = map[string]interface{}{
+			"id":       item.ID,
+			"name":     item.Name,
+			"quantity": item.Quantity,
+		}
+		if item.Description != nil {
+			result[i]["description"] = *item.Description
+		}
+	}
+	return result
+}
+
+// resourceShoppingListLineItemActions compares the line items by position. If
+// the same product is referenced only the quantity is changed, otherwise the
+// old line item is removed and the new one added. A line item without a
+// variant_id references any variant of the product, since it is not known
+// which variant is the master variant.
+func resourceShoppingListLineItemActions(old, new []interface{}) []platform.ShoppingListUpdateAction {
+	actions := []platform.ShoppingListUpdateAction{}
+
+	for i := range old {
+		o := old[i].(map[string]interface{})
+		id := o["id"].(string)
+
+		if i >= len(new) {
+			actions = append(actions, &platform.ShoppingListRemoveLineItemAction{LineItemId: id})
+			continue
+		}
+
+		n := new[i].(map[string]interface{})
+		sameVariant := n["variant_id"] == 0 || o["variant_id"] == n["variant_id"]
+		if o["product_id"] == n["product_id"] && sameVariant && o["sku"] == n["sku"] {
+			if o["quantity"] != n["quantity"] {
+				actions = append(actions, &platform.ShoppingListChangeLineItemQuantityAction{
+					LineItemId: id,
+					Quantity:   n["quantity"].(int),
+				})
+			}
+			continue
+		}
+
+		draft := unmarshallShoppingListLineItem(n)
+		actions = append(
+			actions,
+			&platform.ShoppingListRemoveLineItemAction{LineItemId: id},
+			&platform.ShoppingListAddLineItemAction{
+				ProductId: draft.ProductId,
+				VariantId: draft.VariantId,
+				Sku:       draft.Sku,
+				Quantity:  draft.Quantity,
+			})
+	}
+
+	for i := len(old); i < len(new); i++ {
+		draft := unmarshallShoppingListLineItem(new[i].(map[string]interface{}))
+		actions = append(actions, &platform.ShoppingListAddLineItemAction{
+			ProductId: draft.ProductId,
+			VariantId: draft.VariantId,
+			Sku:       draft.Sku,
+			Quantity:  draft.Quantity,
+		})
+	}
+	return actions
+}
+
+// resourceShoppingListTextLineItemActions compares the text line items by
+// position and updates the name, description and quantity in place.
+func resourceShoppingListTextLineItemActions(old, new []interface{}) []platform.ShoppingListUpdateAction {
+	actions := []platform.ShoppingListUpdateAction{}
+
+	for i := range old {
+		o := old[i].(map[string]interface{})
+		id := o["id"].(string)
+
+		if i >= len(new) {
+			actions = append(actions, &platform.ShoppingListRemoveTextLineItemAction{TextLineItemId: id})
+			continue
+		}
+
+		n := new[i].(map[string]interface{})
+		draft := unmarshallShoppingListTextLineItem(n)
+		if !reflect.DeepEqual(o["name"], n["name"]) {
+			actions = append(actions, &platform.ShoppingListChangeTextLineItemNameAction{
+				TextLineItemId: id,
+				Name:           draft.Name,
+			})
+		}
+		if !reflect.DeepEqual(o["description"], n["description"]) {
+			actions = append(actions, &platform.ShoppingListSetTextLineItemDescriptionAction{
+				TextLineItemId: id,
+				Description:    draft.Description,
+			})
+		}
+		if o["quantity"] != n["quantity"] {
+			actions = append(actions, &platform.ShoppingListChangeTextLineItemQuantityAction{
+				TextLineItemId: id,
+				Quantity:       *draft.Quantity,
+			})
+		}
+	}
+
+	for i := len(old); i < len(new); i++ {
+		draft := unmarshallShoppingListTextLineItem(new[i].(map[string]interface{}))
+		actions = append(actions, &platform.ShoppingListAddTextLineItemAction{
+			Name:        draft.Name,
+			Description: draft.Description,
+			Quantity:    draft.Quantity,
+		})
+	}
+	return actions
+}
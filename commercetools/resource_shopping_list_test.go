@@ -0,0 +1,245 @@
+package commercetools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceShoppingListLineItemActions(t *testing.T) {
+	old := []interface{}{
+		map[string]interface{}{
+			"id":         "line-item-1",
+			"product_id": "product-1",
+			"variant_id": 1,
+			"sku":        "",
+			"quantity":   1,
+		},
+		map[string]interface{}{
+			"id":         "line-item-2",
+			"product_id": "product-2",
+			"variant_id": 1,
+			"sku":        "",
+			"quantity":   1,
+		},
+	}
+	new := []interface{}{
+		map[string]interface{}{
+			"id":         "line-item-1",
+			"product_id": "product-1",
+			"variant_id": 1,
+			"sku":        "",
+			"quantity":   3,
+		},
+		map[string]interface{}{
+			"id":         "line-item-2",
+			"product_id": "",
+			"variant_id": 0,
+			"sku":        "sku-3",
+			"quantity":   1,
+		},
+	}
+
+	actions := resourceShoppingListLineItemActions(old, new)
+	assert.Equal(t, []platform.ShoppingListUpdateAction{
+		&platform.ShoppingListChangeLineItemQuantityAction{
+			LineItemId: "line-item-1",
+			Quantity:   3,
+		},
+		&platform.ShoppingListRemoveLineItemAction{
+			LineItemId: "line-item-2",
+		},
+		&platform.ShoppingListAddLineItemAction{
+			Sku:      stringRef("sku-3"),
+			Quantity: intRef(1),
+		},
+	}, actions)
+
+	actions = resourceShoppingListLineItemActions(old, new[:1])
+	assert.Len(t, actions, 2)
+	assert.Equal(t, &platform.ShoppingListRemoveLineItemAction{LineItemId: "line-item-2"}, actions[1])
+}
+
+func TestResourceShoppingListLineItemWithoutVariant(t *testing.T) {
+	var list platform.ShoppingList
+	err := json.Unmarshal([]byte(`{
+		"id": "shopping-list-1",
+		"lineItems": [{"id": "line-item-1", "productId": "product-1", "variantId": 1, "quantity": 2}]
+	}`), &list)
+	assert.NoError(t, err)
+
+	// The master variant of commercetools is not stored when the variant_id
+	// is not configured
+	config := []interface{}{
+		map[string]interface{}{"id": "", "product_id": "product-1", "variant_id": 0, "sku": "", "quantity": 2},
+	}
+	state := marshallShoppingListLineItems(list.LineItems, config)
+	if assert.Len(t, state, 1) {
+		assert.Equal(t, 0, state[0]["variant_id"])
+		assert.Equal(t, "line-item-1", state[0]["id"])
+	}
+
+	// Imported line items have the variant_id, which the configuration
+	// without a variant_id still matches
+	imported := marshallShoppingListLineItems(list.LineItems, nil)
+	assert.Equal(t, 1, imported[0]["variant_id"])
+	old := []interface{}{map[string]interface{}{
+		"id": "line-item-1", "product_id": "product-1", "variant_id": 1, "sku": "", "quantity": 2,
+	}}
+	assert.Empty(t, resourceShoppingListLineItemActions(old, config))
+
+	config[0].(map[string]interface{})["quantity"] = 3
+	assert.Equal(t, []platform.ShoppingListUpdateAction{
+		&platform.ShoppingListChangeLineItemQuantityAction{LineItemId: "line-item-1", Quantity: 3},
+	}, resourceShoppingListLineItemActions(old, config))
+}
+
+func TestResourceShoppingListTextLineItemActions(t *testing.T) {
+	old := []interface{}{
+		map[string]interface{}{
+			"id":          "text-line-item-1",
+			"name":        map[string]interface{}{"en": "Name"},
+			"description": map[string]interface{}{},
+			"quantity":    1,
+		},
+	}
+	new := []interface{}{
+		map[string]interface{}{
+			"id":          "text-line-item-1",
+			"name":        map[string]interface{}{"en": "Name", "nl": "Naam"},
+			"description": map[string]interface{}{},
+			"quantity":    1,
+		},
+		map[string]interface{}{
+			"id":          "",
+			"name":        map[string]interface{}{"en": "Other"},
+			"description": map[string]interface{}{},
+			"quantity":    2,
+		},
+	}
+
+	actions := resourceShoppingListTextLineItemActions(old, new)
+	assert.Equal(t, []platform.ShoppingListUpdateAction{
+		&platform.ShoppingListChangeTextLineItemNameAction{
+			TextLineItemId: "text-line-item-1",
+			Name:           platform.LocalizedString{"en": "Name", "nl": "Naam"},
+		},
+		&platform.ShoppingListAddTextLineItemAction{
+			Name:        platform.LocalizedString{"en": "Other"},
+			Description: &platform.LocalizedString{},
+			Quantity:    intRef(2),
+		},
+	}, actions)
+}
+
+func TestAccShoppingList_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckShoppingListDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccShoppingListConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"commercetools_shopping_list.standard", "key", "standard",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_shopping_list.standard", "name.en", "Wishlist",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_shopping_list.standard", "text_line_items.#", "1",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_shopping_list.standard", "text_line_items.0.name.en", "Gift wrapping",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_shopping_list.standard", "text_line_items.0.quantity", "1",
+					),
+				),
+			},
+			{
+				Config: testAccShoppingListUpdate(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"commercetools_shopping_list.standard", "key", "standard-new",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_shopping_list.standard", "name.en", "Wishlist new",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_shopping_list.standard", "description.en", "Standard description",
+					),
+					resource.TestCheckResourceAttr(
+						"commercetools_shopping_list.standard", "text_line_items.0.quantity", "2",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccShoppingListConfig() string {
+	return `
+resource "commercetools_shopping_list" "standard" {
+	key = "standard"
+	name = {
+		en = "Wishlist"
+	}
+
+	text_line_items {
+		name = {
+			en = "Gift wrapping"
+		}
+	}
+}
+`
+}
+
+func testAccShoppingListUpdate() string {
+	return `
+resource "commercetools_shopping_list" "standard" {
+	key = "standard-new"
+	name = {
+		en = "Wishlist new"
+	}
+	description = {
+		en = "Standard description"
+	}
+
+	text_line_items {
+		name = {
+			en = "Gift wrapping"
+		}
+		quantity = 2
+	}
+}
+`
+}
+
+func testAccCheckShoppingListDestroy(s *terraform.State) error {
+	client := getClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "commercetools_shopping_list" {
+			continue
+		}
+		response, err := client.ShoppingLists().WithId(rs.Primary.ID).Get().Execute(context.Background())
+		if err == nil {
+			if response != nil && response.ID == rs.Primary.ID {
+				return fmt.Errorf("shopping list (%s) still exists", rs.Primary.ID)
+			}
+			return nil
+		}
+		if newErr := checkApiResult(err); newErr != nil {
+			return newErr
+		}
+	}
+	return nil
+}
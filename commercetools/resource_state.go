@@ -2,6 +2,8 @@ package commercetools
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -24,6 +26,7 @@ func resourceState() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: validateStateTransitions,
 		Schema: map[string]*schema.Schema{
 			"key": {
 				Description: "A unique identifier for the state",
@@ -49,13 +52,13 @@ func resourceState() *schema.Resource {
 			"name": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"description": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"initial": {
@@ -89,6 +92,13 @@ func resourceState() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"warn_unreachable": {
+				Description: "Warn when refreshing the state if it is not initial and no other state of the same " +
+					"type can transition to it, since such a state can never be reached",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
@@ -102,11 +112,10 @@ func resourceStateCreate(ctx context.Context, d *schema.ResourceData, m interfac
 		roles = append(roles, platform.StateRoleEnum(value))
 	}
 
-	var transitions []platform.StateResourceIdentifier
-	for _, value := range d.Get("transitions").(*schema.Set).List() {
-		transitions = append(transitions, platform.StateResourceIdentifier{
-			ID: stringRef(value),
-		})
+	client := getClient(m)
+	transitions, err := resolveStateTransitions(ctx, client, d.Get("transitions").(*schema.Set).List())
+	if err != nil {
+		return diagnosticsFromError(err)
 	}
 
 	draft := platform.StateDraft{
@@ -123,10 +132,9 @@ func resourceStateCreate(ctx context.Context, d *schema.ResourceData, m interfac
 		draft.Initial = boolRef(d.Get("initial"))
 	}
 
-	client := getClient(m)
 	var state *platform.State
 
-	err := resource.RetryContext(ctx, 20*time.Second, func() *resource.RetryError {
+	err = retryContext(ctx, m, "create state", 20*time.Second, func() *resource.RetryError {
 		var err error
 
 		state, err = client.States().Post(draft).Execute(ctx)
@@ -137,7 +145,7 @@ func resourceStateCreate(ctx context.Context, d *schema.ResourceData, m interfac
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	d.SetId(state.ID)
@@ -147,7 +155,7 @@ func resourceStateCreate(ctx context.Context, d *schema.ResourceData, m interfac
 
 func resourceStateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := getClient(m)
-	state, err := client.States().WithId(d.Id()).Get().Execute(ctx)
+	state, err := client.States().WithId(d.Id()).Get().Expand([]string{"transitions[*]"}).Execute(ctx)
 
 	if err != nil {
 		if ctErr, ok := err.(platform.ErrorResponse); ok {
@@ -156,7 +164,7 @@ func resourceStateRead(ctx context.Context, d *schema.ResourceData, m interface{
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	d.SetId(state.ID)
@@ -174,7 +182,12 @@ func resourceStateRead(ctx context.Context, d *schema.ResourceData, m interface{
 		d.Set("roles", state.Roles)
 	}
 	if state.Transitions != nil {
-		d.Set("transitions", marshallStateTransitions(state.Transitions))
+		configured := expandStringArray(d.Get("transitions").(*schema.Set).List())
+		d.Set("transitions", marshallStateTransitions(state.Transitions, configured))
+	}
+
+	if d.Get("warn_unreachable").(bool) {
+		return checkStateReachable(ctx, client, state)
 	}
 	return nil
 }
@@ -233,11 +246,9 @@ func resourceStateUpdate(ctx context.Context, d *schema.ResourceData, m interfac
 	}
 
 	if d.HasChange("transitions") {
-		var transitions []platform.StateResourceIdentifier
-		for _, value := range d.Get("transitions").(*schema.Set).List() {
-			transitions = append(transitions, platform.StateResourceIdentifier{
-				ID: stringRef(value),
-			})
+		transitions, err := resolveStateTransitions(ctx, client, d.Get("transitions").(*schema.Set).List())
+		if err != nil {
+			return diagnosticsFromError(err)
 		}
 		input.Actions = append(
 			input.Actions,
@@ -248,7 +259,7 @@ func resourceStateUpdate(ctx context.Context, d *schema.ResourceData, m interfac
 
 	_, err := client.States().WithId(d.Id()).Post(input).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceStateRead(ctx, d, m)
@@ -259,16 +270,114 @@ func resourceStateDelete(ctx context.Context, d *schema.ResourceData, m interfac
 	version := d.Get("version").(int)
 	_, err := client.States().WithId(d.Id()).Delete().Version(version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
 }
 
-func marshallStateTransitions(values []platform.StateReference) []string {
+// validateStateTransitions checks that every existing state referenced in
+// transitions has the same type as this state. States which don't exist yet
+// are skipped, they are usually created in the same apply, for example the
+// states of a new workflow which are referenced by key. A state which is
+// still missing fails the apply.
+func validateStateTransitions(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("transitions") || !d.NewValueKnown("transitions") || !d.NewValueKnown("type") {
+		return nil
+	}
+
+	client := getClient(m)
+	stateType := platform.StateTypeEnum(d.Get("type").(string))
+	for _, value := range expandStringArray(d.Get("transitions").(*schema.Set).List()) {
+		state, err := findStateByIDOrKey(ctx, client, value)
+		if err != nil {
+			if isNotFoundError(err) {
+				log.Printf("[DEBUG] State %s referenced in transitions does not exist yet, skipping its validation", value)
+				continue
+			}
+			return err
+		}
+		if state.Type != stateType {
+			return fmt.Errorf(
+				"state %q referenced in transitions has type %s, expected %s", value, state.Type, stateType)
+		}
+	}
+	return nil
+}
+
+// findStateByIDOrKey fetches the state with the given ID, when no such state
+// exists the value is used as key instead.
+func findStateByIDOrKey(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, value string) (*platform.State, error) {
+	state, err := client.States().WithId(value).Get().Execute(ctx)
+	if err != nil && isNotFoundError(err) {
+		state, err = client.States().WithKey(value).Get().Execute(ctx)
+	}
+	return state, err
+}
+
+// resolveStateTransitions converts the transitions, which are either IDs or
+// keys, to resource identifiers with the ID of the state.
+func resolveStateTransitions(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, values []interface{}) ([]platform.StateResourceIdentifier, error) {
+	var transitions []platform.StateResourceIdentifier
+	for _, value := range expandStringArray(values) {
+		state, err := findStateByIDOrKey(ctx, client, value)
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil, fmt.Errorf("state %q referenced in transitions does not exist", value)
+			}
+			return nil, err
+		}
+		transitions = append(transitions, platform.StateResourceIdentifier{
+			ID: &state.ID,
+		})
+	}
+	return transitions, nil
+}
+
+// marshallStateTransitions returns the transitions as they are configured.
+// A state referenced by key in the configuration is returned by key, all other
+// states by ID. The references need to be expanded to know the key.
+func marshallStateTransitions(values []platform.StateReference, configured []string) []string {
+	keys := make(map[string]bool, len(configured))
+	for _, value := range configured {
+		keys[value] = true
+	}
+
 	result := make([]string, len(values))
-	for i := range values {
-		result[i] = values[i].ID
+	for i, value := range values {
+		result[i] = value.ID
+		if !keys[value.ID] && value.Obj != nil && keys[value.Obj.Key] {
+			result[i] = value.Obj.Key
+		}
 	}
 	return result
 }
+
+// checkStateReachable returns a warning when the state is not initial and
+// no other state of the same type can transition to it. States without
+// transitions can transition to any state of the same type.
+func checkStateReachable(ctx context.Context, client *platform.ByProjectKeyRequestBuilder, state *platform.State) diag.Diagnostics {
+	if state.Initial {
+		return nil
+	}
+
+	where := fmt.Sprintf(
+		"type = %q and id != %q and (transitions is not defined or transitions(id = %q))",
+		state.Type, state.ID, state.ID)
+	result, err := client.States().Get().Where([]string{where}).Limit(1).Execute(ctx)
+	if err != nil {
+		log.Printf("[WARN] Unable to check if state %s is reachable: %s", state.Key, err)
+		return nil
+	}
+	if len(result.Results) > 0 {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("State %s is unreachable", state.Key),
+		Detail: fmt.Sprintf(
+			"The state %s is not initial and no other state of type %s has a transition to it",
+			state.Key, state.Type),
+	}}
+}
@@ -4,12 +4,68 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
 )
 
+func TestMarshallStateTransitions(t *testing.T) {
+	values := []platform.StateReference{
+		{ID: "id-a", Obj: &platform.State{ID: "id-a", Key: "state-a"}},
+		{ID: "id-b", Obj: &platform.State{ID: "id-b", Key: "state-b"}},
+		{ID: "id-c", Obj: &platform.State{ID: "id-c", Key: "state-c"}},
+	}
+
+	result := marshallStateTransitions(values, []string{"state-a", "id-b"})
+	assert.ElementsMatch(t, []string{"state-a", "id-b", "id-c"}, result)
+
+	result = marshallStateTransitions([]platform.StateReference{{ID: "id-a"}}, []string{"state-a"})
+	assert.Equal(t, []string{"id-a"}, result)
+}
+
+func TestValidateStateTransitions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/my-project/states/key=shipped" {
+			w.Write([]byte(`{"id": "state-1", "key": "shipped", "type": "OrderState"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"statusCode": 404, "message": "The Resource with ID 'x' was not found."}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: server.Client(),
+	})
+	assert.NoError(t, err)
+	meta := &providerMeta{client: client.WithProjectKey("my-project")}
+
+	diff := func(stateType string, transitions ...interface{}) error {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"key":         "ordered",
+			"type":        stateType,
+			"transitions": transitions,
+		})
+		_, err := resourceState().SimpleDiff(context.Background(), &terraform.InstanceState{}, config, meta)
+		return err
+	}
+
+	assert.NoError(t, diff("OrderState", "shipped"))
+	// A state of a new workflow is created in the same apply
+	assert.NoError(t, diff("OrderState", "shipped", "delivered"))
+	err = diff("LineItemState", "shipped")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `state "shipped" referenced in transitions has type OrderState, expected LineItemState`)
+	}
+}
+
 func TestAccState_createAndUpdateWithID(t *testing.T) {
 	name := "test state"
 	key := "test-state"
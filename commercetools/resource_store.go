@@ -35,7 +35,7 @@ func resourceStore() *schema.Resource {
 			"name": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"version": {
@@ -81,7 +81,7 @@ func resourceStoreCreate(ctx context.Context, d *schema.ResourceData, m interfac
 
 	var store *platform.Store
 
-	err := resource.RetryContext(ctx, 20*time.Second, func() *resource.RetryError {
+	err := retryContext(ctx, m, "create store", 20*time.Second, func() *resource.RetryError {
 		var err error
 		store, err = client.Stores().Post(draft).Execute(ctx)
 
@@ -92,7 +92,7 @@ func resourceStoreCreate(ctx context.Context, d *schema.ResourceData, m interfac
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	d.SetId(store.ID)
@@ -116,7 +116,7 @@ func resourceStoreRead(ctx context.Context, d *schema.ResourceData, m interface{
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	d.SetId(store.ID)
@@ -135,7 +135,7 @@ func resourceStoreRead(ctx context.Context, d *schema.ResourceData, m interface{
 	if store.DistributionChannels != nil {
 		channelKeys, err := flattenStoreChannels(store.DistributionChannels)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		log.Printf("[DEBUG] Setting channel keys to: %+v", channelKeys)
 		d.Set("distribution_channels", channelKeys)
@@ -145,7 +145,7 @@ func resourceStoreRead(ctx context.Context, d *schema.ResourceData, m interface{
 	if store.SupplyChannels != nil {
 		channelKeys, err := flattenStoreChannels(store.SupplyChannels)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		log.Printf("[DEBUG] Setting channel keys to: %+v", channelKeys)
 		d.Set("supply_channels", channelKeys)
@@ -206,7 +206,7 @@ func resourceStoreUpdate(ctx context.Context, d *schema.ResourceData, m interfac
 
 	_, err := client.Stores().WithId(d.Id()).Post(input).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceStoreRead(ctx, d, m)
@@ -218,7 +218,7 @@ func resourceStoreDelete(ctx context.Context, d *schema.ResourceData, m interfac
 
 	_, err := client.Stores().WithId(d.Id()).Delete().Version(version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
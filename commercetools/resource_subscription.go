@@ -278,21 +278,21 @@ func resourceSubscriptionCreate(ctx context.Context, d *schema.ResourceData, m i
 	var subscription *platform.Subscription
 
 	if err := validateDestination(d); err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 	if err := validateFormat(d); err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	messages := unmarshallSubscriptionMessages(d)
 	changes := unmarshallSubscriptionChanges(d)
 	destination, err := unmarshallSubscriptionDestination(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 	format, err := unmarshallSubscriptionFormat(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	draft := platform.SubscriptionDraft{
@@ -303,7 +303,7 @@ func resourceSubscriptionCreate(ctx context.Context, d *schema.ResourceData, m i
 		Changes:     changes,
 	}
 
-	err = resource.RetryContext(ctx, 20*time.Second, func() *resource.RetryError {
+	err = retryContext(ctx, m, "create subscription", 20*time.Second, func() *resource.RetryError {
 		var err error
 
 		subscription, err = client.Subscriptions().Post(draft).Execute(ctx)
@@ -315,7 +315,7 @@ func resourceSubscriptionCreate(ctx context.Context, d *schema.ResourceData, m i
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if subscription == nil {
@@ -341,7 +341,7 @@ func resourceSubscriptionRead(ctx context.Context, d *schema.ResourceData, m int
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if subscription == nil {
@@ -365,10 +365,10 @@ func resourceSubscriptionUpdate(ctx context.Context, d *schema.ResourceData, m i
 	client := getClient(m)
 
 	if err := validateDestination(d); err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 	if err := validateFormat(d); err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input := platform.SubscriptionUpdate{
@@ -379,7 +379,7 @@ func resourceSubscriptionUpdate(ctx context.Context, d *schema.ResourceData, m i
 	if d.HasChange("destination") {
 		destination, err := unmarshallSubscriptionDestination(d)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 
 		input.Actions = append(
@@ -408,7 +408,7 @@ func resourceSubscriptionUpdate(ctx context.Context, d *schema.ResourceData, m i
 			&platform.SubscriptionSetChangesAction{Changes: changes})
 	}
 
-	err := resource.RetryContext(ctx, 5*time.Second, func() *resource.RetryError {
+	err := retryContext(ctx, m, "update subscription", 5*time.Second, func() *resource.RetryError {
 		var err error
 
 		_, err = client.Subscriptions().WithId(d.Id()).Post(input).Execute(ctx)
@@ -420,7 +420,7 @@ func resourceSubscriptionUpdate(ctx context.Context, d *schema.ResourceData, m i
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceSubscriptionRead(ctx, d, m)
@@ -431,7 +431,7 @@ func resourceSubscriptionDelete(ctx context.Context, d *schema.ResourceData, m i
 	version := d.Get("version").(int)
 	_, err := client.Subscriptions().WithId(d.Id()).Delete().Version(version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
@@ -56,7 +56,7 @@ func resourceTaxCategoryCreate(ctx context.Context, d *schema.ResourceData, m in
 		Rates:       emptyTaxRates,
 	}
 
-	err := resource.RetryContext(ctx, 1*time.Minute, func() *resource.RetryError {
+	err := retryContext(ctx, m, "create tax category", 1*time.Minute, func() *resource.RetryError {
 		var err error
 
 		taxCategory, err = client.TaxCategories().Post(draft).Execute(ctx)
@@ -67,7 +67,7 @@ func resourceTaxCategoryCreate(ctx context.Context, d *schema.ResourceData, m in
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if taxCategory == nil {
@@ -93,7 +93,7 @@ func resourceTaxCategoryRead(ctx context.Context, d *schema.ResourceData, m inte
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if taxCategory == nil {
@@ -119,7 +119,7 @@ func resourceTaxCategoryUpdate(ctx context.Context, d *schema.ResourceData, m in
 	client := getClient(m)
 	taxCategory, err := client.TaxCategories().WithId(d.Id()).Get().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input := platform.TaxCategoryUpdate{
@@ -157,7 +157,7 @@ func resourceTaxCategoryUpdate(ctx context.Context, d *schema.ResourceData, m in
 		if ctErr, ok := err.(platform.ErrorResponse); ok {
 			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceTaxCategoryRead(ctx, d, m)
@@ -172,11 +172,11 @@ func resourceTaxCategoryDelete(ctx context.Context, d *schema.ResourceData, m in
 
 	taxCategory, err := client.TaxCategories().WithId(d.Id()).Get().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 	_, err = client.TaxCategories().WithId(d.Id()).Delete().Version(taxCategory.Version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
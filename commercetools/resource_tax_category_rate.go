@@ -133,7 +133,7 @@ func resourceTaxCategoryRateCreate(ctx context.Context, d *schema.ResourceData,
 	taxCategory, err := client.TaxCategories().WithId(taxCategoryID).Get().Execute(ctx)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	oldTaxRateIds := getTaxRateIds(taxCategory)
@@ -145,12 +145,12 @@ func resourceTaxCategoryRateCreate(ctx context.Context, d *schema.ResourceData,
 
 	taxRateDraft, err := createTaxRateDraft(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input.Actions = append(input.Actions, platform.TaxCategoryAddTaxRateAction{TaxRate: *taxRateDraft})
 
-	err = resource.RetryContext(ctx, 30*time.Second, func() *resource.RetryError {
+	err = retryContext(ctx, m, "create tax category rate", 30*time.Second, func() *resource.RetryError {
 		taxCategory, err = client.TaxCategories().WithId(taxCategoryID).Post(input).Execute(ctx)
 		if err != nil {
 			return handleCommercetoolsError(err)
@@ -159,7 +159,7 @@ func resourceTaxCategoryRateCreate(ctx context.Context, d *schema.ResourceData,
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	// Refresh the taxCategory. When a tax rate is added the ID is different
@@ -220,7 +220,7 @@ func resourceTaxCategoryRateUpdate(ctx context.Context, d *schema.ResourceData,
 
 	taxCategory, _, err := readResourcesFromStateIDs(ctx, d, m)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	oldTaxRateIds := getTaxRateIds(taxCategory)
@@ -233,7 +233,7 @@ func resourceTaxCategoryRateUpdate(ctx context.Context, d *schema.ResourceData,
 	if d.HasChange("name") || d.HasChange("amount") || d.HasChange("included_in_price") || d.HasChange("country") || d.HasChange("state") || d.HasChange("sub_rate") {
 		taxRateDraft, err := createTaxRateDraft(d)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		input.Actions = append(input.Actions, platform.TaxCategoryReplaceTaxRateAction{
 			TaxRateId: d.Id(),
@@ -251,14 +251,14 @@ func resourceTaxCategoryRateUpdate(ctx context.Context, d *schema.ResourceData,
 		if ctErr, ok := err.(platform.ErrorResponse); ok {
 			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	// Refresh the taxCategory. When a tax rate is added the ID is different
 	// then the ID returned in the response
 	updatedTaxCategory, err := client.TaxCategories().WithId(taxCategoryID).Get().Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	newTaxRate := findNewTaxRate(updatedTaxCategory, oldTaxRateIds)
@@ -314,7 +314,7 @@ func resourceTaxCategoryRateDelete(ctx context.Context, d *schema.ResourceData,
 
 	taxCategory, taxRate, err := readResourcesFromStateIDs(ctx, d, m)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	input := platform.TaxCategoryUpdate{
@@ -330,7 +330,7 @@ func resourceTaxCategoryRateDelete(ctx context.Context, d *schema.ResourceData,
 	client := getClient(m)
 	_, err = client.TaxCategories().WithId(taxCategory.ID).Post(input).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
@@ -37,13 +37,13 @@ func resourceType() *schema.Resource {
 			"name": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Required:         true,
 			},
 			"description": {
 				Description:      "[LocalizedString](https://docs.commercetools.com/api/types#localizedstring)",
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Optional:         true,
 			},
 			"resource_type_ids": {
@@ -80,7 +80,7 @@ func resourceType() *schema.Resource {
 						"label": {
 							Description:      "A human-readable label for the field",
 							Type:             TypeLocalizedString,
-							ValidateDiagFunc: validateLocalizedStringKey,
+							ValidateDiagFunc: validateLocalizedString,
 							Required:         true,
 						},
 						"required": {
@@ -155,7 +155,7 @@ func localizedValueElement() *schema.Resource {
 			},
 			"label": {
 				Type:             TypeLocalizedString,
-				ValidateDiagFunc: validateLocalizedStringKey,
+				ValidateDiagFunc: validateLocalizedString,
 				Required:         true,
 			},
 		},
@@ -249,7 +249,7 @@ func resourceTypeCreate(ctx context.Context, d *schema.ResourceData, m interface
 	fields, err := resourceTypeGetFieldDefinitions(d)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	draft := platform.TypeDraft{
@@ -260,7 +260,7 @@ func resourceTypeCreate(ctx context.Context, d *schema.ResourceData, m interface
 		FieldDefinitions: fields,
 	}
 
-	err = resource.RetryContext(ctx, 1*time.Minute, func() *resource.RetryError {
+	err = retryContext(ctx, m, "create type", 1*time.Minute, func() *resource.RetryError {
 		var err error
 
 		ctType, err = client.Types().Post(draft).Execute(ctx)
@@ -271,7 +271,7 @@ func resourceTypeCreate(ctx context.Context, d *schema.ResourceData, m interface
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if ctType == nil {
@@ -297,7 +297,7 @@ func resourceTypeRead(ctx context.Context, d *schema.ResourceData, m interface{}
 				return nil
 			}
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	if ctType == nil {
@@ -318,7 +318,7 @@ func resourceTypeRead(ctx context.Context, d *schema.ResourceData, m interface{}
 		if fields, err := marshallTypeFields(ctType); err == nil {
 			d.Set("field", fields)
 		} else {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 	}
 	return nil
@@ -358,7 +358,7 @@ func resourceTypeUpdate(ctx context.Context, d *schema.ResourceData, m interface
 		old, new := d.GetChange("field")
 		fieldChangeActions, err := resourceTypeFieldChangeActions(old.([]interface{}), new.([]interface{}))
 		if err != nil {
-			return diag.FromErr(err)
+			return diagnosticsFromError(err)
 		}
 		input.Actions = append(input.Actions, fieldChangeActions...)
 	}
@@ -371,7 +371,7 @@ func resourceTypeUpdate(ctx context.Context, d *schema.ResourceData, m interface
 		if ctErr, ok := err.(platform.ErrorResponse); ok {
 			log.Printf("[DEBUG] %v: %v", ctErr, stringFormatErrorExtras(ctErr))
 		}
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return resourceTypeRead(ctx, d, m)
@@ -545,7 +545,7 @@ func resourceTypeDelete(ctx context.Context, d *schema.ResourceData, m interface
 	version := d.Get("version").(int)
 	_, err := client.Types().WithId(d.Id()).Delete().Version(version).Execute(ctx)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagnosticsFromError(err)
 	}
 
 	return nil
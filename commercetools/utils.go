@@ -1,12 +1,23 @@
 package commercetools
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -19,8 +30,7 @@ import (
 const TypeLocalizedString = schema.TypeMap
 
 func getClient(m interface{}) *platform.ByProjectKeyRequestBuilder {
-	client := m.(*platform.ByProjectKeyRequestBuilder)
-	return client
+	return getProviderMeta(m).client
 }
 
 func stringRef(value interface{}) *string {
@@ -38,15 +48,456 @@ func boolRef(value interface{}) *bool {
 	return &result
 }
 
+// retryContext wraps resource.RetryContext and logs the number of attempts
+// and the total elapsed time of the operation when it is done. This gives
+// insight in how often retries occur and if the retry window needs tuning.
+// The wait between attempts grows exponentially, as implemented by
+// resource.RetryContext. When the operation is still failing with a retryable
+// error once the timeout expires the error is wrapped to make clear the
+// operation was retried.
+//
+// The time spent waiting between attempts is taken from the retry budget of
+// the provider, once the budget is exhausted the last error is returned
+// without retrying again. When commercetools rate limits a request, the next
+// attempt waits at least until the Retry-After of the response, which is also
+// taken from the budget. The wait ends early when the context is done or the
+// timeout expires.
+//
+// When the provider waits for maintenance windows, an operation which fails
+// because the project is under maintenance is started again once the wait is
+// over. The attempts after the wait get the full timeout for their retries,
+// but the wait and the attempts all end when the context expires.
+func retryContext(ctx context.Context, m interface{}, operation string, timeout time.Duration, f resource.RetryFunc) error {
+	if meta, ok := m.(*providerMeta); ok && meta.waitForMaintenance {
+		return waitForMaintenance(ctx, operation, func() error {
+			return retryContextTimeout(ctx, m, operation, timeout, f)
+		})
+	}
+	return retryContextTimeout(ctx, m, operation, timeout, f)
+}
+
+func retryContextTimeout(ctx context.Context, m interface{}, operation string, timeout time.Duration, f resource.RetryFunc) error {
+	start := time.Now()
+	var attempts int32
+	var retryable int32
+
+	var budget *retryBudget
+	if meta, ok := m.(*providerMeta); ok {
+		budget = meta.retryBudget
+	}
+	var mu sync.Mutex
+	var lastFailure time.Time
+	var lastErr error
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	exhausted := func() *resource.RetryError {
+		atomic.StoreInt32(&retryable, 0)
+		return resource.NonRetryableError(fmt.Errorf(
+			"%s failed, the retry budget of %s of the provider is exhausted: %w", operation, budget.total, lastErr))
+	}
+
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !lastFailure.IsZero() && !budget.consume(time.Since(lastFailure)) {
+			return exhausted()
+		}
+
+		atomic.AddInt32(&attempts, 1)
+		result := f()
+		if result == nil || !result.Retryable {
+			atomic.StoreInt32(&retryable, 0)
+			return result
+		}
+		atomic.StoreInt32(&retryable, 1)
+		lastFailure, lastErr = time.Now(), result.Err
+
+		if delay := retryAfter(result.Err); delay > 0 {
+			if !budget.consume(delay) {
+				return exhausted()
+			}
+			log.Printf("[DEBUG] Waiting %s before retrying %s", delay, operation)
+			select {
+			case <-waitCtx.Done():
+			case <-time.After(delay):
+			}
+			lastFailure = time.Now()
+		}
+		return result
+	})
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+	log.Printf(
+		"[DEBUG] Finished %s after %d attempt(s) in %s (timeout %s)",
+		operation, atomic.LoadInt32(&attempts), elapsed, timeout)
+
+	if err != nil && atomic.LoadInt32(&retryable) == 1 {
+		return &retryTimeoutError{
+			operation: operation,
+			timeout:   timeout,
+			attempts:  atomic.LoadInt32(&attempts),
+			err:       err,
+		}
+	}
+	return err
+}
+
+// retryTimeoutError is returned by retryContext when the last attempt failed
+// with a retryable error and the timeout expired
+type retryTimeoutError struct {
+	operation string
+	timeout   time.Duration
+	attempts  int32
+	err       error
+}
+
+func (e *retryTimeoutError) Error() string {
+	return fmt.Sprintf("%s did not succeed within %s after %d attempt(s): %s", e.operation, e.timeout, e.attempts, e.err)
+}
+
+func (e *retryTimeoutError) Unwrap() error {
+	return e.err
+}
+
+// isRetryTimeout returns whether the error of retryContext is caused by the
+// timeout, in which case the last attempt may have succeeded without a response
+func isRetryTimeout(err error) bool {
+	var timeoutErr *retryTimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+// retryBudget is the total time all resources of the provider can spend on
+// waiting for retries. This makes an apply fail fast when commercetools keeps
+// failing, instead of every resource retrying until its own timeout. A nil
+// budget is unlimited.
+type retryBudget struct {
+	total time.Duration
+
+	mu        sync.Mutex
+	remaining time.Duration
+}
+
+func newRetryBudget(total time.Duration) *retryBudget {
+	return &retryBudget{total: total, remaining: total}
+}
+
+// consume takes the duration from the budget and reports whether the budget
+// still has time left
+func (b *retryBudget) consume(duration time.Duration) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remaining -= duration
+	return b.remaining > 0
+}
+
+// forEachConcurrently calls f for every ID, with at most concurrency calls at
+// the same time. It returns the errors of the failed calls by ID. IDs which
+// are not started before the context is done fail with the context error.
+func forEachConcurrently(ctx context.Context, ids []string, concurrency int, f func(id string) error) map[string]error {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failures := map[string]error{}
+	semaphore := make(chan struct{}, concurrency)
+
+	for _, id := range ids {
+		id := id
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			failures[id] = ctx.Err()
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if err := f(id); err != nil {
+				mu.Lock()
+				failures[id] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return failures
+}
+
+// waitForConsistency calls f until it no longer returns a not found error. A
+// resource can't always be read directly after it was created, since
+// commercetools is eventually consistent.
+func waitForConsistency(ctx context.Context, m interface{}, operation string, timeout time.Duration, f func() error) error {
+	return retryContext(ctx, m, operation, timeout, func() *resource.RetryError {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if isNotFoundError(err) {
+			return resource.RetryableError(err)
+		}
+		return handleCommercetoolsError(err)
+	})
+}
+
+// handleCommercetoolsError decides whether an error returned by commercetools
+// should be retried. Gateway errors (502, 503 and 504) occur temporarily during
+// deployments of the platform and are retried, as are errors which are not an
+// error response of the API (for example network errors). All other error
+// responses, like the 400-class errors, are returned directly. Note that the
+// SDK does not decode a 504 into an error response, so it ends up in the
+// generic case. A 503 because of a maintenance is returned as a
+// maintenanceError, which retryContext handles.
 func handleCommercetoolsError(err error) *resource.RetryError {
 	if ctErr, ok := err.(platform.ErrorResponse); ok {
+		if isMaintenanceResponse(ctErr) {
+			log.Printf("[DEBUG] Received maintenance error: %s", err)
+			return resource.NonRetryableError(&maintenanceError{err: ctErr})
+		}
+		if isGatewayError(ctErr.StatusCode) {
+			log.Printf("[DEBUG] Received gateway error (%d): %s", ctErr.StatusCode, err)
+			return resource.RetryableError(ctErr)
+		}
 		return resource.NonRetryableError(ctErr)
 	}
 
+	var rateLimited *rateLimitError
+	if errors.As(err, &rateLimited) {
+		log.Printf("[DEBUG] Received rate limit error, retrying after %s", rateLimited.retryAfter)
+		return resource.RetryableError(err)
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return resource.NonRetryableError(err)
+	}
+
+	if isNetworkError(err) {
+		log.Printf("[DEBUG] Received network error, retrying: %s", err)
+		return resource.RetryableError(err)
+	}
+
 	log.Printf("[DEBUG] Received error: %s", err)
 	return resource.RetryableError(err)
 }
 
+// retryAfter returns how long to wait before retrying the error, which is the
+// Retry-After of a rate limited request and 0 for all other errors
+func retryAfter(err error) time.Duration {
+	var rateLimited *rateLimitError
+	if errors.As(err, &rateLimited) {
+		return rateLimited.retryAfter
+	}
+	return 0
+}
+
+// isNetworkError returns whether the request failed before commercetools
+// could respond, for example because of a timeout, a failed DNS lookup or a
+// connection which was reset
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func isGatewayError(statusCode int) bool {
+	switch statusCode {
+	case 502, 503, 504:
+		return true
+	}
+	return false
+}
+
+var uuidRegex = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+// resolveResourceIdentifier returns the value as the ID of a resource
+// identifier when it is a UUID, which all IDs generated by commercetools are,
+// and as the key otherwise. Keys which are a UUID are used as an ID.
+func resolveResourceIdentifier(value string) (id *string, key *string) {
+	if uuidRegex.MatchString(value) {
+		return &value, nil
+	}
+	return nil, &value
+}
+
+// diagnosticsFromError converts an error to diagnostics. For commercetools
+// error responses a diagnostic is created for every error in the response, with
+// the error code and HTTP status code in the detail so automation parsing the
+// diagnostics can act on the type of error. Other errors are converted using
+// diag.FromErr.
+func diagnosticsFromError(err error) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+
+	var maintenance *maintenanceError
+	if errors.As(err, &maintenance) {
+		return maintenanceDiagnostics(maintenance)
+	}
+
+	var ctErr platform.ErrorResponse
+	if !errors.As(err, &ctErr) {
+		return diag.FromErr(err)
+	}
+
+	if len(ctErr.Errors) == 0 {
+		code := ""
+		if ctErr.ErrorMessage != nil {
+			code = *ctErr.ErrorMessage
+		}
+		return diag.Diagnostics{
+			diagnosticFromErrorObject(ctErr.StatusCode, code, ctErr.Message),
+		}
+	}
+
+	diags := make(diag.Diagnostics, 0, len(ctErr.Errors))
+	for _, item := range ctErr.Errors {
+		fields := errorObjectFields(item)
+		code, _ := fields["code"].(string)
+		message, ok := fields["message"].(string)
+		if !ok {
+			message = ctErr.Message
+		}
+		diags = append(diags, diagnosticFromErrorObject(ctErr.StatusCode, code, message))
+	}
+	return diags
+}
+
+func diagnosticFromErrorObject(statusCode int, code string, message string) diag.Diagnostic {
+	details := []string{}
+	if code != "" {
+		details = append(details, fmt.Sprintf("Error code: %s", code))
+	}
+	details = append(details, fmt.Sprintf("HTTP status: %d", statusCode))
+	if position := predicateErrorPosition(message); position != "" {
+		details = append(details, fmt.Sprintf("Position in predicate: %s", position))
+	}
+	if code == "NotEnabled" {
+		details = append(details, featureNotEnabledDetail(message))
+	}
+
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  message,
+		Detail:   strings.Join(details, "\n"),
+	}
+}
+
+var featureNameRegex = regexp.MustCompile(`['"]([^'"]+)['"]`)
+
+// projectFeatureSettings maps features which can be enabled by the project
+// settings to the attribute of the commercetools_project_settings resource
+var projectFeatureSettings = []struct {
+	name    string
+	setting string
+}{
+	{"messages", "messages.enabled"},
+	{"product search", "enable_search_index_products"},
+	{"products search", "enable_search_index_products"},
+	{"order search", "enable_search_index_orders"},
+	{"orders search", "enable_search_index_orders"},
+}
+
+// featureNotEnabledDetail returns a hint for a NotEnabled error, which is
+// returned when the project uses a feature which is not enabled for it. The
+// error object only has a message, so the feature name is taken from it.
+func featureNotEnabledDetail(message string) string {
+	feature := "used by this resource"
+	if match := featureNameRegex.FindStringSubmatch(message); match != nil {
+		feature = match[1]
+	}
+
+	lower := strings.ToLower(message)
+	for _, item := range projectFeatureSettings {
+		if strings.Contains(lower, item.name) {
+			return fmt.Sprintf("The feature %s is not enabled for the project. Enable it by setting %s of "+
+				"the commercetools_project_settings resource", feature, item.setting)
+		}
+	}
+	return fmt.Sprintf("The feature %s is not enabled for the project. Enable it in the project settings "+
+		"in the Merchant Center, some features need to be enabled by commercetools support", feature)
+}
+
+var (
+	predicateLineColumnRegex = regexp.MustCompile(`(?i)\bline (\d+), column (\d+)`)
+	predicateOffsetRegex     = regexp.MustCompile(`(?i)\b(?:at position|offset) (\d+)`)
+)
+
+// predicateErrorPosition returns the position of a syntax error in a predicate
+// as reported in the error message, for example "(line 1, column 23)". The
+// error objects don't contain the position as a separate field. Long
+// predicates are hard to debug without it.
+func predicateErrorPosition(message string) string {
+	if match := predicateLineColumnRegex.FindStringSubmatch(message); match != nil {
+		return fmt.Sprintf("line %s, column %s", match[1], match[2])
+	}
+	if match := predicateOffsetRegex.FindStringSubmatch(message); match != nil {
+		return fmt.Sprintf("offset %s", match[1])
+	}
+	return ""
+}
+
+// errorObjectFields returns the fields of an error object in the errors list
+// of an ErrorResponse. The SDK decodes these into typed structs which don't
+// hold the error code, but which add it again when marshalled to JSON.
+func errorObjectFields(item platform.ErrorObject) map[string]interface{} {
+	result := map[string]interface{}{}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return result
+	}
+	json.Unmarshal(data, &result)
+	return result
+}
+
+// isNotFoundError returns true when the error is a 404 response. Depending on
+// the endpoint the SDK returns either an ErrorResponse or a GenericRequestError
+func isNotFoundError(err error) bool {
+	switch v := err.(type) {
+	case platform.ErrorResponse:
+		return v.StatusCode == 404
+	case platform.GenericRequestError:
+		return v.StatusCode == 404
+	}
+	return false
+}
+
+// duplicateFieldError returns the DuplicateField error for the given field if
+// the error is a commercetools error response containing one
+func duplicateFieldError(err error, field string) (*platform.DuplicateFieldError, bool) {
+	var ctErr platform.ErrorResponse
+	if !errors.As(err, &ctErr) {
+		return nil, false
+	}
+	for _, item := range ctErr.Errors {
+		if v, ok := item.(platform.DuplicateFieldError); ok && v.Field != nil && *v.Field == field {
+			return &v, true
+		}
+	}
+	return nil, false
+}
+
+// isExplicitEmptyMap returns true when the attribute is set to an empty map in
+// the configuration, as opposed to not being set at all
+func isExplicitEmptyMap(config cty.Value, key string) bool {
+	if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() || !config.Type().HasAttribute(key) {
+		return false
+	}
+	value := config.GetAttr(key)
+	return !value.IsNull() && value.IsKnown() && value.LengthInt() == 0
+}
+
 func expandStringArray(input []interface{}) []string {
 	s := make([]string, len(input))
 	for i := range input {
@@ -64,6 +515,103 @@ func localizedStringCompare(a platform.LocalizedString, b map[string]interface{}
 	return true
 }
 
+// diffLocalizedString returns a line per locale which was added, removed or
+// changed between the two values, sorted by locale
+func diffLocalizedString(old, new platform.LocalizedString) []string {
+	locales := map[string]bool{}
+	for k := range old {
+		locales[k] = true
+	}
+	for k := range new {
+		locales[k] = true
+	}
+
+	keys := make([]string, 0, len(locales))
+	for k := range locales {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := []string{}
+	for _, k := range keys {
+		oldValue, inOld := old[k]
+		newValue, inNew := new[k]
+		switch {
+		case !inOld:
+			lines = append(lines, fmt.Sprintf("+ %s: %q", k, newValue))
+		case !inNew:
+			lines = append(lines, fmt.Sprintf("- %s: %q", k, oldValue))
+		case oldValue != newValue:
+			lines = append(lines, fmt.Sprintf("~ %s: %q => %q", k, oldValue, newValue))
+		}
+	}
+	return lines
+}
+
+// resourceChange is implemented by both *schema.ResourceData and
+// *schema.ResourceDiff, so update actions can be built when applying and when
+// planning
+type resourceChange interface {
+	Get(key string) interface{}
+	GetChange(key string) (interface{}, interface{})
+	HasChange(key string) bool
+	GetRawConfig() cty.Value
+}
+
+// logLocalizedStringChange logs which locales of a localized field changed so
+// the logs show more than the full replacement value of the update action
+func logLocalizedStringChange(d resourceChange, field string) {
+	old, new := d.GetChange(field)
+	lines := diffLocalizedString(unmarshallLocalizedString(old), unmarshallLocalizedString(new))
+	log.Printf("[DEBUG] Changed locales of %s:\n%s", field, strings.Join(lines, "\n"))
+}
+
+// diffStringList returns the values which were added to and removed from the
+// list, in the order of the lists. Values which only moved are not returned.
+func diffStringList(old, new []string) (added []string, removed []string) {
+	oldValues := make(map[string]bool, len(old))
+	for _, value := range old {
+		oldValues[value] = true
+	}
+	newValues := make(map[string]bool, len(new))
+	for _, value := range new {
+		newValues[value] = true
+	}
+
+	for _, value := range new {
+		if !oldValues[value] {
+			added = append(added, value)
+		}
+	}
+	for _, value := range old {
+		if !newValues[value] {
+			removed = append(removed, value)
+		}
+	}
+	return added, removed
+}
+
+// logStringListChange logs the values which were added to and removed from a
+// list attribute. The plan of a list shows every changed position, which makes
+// adding a single value to the front of a list hard to review.
+func logStringListChange(d resourceChange, field string) {
+	old, new := d.GetChange(field)
+	added, removed := diffStringList(expandStringArray(old.([]interface{})), expandStringArray(new.([]interface{})))
+
+	lines := []string{}
+	for _, value := range added {
+		lines = append(lines, "+ "+value)
+	}
+	for _, value := range removed {
+		lines = append(lines, "- "+value)
+	}
+	if len(lines) == 0 {
+		log.Printf("[DEBUG] Changed the order of %s", field)
+		return
+	}
+	log.Printf("[DEBUG] Changed values of %s (+%d -%d):\n%s", field, len(added), len(removed), strings.Join(lines, "\n"))
+}
+
 func stringFormatObject(object interface{}) string {
 	data, err := json.MarshalIndent(object, "", "    ")
 
@@ -73,6 +621,11 @@ func stringFormatObject(object interface{}) string {
 	return string(append(data, '\n'))
 }
 
+// newCorrelationID returns a unique ID to send as X-Correlation-ID header
+func newCorrelationID() string {
+	return resource.PrefixedUniqueId("terraform-provider-commercetools-")
+}
+
 func stringFormatErrorExtras(err platform.ErrorResponse) string {
 	switch len(err.Errors) {
 	case 0:
@@ -309,6 +862,17 @@ func ValidateCurrencyCode(val interface{}, key string) (warns []string, errs []e
 	return
 }
 
+func validateDuration(val interface{}, key string) (warns []string, errs []error) {
+	value := val.(string)
+	if value == "" {
+		return
+	}
+	if duration, err := time.ParseDuration(value); err != nil || duration <= 0 {
+		errs = append(errs, fmt.Errorf("%q must be a positive duration like 5m, got: %s", key, value))
+	}
+	return
+}
+
 func transformToList(data map[string]interface{}, key string) {
 	newDestination := make([]interface{}, 1)
 	if data[key] != nil {
@@ -353,7 +917,28 @@ func isNotEmpty(d map[string]interface{}, key string) (interface{}, bool) {
 	return nil, false
 }
 
-var validateLocalizedStringKey = validation.MapKeyMatch(
+var validateLocaleKey = validation.MapKeyMatch(
 	regexp.MustCompile("^[a-z]{2}(-[A-Z]{2})?$"),
 	"Locale keys must match pattern ^[a-z]{2}(-[A-Z]{2})?$",
 )
+
+// validateLocalizedString checks that the keys of a localized string are
+// locales and that its values can be used as strings
+func validateLocalizedString(val interface{}, path cty.Path) diag.Diagnostics {
+	diags := validateLocaleKey(val, path)
+	values, ok := val.(map[string]interface{})
+	if !ok {
+		return diags
+	}
+	for _, locale := range sortedKeys(mapKeys(values, nil)) {
+		if _, err := localizedStringValue(values[locale]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Invalid value for locale %s", locale),
+				Detail:        fmt.Sprintf("The value of locale %s %s", locale, err),
+				AttributePath: path.IndexString(locale),
+			})
+		}
+	}
+	return diags
+}
@@ -1,10 +1,26 @@
 package commercetools
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/labd/commercetools-go-sdk/platform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateLookup(t *testing.T) {
@@ -27,6 +43,427 @@ func TestCreateLookup(t *testing.T) {
 	}
 }
 
+func TestDiagnosticsFromError(t *testing.T) {
+	err := platform.ErrorResponse{
+		StatusCode: 400,
+		Message:    "Request body does not contain valid JSON.",
+		Errors: []platform.ErrorObject{
+			platform.InvalidJsonInputError{
+				Message: "Request body does not contain valid JSON.",
+			},
+			platform.DuplicateFieldError{
+				Message: "A duplicate value '\"2\"' exists for field 'code'.",
+				Field:   stringRef("code"),
+			},
+		},
+	}
+
+	diags := diagnosticsFromError(err)
+	assert.Equal(t, diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  "Request body does not contain valid JSON.",
+			Detail:   "Error code: InvalidJsonInput\nHTTP status: 400",
+		},
+		{
+			Severity: diag.Error,
+			Summary:  "A duplicate value '\"2\"' exists for field 'code'.",
+			Detail:   "Error code: DuplicateField\nHTTP status: 400",
+		},
+	}, diags)
+
+	// Errors returned by the retry helpers are unwrapped
+	timeoutErr := &resource.TimeoutError{LastError: err}
+	assert.Len(t, diagnosticsFromError(timeoutErr), 2)
+
+	authErr := platform.ErrorResponse{
+		StatusCode:   401,
+		Message:      "Please provide valid client credentials",
+		ErrorMessage: stringRef("invalid_client"),
+	}
+	assert.Equal(t, diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  "Please provide valid client credentials",
+			Detail:   "Error code: invalid_client\nHTTP status: 401",
+		},
+	}, diagnosticsFromError(authErr))
+
+	assert.Equal(t, diag.FromErr(errors.New("other")), diagnosticsFromError(errors.New("other")))
+	assert.Nil(t, diagnosticsFromError(nil))
+}
+
+func TestDuplicateFieldError(t *testing.T) {
+	field := "code"
+	err := fmt.Errorf("create failed: %w", platform.ErrorResponse{
+		StatusCode: 400,
+		Message:    "A duplicate value '\"SUMMER\"' exists for field 'code'.",
+		Errors: []platform.ErrorObject{
+			platform.DuplicateFieldError{Field: &field, DuplicateValue: "SUMMER"},
+		},
+	})
+
+	result, ok := duplicateFieldError(err, "code")
+	assert.True(t, ok)
+	assert.Equal(t, "SUMMER", result.DuplicateValue)
+
+	_, ok = duplicateFieldError(err, "key")
+	assert.False(t, ok)
+	_, ok = duplicateFieldError(errors.New("other"), "code")
+	assert.False(t, ok)
+}
+
+func TestIsExplicitEmptyMap(t *testing.T) {
+	config := cty.ObjectVal(map[string]cty.Value{
+		"name":        cty.MapValEmpty(cty.String),
+		"description": cty.NullVal(cty.Map(cty.String)),
+		"title":       cty.MapVal(map[string]cty.Value{"en": cty.StringVal("Title")}),
+	})
+
+	assert.True(t, isExplicitEmptyMap(config, "name"))
+	assert.False(t, isExplicitEmptyMap(config, "description"))
+	assert.False(t, isExplicitEmptyMap(config, "title"))
+	assert.False(t, isExplicitEmptyMap(config, "unknown"))
+	assert.False(t, isExplicitEmptyMap(cty.NullVal(config.Type()), "name"))
+}
+
+func TestDiffLocalizedString(t *testing.T) {
+	old := platform.LocalizedString{"en": "Name", "de": "Name", "nl": "Naam"}
+	new := platform.LocalizedString{"en": "New name", "de": "Name", "fr": "Nom"}
+
+	assert.Equal(t, []string{
+		`~ en: "Name" => "New name"`,
+		`+ fr: "Nom"`,
+		`- nl: "Naam"`,
+	}, diffLocalizedString(old, new))
+	assert.Empty(t, diffLocalizedString(old, old))
+}
+
+func TestDiffStringList(t *testing.T) {
+	added, removed := diffStringList([]string{"a", "b", "c"}, []string{"d", "c", "a"})
+	assert.Equal(t, []string{"d"}, added)
+	assert.Equal(t, []string{"b"}, removed)
+
+	added, removed = diffStringList([]string{"a", "b"}, []string{"b", "a"})
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+
+	added, removed = diffStringList(nil, []string{"a"})
+	assert.Equal(t, []string{"a"}, added)
+	assert.Empty(t, removed)
+}
+
+func TestDiagnosticsFromErrorPredicatePosition(t *testing.T) {
+	message := "Malformed parameter: cartPredicate: Syntax error while parsing 'cartPredicate'. " +
+		"Invalid input 'x', expected andOperator or orOperator (line 1, column 23):\n" +
+		"lineItemTotal(1 = 1) x"
+	err := platform.ErrorResponse{
+		StatusCode: 400,
+		Message:    message,
+		Errors: []platform.ErrorObject{
+			platform.InvalidInputError{Message: message},
+		},
+	}
+
+	diags := diagnosticsFromError(err)
+	assert.Len(t, diags, 1)
+	assert.Equal(t,
+		"Error code: InvalidInput\nHTTP status: 400\nPosition in predicate: line 1, column 23",
+		diags[0].Detail)
+
+	assert.Equal(t, "offset 12", predicateErrorPosition("Unexpected token at position 12"))
+	assert.Equal(t, "", predicateErrorPosition("A duplicate value exists for field 'code'."))
+}
+
+func TestDiagnosticsFromErrorNotEnabled(t *testing.T) {
+	message := "The feature 'productSelections' is not enabled for this project."
+	err := platform.ErrorResponse{
+		StatusCode: 400,
+		Message:    message,
+		Errors: []platform.ErrorObject{
+			platform.NotEnabledError{Message: message},
+		},
+	}
+
+	diags := diagnosticsFromError(err)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, message, diags[0].Summary)
+	assert.Equal(t,
+		"Error code: NotEnabled\nHTTP status: 400\n"+
+			"The feature productSelections is not enabled for the project. Enable it in the project settings "+
+			"in the Merchant Center, some features need to be enabled by commercetools support",
+		diags[0].Detail)
+
+	assert.Equal(t,
+		"The feature used by this resource is not enabled for the project. Enable it by setting "+
+			"enable_search_index_orders of the commercetools_project_settings resource",
+		featureNotEnabledDetail("Order search is not enabled for this project."))
+}
+
+func TestRetryContext(t *testing.T) {
+	attempts := 0
+	err := retryContext(context.Background(), nil, "test", 5*time.Second, func() *resource.RetryError {
+		attempts++
+		if attempts < 2 {
+			return resource.RetryableError(errors.New("temporary"))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	err = retryContext(context.Background(), nil, "test", 5*time.Second, func() *resource.RetryError {
+		return resource.NonRetryableError(errors.New("permanent"))
+	})
+	assert.EqualError(t, err, "permanent")
+}
+
+func TestDiffSuppressTime(t *testing.T) {
+	assert.True(t, diffSuppressTime("", "", "", nil))
+	assert.True(t, diffSuppressTime("", "2020-01-02T15:04:05Z", "2020-01-02T15:04:05.000Z", nil))
+	assert.True(t, diffSuppressTime("", "2020-01-02T15:04:05Z", "2020-01-02T16:04:05+01:00", nil))
+	assert.False(t, diffSuppressTime("", "", "2020-01-02T15:04:05Z", nil))
+	assert.False(t, diffSuppressTime("", "2020-01-02T15:04:05Z", "", nil))
+	assert.False(t, diffSuppressTime("", "2020-01-02T15:04:05Z", "2020-01-03T15:04:05Z", nil))
+	assert.False(t, diffSuppressTime("", "2020-01-02T15:04:05Z", "invalid", nil))
+}
+
+func TestHandleCommercetoolsError(t *testing.T) {
+	for _, statusCode := range []int{502, 503, 504} {
+		result := handleCommercetoolsError(platform.ErrorResponse{StatusCode: statusCode})
+		assert.True(t, result.Retryable, "status code %d", statusCode)
+	}
+	for _, statusCode := range []int{400, 409, 500} {
+		result := handleCommercetoolsError(platform.ErrorResponse{StatusCode: statusCode})
+		assert.False(t, result.Retryable, "status code %d", statusCode)
+	}
+	assert.True(t, handleCommercetoolsError(errors.New("connection reset")).Retryable)
+}
+
+func TestHandleCommercetoolsErrorNetwork(t *testing.T) {
+	dnsErr := &url.Error{Op: "Post", URL: "https://api.europe-west1.gcp.commercetools.com", Err: &net.OpError{
+		Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "api.europe-west1.gcp.commercetools.com"}}}
+	resetErr := fmt.Errorf("read response: %w", syscall.ECONNRESET)
+	timeoutErr := &url.Error{Op: "Get", URL: "https://api.europe-west1.gcp.commercetools.com", Err: &net.DNSError{
+		Err: "i/o timeout", IsTimeout: true}}
+
+	for _, err := range []error{dnsErr, resetErr, timeoutErr, io.ErrUnexpectedEOF} {
+		assert.True(t, isNetworkError(err), "%s", err)
+		assert.True(t, handleCommercetoolsError(err).Retryable, "%s", err)
+	}
+
+	canceled := &url.Error{Op: "Post", URL: "https://api.europe-west1.gcp.commercetools.com", Err: context.Canceled}
+	for _, err := range []error{canceled, context.Canceled, context.DeadlineExceeded} {
+		result := handleCommercetoolsError(err)
+		assert.False(t, result.Retryable, "%s", err)
+		assert.Equal(t, err, result.Err)
+	}
+}
+
+func TestRetryContextNetworkErrors(t *testing.T) {
+	failures := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			// Close the connection without a response, like a proxy resetting it
+			conn, _, err := w.(http.Hijacker).Hijack()
+			assert.NoError(t, err)
+			conn.Close()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "code-1", "code": "SUMMER"}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{URL: server.URL, HTTPClient: server.Client()})
+	assert.NoError(t, err)
+
+	attempts := 0
+	err = retryContext(context.Background(), nil, "read discount code", time.Minute, func() *resource.RetryError {
+		attempts++
+		_, err := client.WithProjectKey("my-project").DiscountCodes().WithId("code-1").Get().Execute(context.Background())
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts = 0
+	err = retryContext(context.Background(), nil, "read discount code", time.Minute, func() *resource.RetryError {
+		attempts++
+		_, err := client.WithProjectKey("my-project").DiscountCodes().WithId("code-1").Get().Execute(ctx)
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 1, attempts)
+}
+
+func TestWaitForConsistency(t *testing.T) {
+	attempts := 0
+	err := waitForConsistency(context.Background(), nil, "read test", 5*time.Second, func() error {
+		attempts++
+		if attempts < 2 {
+			return platform.GenericRequestError{StatusCode: 404}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	attempts = 0
+	err = waitForConsistency(context.Background(), nil, "read test", 5*time.Second, func() error {
+		attempts++
+		return platform.ErrorResponse{StatusCode: 400, Message: "invalid"}
+	})
+	assert.EqualError(t, err, "invalid")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryContextGatewayErrors(t *testing.T) {
+	unavailable := platform.ErrorResponse{StatusCode: 503, Message: "Service Unavailable"}
+
+	attempts := 0
+	err := retryContext(context.Background(), nil, "create test", 5*time.Second, func() *resource.RetryError {
+		attempts++
+		if attempts < 3 {
+			return handleCommercetoolsError(unavailable)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	err = retryContext(context.Background(), nil, "create test", 1*time.Second, func() *resource.RetryError {
+		return handleCommercetoolsError(unavailable)
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "create test did not succeed within 1s after")
+	var ctErr platform.ErrorResponse
+	assert.True(t, errors.As(err, &ctErr))
+	assert.Equal(t, 503, ctErr.StatusCode)
+	assert.True(t, isRetryTimeout(err))
+	assert.False(t, isRetryTimeout(unavailable))
+}
+
+func TestHandleCommercetoolsErrorRetryAfter(t *testing.T) {
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		if len(requests) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "code-1", "code": "SUMMER"}`))
+	}))
+	defer server.Close()
+
+	client, err := platform.NewClient(&platform.ClientConfig{
+		URL:        server.URL,
+		HTTPClient: &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport}},
+	})
+	assert.NoError(t, err)
+
+	err = retryContext(context.Background(), nil, "read discount code", time.Minute, func() *resource.RetryError {
+		_, err := client.WithProjectKey("my-project").DiscountCodes().WithId("code-1").Get().Execute(context.Background())
+		if err != nil {
+			return handleCommercetoolsError(err)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, requests, 2) {
+		assert.GreaterOrEqual(t, requests[1].Sub(requests[0]), 2*time.Second)
+	}
+}
+
+func TestRetryContextRetryAfter(t *testing.T) {
+	rateLimited := &rateLimitError{retryAfter: time.Minute}
+
+	// The wait for the Retry-After ends when the timeout expires
+	start := time.Now()
+	err := retryContext(context.Background(), nil, "read test", 500*time.Millisecond, func() *resource.RetryError {
+		return handleCommercetoolsError(rateLimited)
+	})
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Second))
+	assert.True(t, isRetryTimeout(err))
+
+	// The wait for the Retry-After ends when the context is canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	err = retryContext(ctx, nil, "read test", time.Hour, func() *resource.RetryError {
+		return handleCommercetoolsError(rateLimited)
+	})
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Second))
+	assert.Error(t, err)
+
+	// The wait is taken from the retry budget
+	meta := &providerMeta{retryBudget: newRetryBudget(30 * time.Second)}
+	attempts := 0
+	start = time.Now()
+	err = retryContext(context.Background(), meta, "read test", time.Hour, func() *resource.RetryError {
+		attempts++
+		return handleCommercetoolsError(rateLimited)
+	})
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Second))
+	assert.Equal(t, 1, attempts)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "read test failed, the retry budget of 30s of the provider is exhausted")
+		assert.Contains(t, err.Error(), "rate limited by commercetools, retry after 1m0s")
+	}
+}
+
+func TestRetryContextBudget(t *testing.T) {
+	meta := &providerMeta{retryBudget: newRetryBudget(100 * time.Millisecond)}
+	unavailable := platform.ErrorResponse{StatusCode: 503, Message: "Service Unavailable"}
+
+	start := time.Now()
+	err := retryContext(context.Background(), meta, "create test", 30*time.Second, func() *resource.RetryError {
+		return handleCommercetoolsError(unavailable)
+	})
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Second))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "create test failed, the retry budget of 100ms of the provider is exhausted")
+	var ctErr platform.ErrorResponse
+	assert.True(t, errors.As(err, &ctErr))
+
+	// The budget is shared, so the next operation is not retried at all
+	attempts := 0
+	err = retryContext(context.Background(), meta, "update test", 30*time.Second, func() *resource.RetryError {
+		attempts++
+		return handleCommercetoolsError(unavailable)
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+
+	// Operations which succeed are not affected by an exhausted budget
+	err = retryContext(context.Background(), meta, "read test", 30*time.Second, func() *resource.RetryError {
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestValidateDuration(t *testing.T) {
+	_, errs := validateDuration("5m", "retry_budget")
+	assert.Empty(t, errs)
+	_, errs = validateDuration("", "retry_budget")
+	assert.Empty(t, errs)
+	_, errs = validateDuration("5 minutes", "retry_budget")
+	assert.NotEmpty(t, errs)
+	_, errs = validateDuration("-1s", "retry_budget")
+	assert.NotEmpty(t, errs)
+}
+
 func checkApiResult(err error) error {
 	switch v := err.(type) {
 	case platform.GenericRequestError:
@@ -40,3 +477,113 @@ func checkApiResult(err error) error {
 		return fmt.Errorf("unexpected result returned")
 	}
 }
+
+func TestUnmarshallLocalizedString(t *testing.T) {
+	// A map which is not written inline but passed from a variable, merged
+	// from several maps or decoded from JSON
+	names := map[string]interface{}{
+		"en":    "Shoe",
+		"de":    "Schuh",
+		"nl":    42.0,
+		"fr":    true,
+		"en-US": nil,
+	}
+
+	assert.Equal(t, platform.LocalizedString{
+		"en": "Shoe",
+		"de": "Schuh",
+		"nl": "42",
+		"fr": "true",
+	}, unmarshallLocalizedString(names))
+	assert.Equal(t, platform.LocalizedString{}, unmarshallLocalizedString(nil))
+}
+
+func TestUnmarshallLocalizedStringVariable(t *testing.T) {
+	names := map[string]string{"en": "Shoes", "de": "Schuhe"}
+	raw := make(map[string]interface{}, len(names))
+	for k, v := range names {
+		raw[k] = v
+	}
+	d := schema.TestResourceDataRaw(t, resourceCategory().Schema, map[string]interface{}{
+		"name": raw,
+		"slug": raw,
+	})
+
+	assert.Equal(t, platform.LocalizedString{"en": "Shoes", "de": "Schuhe"}, unmarshallLocalizedString(d.Get("name")))
+}
+
+func TestValidateLocalizedString(t *testing.T) {
+	path := cty.GetAttrPath("name")
+
+	assert.Empty(t, validateLocalizedString(map[string]interface{}{"en": "Shoe", "nl": 42, "de": false}, path))
+
+	diags := validateLocalizedString(map[string]interface{}{
+		"en":  "Shoe",
+		"de":  []interface{}{"Schuh"},
+		"nl":  map[string]interface{}{"value": "Schoen"},
+		"eng": "Shoe",
+	}, path)
+	if assert.Len(t, diags, 3) {
+		assert.Equal(t, "Invalid value for locale de", diags[1].Summary)
+		assert.Equal(t, "The value of locale de must be a string, got []interface {}", diags[1].Detail)
+		assert.Equal(t, path.IndexString("de"), diags[1].AttributePath)
+		assert.Equal(t, "Invalid value for locale nl", diags[2].Summary)
+	}
+}
+
+func TestResolveResourceIdentifier(t *testing.T) {
+	id, key := resolveResourceIdentifier("5d3e1a0c-7b2f-4e8b-9c51-2f0d3a6b8e11")
+	assert.Equal(t, stringRef("5d3e1a0c-7b2f-4e8b-9c51-2f0d3a6b8e11"), id)
+	assert.Nil(t, key)
+
+	id, key = resolveResourceIdentifier("summer-sale")
+	assert.Nil(t, id)
+	assert.Equal(t, stringRef("summer-sale"), key)
+}
+
+// testResourceDataChange returns the resource data for a change from the old
+// to the new configuration, so the update logic can be tested with HasChange
+func testResourceDataChange(t *testing.T, s map[string]*schema.Schema, old, new map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+
+	current := schema.TestResourceDataRaw(t, s, old)
+	current.SetId("00000000-0000-0000-0000-000000000000")
+	state := current.State()
+
+	sm := schema.InternalMap(s)
+	diff, err := sm.Diff(context.Background(), state, terraform.NewResourceConfigRaw(new), nil, nil, true)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d, err := sm.Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return d
+}
+
+func TestForEachConcurrently(t *testing.T) {
+	var inFlight, maxInFlight int32
+	ids := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	failures := forEachConcurrently(context.Background(), ids, 3, func(id string) error {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if id == "c" || id == "f" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+	assert.Len(t, failures, 2)
+	assert.Contains(t, failures, "c")
+	assert.Contains(t, failures, "f")
+}
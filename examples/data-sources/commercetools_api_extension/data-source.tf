@@ -0,0 +1,7 @@
+data "commercetools_api_extension" "tax_calculation" {
+  key = "tax-calculation"
+}
+
+output "tax_calculation_timeout" {
+  value = data.commercetools_api_extension.tax_calculation.timeout_in_ms
+}
@@ -0,0 +1,7 @@
+data "commercetools_cart_discount_stacking" "berlin" {
+  store = "berlin"
+}
+
+output "berlin_stacking_discounts" {
+  value = [for pair in data.commercetools_cart_discount_stacking.berlin.pairs : pair.cart_discounts if pair.effect == "stacks"]
+}
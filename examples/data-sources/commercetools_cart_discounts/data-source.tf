@@ -0,0 +1,23 @@
+data "commercetools_cart_discounts" "summer" {
+  group = "summer-campaign"
+}
+
+output "summer_cart_discount_ids" {
+  value = data.commercetools_cart_discounts.summer.cart_discounts[*].id
+}
+
+data "commercetools_cart_discounts" "running" {
+  active_now = true
+}
+
+output "running_cart_discounts" {
+  value = data.commercetools_cart_discounts.running.total
+}
+
+data "commercetools_cart_discounts" "gifts" {
+  value_type = "giftLineItem"
+}
+
+output "gift_product_ids" {
+  value = data.commercetools_cart_discounts.gifts.cart_discounts[*].value[0].product_id
+}
@@ -0,0 +1,23 @@
+data "commercetools_category" "shoes" {
+  slug   = "shoes"
+  locale = "en"
+}
+
+resource "commercetools_cart_discount" "shoes" {
+  name = {
+    en = "10% off shoes"
+  }
+  value {
+    type      = "relative"
+    permyriad = 1000
+  }
+  predicate = "1 = 1"
+  target {
+    type      = "lineItems"
+    predicate = "categories.id contains \"${data.commercetools_category.shoes.id}\""
+  }
+}
+
+output "shoes_breadcrumb" {
+  value = join(" > ", concat(data.commercetools_category.shoes.ancestors[*].name.en, ["Shoes"]))
+}
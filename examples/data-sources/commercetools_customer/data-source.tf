@@ -0,0 +1,8 @@
+data "commercetools_customer" "test_customer" {
+  email = "test-customer@example.com"
+  store = "nl"
+}
+
+output "test_customer_group" {
+  value = data.commercetools_customer.test_customer.customer_group_id
+}
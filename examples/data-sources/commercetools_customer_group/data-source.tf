@@ -0,0 +1,9 @@
+data "commercetools_customer_group" "vip" {
+  key = "vip"
+}
+
+resource "commercetools_discount_code" "vip" {
+  code           = "VIP"
+  predicate      = "customer.customerGroup.id = \"${data.commercetools_customer_group.vip.id}\""
+  cart_discounts = ["cart-discount-id"]
+}
@@ -0,0 +1,23 @@
+data "commercetools_discount_code" "spring" {
+  code = "SPRING"
+}
+
+# Copy the settings of the spring discount code to a new code
+resource "commercetools_discount_code" "summer" {
+  code                          = "SUMMER"
+  name                          = data.commercetools_discount_code.spring.name
+  description                   = data.commercetools_discount_code.spring.description
+  predicate                     = data.commercetools_discount_code.spring.predicate
+  cart_discounts                = data.commercetools_discount_code.spring.cart_discounts
+  groups                        = data.commercetools_discount_code.spring.groups
+  max_applications              = data.commercetools_discount_code.spring.max_applications
+  max_applications_per_customer = data.commercetools_discount_code.spring.max_applications_per_customer
+
+  dynamic "custom" {
+    for_each = data.commercetools_discount_code.spring.custom
+    content {
+      type_id = custom.value.type_id
+      fields  = custom.value.fields
+    }
+  }
+}
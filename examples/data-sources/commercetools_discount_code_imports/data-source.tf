@@ -0,0 +1,17 @@
+data "commercetools_discount_code_imports" "all" {}
+
+# Import the codes with terraform 1.5 or later
+resource "local_file" "discount_code_imports" {
+  filename = "${path.module}/discount_code_imports.tf"
+  content  = data.commercetools_discount_code_imports.all.import_blocks
+}
+
+# Or write a script for older versions of terraform
+data "commercetools_discount_code_imports" "summer" {
+  where = "groups contains \"summer\""
+}
+
+resource "local_file" "import_summer_codes" {
+  filename = "${path.module}/import_summer_codes.sh"
+  content  = join("\n", data.commercetools_discount_code_imports.summer.import_commands)
+}
@@ -0,0 +1,16 @@
+data "commercetools_discount_code_simulation" "summer" {
+  code           = "SUMMER21"
+  customer_group = "vip"
+  currency_code  = "EUR"
+
+  line_item {
+    sku          = "shoe-1"
+    category_ids = [commercetools_category.shoes.id]
+    quantity     = 2
+    cent_amount  = 2500
+  }
+}
+
+output "summer_applied_discounts" {
+  value = [for discount in data.commercetools_discount_code_simulation.summer.cart_discounts : discount.id if discount.applies]
+}
@@ -0,0 +1,28 @@
+data "commercetools_discount_codes" "summer" {
+  group = "summer-campaign"
+}
+
+output "inactive_summer_codes" {
+  value = [for code in data.commercetools_discount_codes.summer.discount_codes : code.code if !code.is_active]
+}
+
+data "commercetools_discount_codes" "summer_active" {
+  group       = "summer-campaign"
+  active_only = true
+}
+
+resource "local_file" "summer_codes" {
+  filename = "${path.module}/summer-codes.csv"
+  content  = data.commercetools_discount_codes.summer_active.csv
+}
+
+data "commercetools_discount_codes" "black_friday" {
+  name   = "black friday"
+  locale = "en"
+}
+
+# Fail the plan when two active codes of the group are valid at the same time
+data "commercetools_discount_codes" "newsletter" {
+  group           = "newsletter"
+  fail_on_overlap = true
+}
@@ -0,0 +1,21 @@
+data "commercetools_line_item_predicate" "shirts" {
+  skus             = ["shirt-s", "shirt-m", "shirt-l"]
+  product_type_ids = ["product-type-id"]
+}
+
+resource "commercetools_cart_discount" "shirts" {
+  name = {
+    en = "10% off shirts"
+  }
+  predicate = "1 = 1"
+
+  target {
+    type      = "lineItems"
+    predicate = data.commercetools_line_item_predicate.shirts.predicate
+  }
+
+  value {
+    type      = "relative"
+    permyriad = 1000
+  }
+}
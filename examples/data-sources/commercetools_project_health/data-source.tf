@@ -0,0 +1,5 @@
+data "commercetools_project_health" "check" {}
+
+output "scopes" {
+  value = data.commercetools_project_health.check.scopes
+}
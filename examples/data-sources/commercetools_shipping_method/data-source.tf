@@ -0,0 +1,20 @@
+data "commercetools_shipping_method" "express" {
+  key = "express"
+}
+
+resource "commercetools_cart_discount" "free_express" {
+  name = {
+    en = "Free express shipping"
+  }
+  sort_order = "0.5"
+  predicate  = "shippingInfo.shippingMethod.id = \"${data.commercetools_shipping_method.express.id}\""
+
+  target {
+    type = "shipping"
+  }
+
+  value {
+    type      = "relative"
+    permyriad = 10000
+  }
+}
@@ -0,0 +1,9 @@
+data "commercetools_store" "nl" {
+  key = "nl-webshop"
+}
+
+resource "commercetools_discount_code" "nl_summer" {
+  code           = "SUMMER-NL"
+  stores         = [data.commercetools_store.nl.key]
+  cart_discounts = [commercetools_cart_discount.summer.id]
+}
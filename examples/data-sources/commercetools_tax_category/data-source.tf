@@ -0,0 +1,9 @@
+data "commercetools_tax_category" "standard" {
+  key = "standard"
+}
+
+resource "commercetools_shipping_method" "express" {
+  name            = "Express"
+  key             = "express"
+  tax_category_id = data.commercetools_tax_category.standard.id
+}
@@ -0,0 +1,16 @@
+data "commercetools_type" "discount_code_fields" {
+  key = "discount-code-fields"
+}
+
+resource "commercetools_discount_code" "summer" {
+  code           = "SUMMER"
+  predicate      = "1=1"
+  cart_discounts = ["cart-discount-id-1"]
+
+  custom {
+    type_id = data.commercetools_type.discount_code_fields.id
+    fields = {
+      "campaign" = "summer"
+    }
+  }
+}
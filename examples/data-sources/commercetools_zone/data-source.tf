@@ -0,0 +1,13 @@
+data "commercetools_zone" "dach" {
+  key = "dach"
+}
+
+resource "commercetools_shipping_zone_rate" "standard_dach" {
+  shipping_method_id = commercetools_shipping_method.standard.id
+  shipping_zone_id   = data.commercetools_zone.dach.id
+
+  price {
+    cent_amount   = 500
+    currency_code = "EUR"
+  }
+}
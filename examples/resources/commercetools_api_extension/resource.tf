@@ -2,9 +2,12 @@ resource "commercetools_api_extension" "my-extension" {
   key = "test-case"
 
   destination {
-    type                 = "HTTP"
-    url                  = "https://example.com"
-    authorization_header = "Basic 12345"
+    type = "HTTP"
+    url  = "https://example.com"
+
+    authorization_header {
+      header_value = "Basic 12345"
+    }
   }
 
   trigger {
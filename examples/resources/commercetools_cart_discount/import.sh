@@ -0,0 +1,8 @@
+# Cart discounts can be imported using the ID
+terraform import commercetools_cart_discount.my_cart_discount 2845b936-e407-4f29-957b-f8deb0fcba97
+
+# or using the key, prefix it with key= when the key looks like an ID
+terraform import commercetools_cart_discount.my_cart_discount summer-sale
+
+# or using the sort order, which is unique within a project
+terraform import commercetools_cart_discount.my_cart_discount sortOrder=0.9
@@ -0,0 +1,7 @@
+resource "commercetools_cart_discount_activation" "summer_sale" {
+  cart_discount_ids = [
+    commercetools_cart_discount.summer_shirts.id,
+    commercetools_cart_discount.summer_shoes.id,
+  ]
+  is_active = var.summer_sale_active
+}
@@ -0,0 +1,2 @@
+# Custom objects can be imported using the container and the key
+terraform import commercetools_custom_object.my-value my-container/my-key
@@ -0,0 +1,6 @@
+# Discount codes can be imported using the ID or the code
+terraform import commercetools_discount_code.my_discount_code 2845b936-e407-4f29-957b-f8deb0fcba97
+terraform import commercetools_discount_code.my_discount_code SUMMER21
+
+# When a value is both the ID of a discount code and the code of another one, select how it is used
+terraform import commercetools_discount_code.my_discount_code code=SUMMER21
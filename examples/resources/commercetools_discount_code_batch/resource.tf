@@ -0,0 +1,17 @@
+resource "commercetools_discount_code_batch" "promo" {
+  code_prefix    = "PROMO-"
+  first_index    = 1
+  last_index     = 100
+  cart_discounts = ["cart-discount-id-1"]
+
+  name = {
+    en = "Promo code"
+  }
+  groups                        = ["promo-2021"]
+  max_applications_per_customer = 1
+  valid_until                   = "2021-12-31T23:59:59Z"
+}
+
+output "promo_codes" {
+  value = keys(commercetools_discount_code_batch.promo.codes)
+}
@@ -0,0 +1,2 @@
+# Memberships can be imported using the ID of the discount code and the group
+terraform import commercetools_discount_code_group_membership.summer_newsletter 2845b936-e407-4f29-957b-f8deb0fcba97/newsletter
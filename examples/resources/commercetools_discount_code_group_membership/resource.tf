@@ -0,0 +1,14 @@
+resource "commercetools_discount_code" "summer" {
+  code           = "SUMMER"
+  cart_discounts = ["cart-discount-id"]
+
+  # The groups are managed by the memberships
+  lifecycle {
+    ignore_changes = [groups]
+  }
+}
+
+resource "commercetools_discount_code_group_membership" "summer_newsletter" {
+  discount_code_id = commercetools_discount_code.summer.id
+  group            = "newsletter"
+}
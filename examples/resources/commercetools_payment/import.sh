@@ -0,0 +1,5 @@
+# Payments can be imported using the ID
+terraform import commercetools_payment.my_payment 2845b936-e407-4f29-957b-f8deb0fcba97
+
+# or using the key
+terraform import commercetools_payment.my_payment order-1234
@@ -0,0 +1,31 @@
+resource "commercetools_payment" "standard" {
+  key          = "order-1234"
+  interface_id = "psp-payment-id"
+
+  amount_planned {
+    currency_code = "EUR"
+    cent_amount   = 1500
+  }
+
+  payment_method_info {
+    payment_interface = "Stripe"
+    method            = "CreditCard"
+    name = {
+      en = "Credit card"
+    }
+  }
+
+  payment_status {
+    interface_code = "authorized"
+  }
+
+  transactions {
+    type = "Authorization"
+    amount {
+      currency_code = "EUR"
+      cent_amount   = 1500
+    }
+    interaction_id = "psp-authorization-id"
+    state          = "Success"
+  }
+}
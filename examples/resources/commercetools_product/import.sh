@@ -0,0 +1,2 @@
+# Products can be imported using the ID
+terraform import commercetools_product.my_product 2845b936-e407-4f29-957b-f8deb0fcba97
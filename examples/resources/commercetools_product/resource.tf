@@ -0,0 +1,35 @@
+resource "commercetools_product" "running_shoe" {
+  key             = "running-shoe"
+  product_type_id = commercetools_product_type.shoe.id
+  categories      = [commercetools_category.shoes.id]
+  publish         = true
+
+  name = {
+    en = "Running shoe"
+  }
+  slug = {
+    en = "running-shoe"
+  }
+
+  master_variant {
+    sku = "running-shoe-42"
+    attributes = {
+      size = "42"
+    }
+    price {
+      currency_code = "EUR"
+      cent_amount   = 8999
+    }
+  }
+
+  variants {
+    sku = "running-shoe-43"
+    attributes = {
+      size = "43"
+    }
+    price {
+      currency_code = "EUR"
+      cent_amount   = 8999
+    }
+  }
+}
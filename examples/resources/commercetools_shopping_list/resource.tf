@@ -0,0 +1,33 @@
+resource "commercetools_shopping_list" "wishlist" {
+  key = "my-wishlist"
+  name = {
+    en = "My wishlist"
+  }
+  description = {
+    en = "Products I would like to buy"
+  }
+  customer = "customer-id"
+
+  line_items {
+    sku      = "sku-1"
+    quantity = 2
+  }
+
+  line_items {
+    product_id = "product-id"
+    variant_id = 1
+  }
+
+  text_line_items {
+    name = {
+      en = "Gift wrapping"
+    }
+  }
+
+  custom {
+    type_id = "type-id"
+    fields = {
+      "my-field" = "value"
+    }
+  }
+}